@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+
+	"grpc-tls/tlsconfig"
+)
+
+type Config struct {
+	// Addr is where the gateway serves HTTP
+	Addr string
+	// TodoEndpoint and AuthEndpoint are the gRPC addresses of grpc-todo and grpc-auth
+	TodoEndpoint string
+	AuthEndpoint string
+	// TLS is used to dial both services, plaintext unless TLS_CA_FILE is set
+	TLS tlsconfig.Config
+	// TrustForwardedFor takes the client address from X-Forwarded-For, set it only behind a proxy
+	TrustForwardedFor bool
+}
+
+// LoadConfig reads the gateway settings from the environment, defaulting to the local services
+// over plaintext
+func LoadConfig() Config {
+	return Config{
+		Addr:              getenv("GATEWAY_ADDR", ":8080"),
+		TodoEndpoint:      getenv("TODO_GRPC_ADDR", "localhost:3000"),
+		AuthEndpoint:      getenv("AUTH_GRPC_ADDR", "localhost:9000"),
+		TLS:               tlsconfig.LoadConfig(),
+		TrustForwardedFor: os.Getenv("GATEWAY_TRUST_FORWARDED_FOR") == "true",
+	}
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+
+	return fallback
+}
@@ -0,0 +1,71 @@
+package gateway
+
+import (
+	"api-gateway/openapi"
+	"context"
+	"grpc-auth/auth"
+	"grpc-auth/authv2"
+	"grpc-auth/utils"
+	"grpc-todo/todov2"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// InitHandler translates REST calls into RPCs on the TodoService behind todoConn and the
+// AuthService behind authConn, and serves the OpenAPI document at /openapi.json.
+// The Authorization header of every request is forwarded as "authorization" metadata and the
+// address of the client as utils.ClientIPMetadata, read from X-Forwarded-For when trustForwardedFor is set.
+func InitHandler(ctx context.Context, todoConn, authConn *grpc.ClientConn, trustForwardedFor bool) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{OrigName: true, EmitDefaults: true}),
+		runtime.WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+			return metadata.Pairs(utils.ClientIPMetadata, clientIP(r, trustForwardedFor))
+		}),
+	)
+
+	if err := todov2.RegisterTodoServiceHandler(ctx, mux, todoConn); err != nil {
+		return nil, err
+	}
+	if err := auth.RegisterAuthServiceHandler(ctx, mux, authConn); err != nil {
+		return nil, err
+	}
+	if err := authv2.RegisterAuthServiceHandler(ctx, mux, authConn); err != nil {
+		return nil, err
+	}
+
+	document, err := openapi.Document()
+	if err != nil {
+		return nil, err
+	}
+
+	root := http.NewServeMux()
+	root.HandleFunc("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(document)
+	})
+	root.Handle("/", mux)
+
+	return root, nil
+}
+
+// clientIP returns the address of the HTTP client. Behind a proxy the last X-Forwarded-For entry is
+// the one the proxy added, earlier entries come from the client and can't be trusted.
+func clientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if last := strings.TrimSpace(hops[len(hops)-1]); net.ParseIP(last) != nil {
+			return last
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
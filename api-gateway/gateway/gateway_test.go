@@ -0,0 +1,263 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"grpc-auth/auth"
+	"grpc-auth/authv2"
+	"grpc-auth/utils"
+	"grpc-todo/todov2"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type fakeTodoServer struct {
+	todov2.UnimplementedTodoServiceServer
+	authorization string
+	updateMask    []string
+}
+
+func (s *fakeTodoServer) GetTodo(ctx context.Context, request *todov2.GetTodoRequest) (*todov2.Todo, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization = strings.Join(md.Get("authorization"), ",")
+	if request.Id != 7 {
+		return nil, status.Error(codes.NotFound, "todo not found")
+	}
+	return &todov2.Todo{Id: 7, Title: "write tests", UserId: 42}, nil
+}
+
+func (s *fakeTodoServer) UpdateTodo(ctx context.Context, request *todov2.UpdateTodoRequest) (*todov2.Todo, error) {
+	s.updateMask = request.UpdateMask.GetPaths()
+	return request.Todo, nil
+}
+
+type fakeAuthServer struct {
+	auth.UnimplementedAuthServiceServer
+	clientIP string
+}
+
+func (s *fakeAuthServer) Login(ctx context.Context, request *auth.LoginRequest) (*auth.LoginResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.clientIP = strings.Join(md.Get(utils.ClientIPMetadata), ",")
+	if request.Username != "alice" || request.Password != "secret" {
+		return nil, status.Error(codes.Unauthenticated, "wrong username or password")
+	}
+	return &auth.LoginResponse{Success: true, Message: "Login success", Token: "token-for-alice"}, nil
+}
+
+type fakeAuthV2Server struct {
+	authv2.UnimplementedAuthServiceServer
+	authorization string
+	revoked       string
+}
+
+func (s *fakeAuthV2Server) RevokeSession(ctx context.Context, request *authv2.RevokeSessionRequest) (*emptypb.Empty, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.authorization = strings.Join(md.Get("authorization"), ",")
+	if request.SessionId != "s1" {
+		return nil, status.Error(codes.NotFound, "session not found")
+	}
+	s.revoked = request.SessionId
+	return &emptypb.Empty{}, nil
+}
+
+func (s *fakeAuthV2Server) Register(ctx context.Context, request *authv2.RegisterRequest) (*authv2.User, error) {
+	if request.Username == "alice" {
+		return nil, status.Error(codes.AlreadyExists, "username is already taken")
+	}
+	return &authv2.User{Id: 2, Username: request.Username}, nil
+}
+
+// dialBufconn serves register on an in-memory listener and returns a connection to it
+func dialBufconn(t *testing.T, register func(*grpc.Server)) *grpc.ClientConn {
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func setup(t *testing.T) (*httptest.Server, *fakeTodoServer, *fakeAuthV2Server) {
+	todoServer := &fakeTodoServer{}
+	authV2Server := &fakeAuthV2Server{}
+	todoConn := dialBufconn(t, func(s *grpc.Server) { todov2.RegisterTodoServiceServer(s, todoServer) })
+	authConn := dialBufconn(t, func(s *grpc.Server) {
+		auth.RegisterAuthServiceServer(s, &fakeAuthServer{})
+		authv2.RegisterAuthServiceServer(s, authV2Server)
+	})
+
+	handler, err := InitHandler(context.Background(), todoConn, authConn, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, todoServer, authV2Server
+}
+
+func do(t *testing.T, method, url, body string, header http.Header) (int, map[string]interface{}) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	raw, _ := ioutil.ReadAll(res.Body)
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		t.Fatalf("%s %s: invalid JSON %q", method, url, raw)
+	}
+	return res.StatusCode, result
+}
+
+func TestLoginRoute(t *testing.T) {
+	server, _, _ := setup(t)
+
+	code, body := do(t, http.MethodPost, server.URL+"/v1/auth/login", `{"username":"alice","password":"secret"}`, nil)
+	if code != http.StatusOK || body["token"] != "token-for-alice" {
+		t.Fatalf("expected token, got %d %v", code, body)
+	}
+
+	code, _ = do(t, http.MethodPost, server.URL+"/v1/auth/login", `{"username":"alice","password":"wrong"}`, nil)
+	if code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", code)
+	}
+}
+
+func TestLoginRouteForwardsClientIP(t *testing.T) {
+	cases := []struct {
+		name              string
+		trustForwardedFor bool
+		want              string
+	}{
+		{name: "remote address", want: "127.0.0.1"},
+		{name: "last hop added by the proxy", trustForwardedFor: true, want: "198.51.100.7"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			authServer := &fakeAuthServer{}
+			authConn := dialBufconn(t, func(s *grpc.Server) { auth.RegisterAuthServiceServer(s, authServer) })
+			handler, err := InitHandler(context.Background(), authConn, authConn, c.trustForwardedFor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			// the first entry is whatever the client claims to be
+			header := http.Header{"X-Forwarded-For": {"203.0.113.1, 198.51.100.7"}}
+			do(t, http.MethodPost, server.URL+"/v1/auth/login", `{"username":"alice","password":"secret"}`, header)
+			if authServer.clientIP != c.want {
+				t.Fatalf("expected client IP %s, got %q", c.want, authServer.clientIP)
+			}
+		})
+	}
+}
+
+func TestRegisterV2Route(t *testing.T) {
+	server, _, _ := setup(t)
+
+	code, body := do(t, http.MethodPost, server.URL+"/v2/auth/register", `{"username":"bob","password":"secret"}`, nil)
+	if code != http.StatusOK || body["username"] != "bob" {
+		t.Fatalf("expected registered user, got %d %v", code, body)
+	}
+
+	code, _ = do(t, http.MethodPost, server.URL+"/v2/auth/register", `{"username":"alice","password":"secret"}`, nil)
+	if code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", code)
+	}
+}
+
+func TestRevokeSessionRoute(t *testing.T) {
+	server, _, authV2Server := setup(t)
+	header := http.Header{"Authorization": {"Bearer abc"}}
+
+	code, _ := do(t, http.MethodDelete, server.URL+"/v2/auth/sessions/s1", "", header)
+	if code != http.StatusOK || authV2Server.revoked != "s1" {
+		t.Fatalf("expected session s1 revoked, got %d %q", code, authV2Server.revoked)
+	}
+	if authV2Server.authorization != "Bearer abc" {
+		t.Fatalf("expected authorization metadata, got %q", authV2Server.authorization)
+	}
+
+	code, _ = do(t, http.MethodDelete, server.URL+"/v2/auth/sessions/s2", "", header)
+	if code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", code)
+	}
+}
+
+func TestTodoRoutesForwardAuthorization(t *testing.T) {
+	server, todoServer, _ := setup(t)
+	header := http.Header{"Authorization": {"Bearer abc"}}
+
+	code, body := do(t, http.MethodGet, server.URL+"/v2/todos/7", "", header)
+	if code != http.StatusOK || body["title"] != "write tests" || body["user_id"] != "42" {
+		t.Fatalf("expected todo 7, got %d %v", code, body)
+	}
+	if todoServer.authorization != "Bearer abc" {
+		t.Fatalf("expected authorization metadata, got %q", todoServer.authorization)
+	}
+
+	code, _ = do(t, http.MethodGet, server.URL+"/v2/todos/8", "", header)
+	if code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", code)
+	}
+}
+
+func TestPatchDerivesUpdateMask(t *testing.T) {
+	server, todoServer, _ := setup(t)
+
+	code, body := do(t, http.MethodPatch, server.URL+"/v2/todos/7", `{"title":"renamed"}`, nil)
+	if code != http.StatusOK || body["id"] != "7" || body["title"] != "renamed" {
+		t.Fatalf("expected updated todo, got %d %v", code, body)
+	}
+	if len(todoServer.updateMask) != 1 || todoServer.updateMask[0] != "title" {
+		t.Fatalf("expected update mask [title], got %v", todoServer.updateMask)
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	server, _, _ := setup(t)
+
+	code, body := do(t, http.MethodGet, server.URL+"/openapi.json", "", nil)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	paths, _ := body["paths"].(map[string]interface{})
+	for _, path := range []string{"/v2/todos", "/v2/todos/{id}", "/v1/auth/login", "/v2/auth/login", "/v2/auth/sessions"} {
+		if _, ok := paths[path]; !ok {
+			t.Fatalf("expected path %s in document", path)
+		}
+	}
+}
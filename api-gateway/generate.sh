@@ -0,0 +1,14 @@
+go mod download github.com/grpc-ecosystem/grpc-gateway@v1.16.0
+GOOGLEAPIS=$(go env GOMODCACHE)/github.com/grpc-ecosystem/grpc-gateway@v1.16.0/third_party/googleapis
+protoc -I ../grpc-todo -I $GOOGLEAPIS --swagger_out=openapi todo_v2.proto
+protoc -I ../grpc-auth -I $GOOGLEAPIS --swagger_out=openapi auth.proto
+protoc -I ../grpc-auth -I $GOOGLEAPIS --swagger_out=openapi auth_v2.proto
+{
+	echo '// Code generated by generate.sh. DO NOT EDIT.'
+	echo
+	echo 'package openapi'
+	echo
+	printf 'const todoDocument = `%s`\n\n' "$(cat openapi/todo_v2.swagger.json)"
+	printf 'const authDocument = `%s`\n\n' "$(cat openapi/auth.swagger.json)"
+	printf 'const authV2Document = `%s`\n' "$(cat openapi/auth_v2.swagger.json)"
+} > openapi/documents.go
@@ -0,0 +1,23 @@
+module api-gateway
+
+go 1.15
+
+require (
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0
+	google.golang.org/grpc v1.35.0
+	google.golang.org/protobuf v1.26.0
+	grpc-auth v0.0.0-00010101000000-000000000000
+	grpc-tls v0.0.0-00010101000000-000000000000
+	grpc-todo v0.0.0-00010101000000-000000000000
+)
+
+replace (
+	grpc-auth => ../grpc-auth
+	grpc-todo => ../grpc-todo
+)
+
+replace grpc-observability => ../grpc-observability
+
+replace grpc-tls => ../grpc-tls
+
+replace ratelimit => ../ratelimit
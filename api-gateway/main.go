@@ -0,0 +1,46 @@
+package main
+
+import (
+	"api-gateway/config"
+	"api-gateway/gateway"
+	"context"
+	"fmt"
+	"grpc-tls/tlsconfig"
+	"log"
+	"net/http"
+
+	"google.golang.org/grpc"
+)
+
+func main() {
+	cfg := config.LoadConfig()
+	ctx := context.Background()
+
+	credentials, stopTLS, err := tlsconfig.DialOption(cfg.TLS)
+	if err != nil {
+		log.Fatalf("failed to load TLS certificates: %v", err)
+	}
+	defer stopTLS()
+
+	todoConn, err := grpc.DialContext(ctx, cfg.TodoEndpoint, credentials)
+	if err != nil {
+		log.Fatalf("failed to dial grpc-todo: %v", err)
+	}
+	defer todoConn.Close()
+
+	authConn, err := grpc.DialContext(ctx, cfg.AuthEndpoint, credentials)
+	if err != nil {
+		log.Fatalf("failed to dial grpc-auth: %v", err)
+	}
+	defer authConn.Close()
+
+	handler, err := gateway.InitHandler(ctx, todoConn, authConn, cfg.TrustForwardedFor)
+	if err != nil {
+		log.Fatalf("failed to set up gateway: %v", err)
+	}
+
+	fmt.Println("Listen to", cfg.Addr)
+	if err := http.ListenAndServe(cfg.Addr, handler); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
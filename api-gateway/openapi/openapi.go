@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Document merges the Swagger documents generated from todo_v2.proto, auth.proto and auth_v2.proto
+// into one describing every route of the gateway
+func Document() ([]byte, error) {
+	merged := map[string]interface{}{
+		"swagger": "2.0",
+		"info": map[string]interface{}{
+			"title":   "Todo and Auth API",
+			"version": "2.0",
+		},
+		"consumes": []string{"application/json"},
+		"produces": []string{"application/json"},
+		"securityDefinitions": map[string]interface{}{
+			"bearer": map[string]interface{}{
+				"type":        "apiKey",
+				"name":        "Authorization",
+				"in":          "header",
+				"description": "Bearer token returned by /v1/auth/login or /v2/auth/login",
+			},
+		},
+	}
+	paths := map[string]interface{}{}
+	definitions := map[string]interface{}{}
+
+	sources := []struct {
+		raw     string
+		secured func(path string) bool
+	}{
+		{raw: todoDocument, secured: func(string) bool { return true }},
+		{raw: authDocument, secured: func(string) bool { return false }},
+		{raw: authV2Document, secured: isAccountPath},
+	}
+	for _, source := range sources {
+		var doc struct {
+			Paths       map[string]map[string]map[string]interface{} `json:"paths"`
+			Definitions map[string]interface{}                       `json:"definitions"`
+		}
+		if err := json.Unmarshal([]byte(source.raw), &doc); err != nil {
+			return nil, err
+		}
+
+		for path, operations := range doc.Paths {
+			if _, ok := paths[path]; ok {
+				return nil, fmt.Errorf("path %s is defined twice", path)
+			}
+			if source.secured(path) {
+				for _, operation := range operations {
+					operation["security"] = []map[string][]string{{"bearer": {}}}
+				}
+			}
+			paths[path] = operations
+		}
+		// The documents share the runtime and protobuf definitions, which are identical.
+		for name, definition := range doc.Definitions {
+			if existing, ok := definitions[name]; ok && !reflect.DeepEqual(existing, definition) {
+				return nil, fmt.Errorf("definition %s differs between documents", name)
+			}
+			definitions[name] = definition
+		}
+	}
+
+	merged["paths"] = paths
+	merged["definitions"] = definitions
+
+	return json.MarshalIndent(merged, "", "  ")
+}
+
+// isAccountPath reports the auth v2 routes acting on the user of the bearer token
+func isAccountPath(path string) bool {
+	for _, prefix := range []string{"/v2/auth/password", "/v2/auth/account", "/v2/auth/sessions"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,7 +1,10 @@
 package auth
 
 import (
+	"encoding/json"
+	"grpc-auth/grpcerr"
 	"grpc-auth/usecase"
+	"grpc-auth/utils"
 	"log"
 
 	"golang.org/x/net/context"
@@ -21,25 +24,30 @@ func (s *Server) Register(ctx context.Context, request *RegisterRequest) (*Regis
 	_, err := s.userUsecase.Register(request.Username, request.Password)
 	if err != nil {
 		log.Println("Error to register user", err)
-		return &RegisterResponse{Success: false, Message: "Failed to register"}, err
+		return nil, grpcerr.Status(err)
 	}
 	return &RegisterResponse{Success: true, Message: "Succeed to register"}, nil
 }
 
 func (s *Server) Login(ctx context.Context, request *LoginRequest) (*LoginResponse, error) {
-	token, err := s.userUsecase.Login(request.Username, request.Password)
+	token, err := s.userUsecase.Login(request.Username, request.Password, utils.ClientIP(ctx))
 	if err != nil {
-		log.Println("Error to register user", err)
-		return &LoginResponse{Success: false, Message: "Failed to login", Token: ""}, err
+		log.Println("Error to login", err)
+		return nil, grpcerr.Status(err)
 	}
 	return &LoginResponse{Success: true, Message: "Login success", Token: token}, nil
 }
 
 func (s *Server) ValidateToken(ctx context.Context, request *ValidateTokenRequest) (*ValidateTokenResponse, error) {
-	result, err := s.userUsecase.ValidateToken(request.Token)
+	user, err := s.userUsecase.ValidateToken(request.Token)
+	if err != nil {
+		log.Println("Error to validate token", err)
+		return nil, grpcerr.Status(err)
+	}
+	result, err := json.Marshal(&user)
 	if err != nil {
 		log.Println("Error to validate token", err)
-		return &ValidateTokenResponse{Success: false, Message: "Invalid token"}, err
+		return nil, grpcerr.Status(err)
 	}
-	return &ValidateTokenResponse{Success: true, Message: result}, nil
+	return &ValidateTokenResponse{Success: true, Message: string(result)}, nil
 }
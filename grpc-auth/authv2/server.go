@@ -0,0 +1,108 @@
+package authv2
+
+import (
+	"context"
+	"grpc-auth/grpcerr"
+	"grpc-auth/models"
+	"grpc-auth/usecase"
+	"grpc-auth/utils"
+	"log"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Server struct {
+	userUsecase usecase.UserUsecase
+}
+
+func InitServer(userUsecase usecase.UserUsecase) Server {
+	return Server{
+		userUsecase,
+	}
+}
+
+func (s *Server) Register(ctx context.Context, request *RegisterRequest) (*User, error) {
+	user, err := s.userUsecase.Register(request.Username, request.Password)
+	if err != nil {
+		log.Println("Error to register user", err)
+		return nil, grpcerr.Status(err)
+	}
+	return toProto(user), nil
+}
+
+func (s *Server) Login(ctx context.Context, request *LoginRequest) (*LoginResponse, error) {
+	token, err := s.userUsecase.Login(request.Username, request.Password, utils.ClientIP(ctx))
+	if err != nil {
+		log.Println("Error to login", err)
+		return nil, grpcerr.Status(err)
+	}
+	return &LoginResponse{Token: token}, nil
+}
+
+func (s *Server) ValidateToken(ctx context.Context, request *ValidateTokenRequest) (*User, error) {
+	user, err := s.userUsecase.ValidateToken(request.Token)
+	if err != nil {
+		log.Println("Error to validate token", err)
+		return nil, grpcerr.Status(err)
+	}
+	return toProto(user), nil
+}
+
+func (s *Server) ChangePassword(ctx context.Context, request *ChangePasswordRequest) (*emptypb.Empty, error) {
+	err := s.userUsecase.ChangePassword(utils.BearerToken(ctx), request.CurrentPassword, request.NewPassword, utils.ClientIP(ctx))
+	if err != nil {
+		log.Println("Error to change password", err)
+		return nil, grpcerr.Status(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) DeleteAccount(ctx context.Context, request *DeleteAccountRequest) (*emptypb.Empty, error) {
+	err := s.userUsecase.DeleteAccount(utils.BearerToken(ctx), request.Password, utils.ClientIP(ctx))
+	if err != nil {
+		log.Println("Error to delete account", err)
+		return nil, grpcerr.Status(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Server) ListSessions(ctx context.Context, request *ListSessionsRequest) (*ListSessionsResponse, error) {
+	sessions, currentID, err := s.userUsecase.ListSessions(utils.BearerToken(ctx))
+	if err != nil {
+		log.Println("Error to list sessions", err)
+		return nil, grpcerr.Status(err)
+	}
+
+	response := &ListSessionsResponse{}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, toSessionProto(session, currentID))
+	}
+	return response, nil
+}
+
+func (s *Server) RevokeSession(ctx context.Context, request *RevokeSessionRequest) (*emptypb.Empty, error) {
+	err := s.userUsecase.RevokeSession(utils.BearerToken(ctx), request.SessionId)
+	if err != nil {
+		log.Println("Error to revoke session", err)
+		return nil, grpcerr.Status(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func toProto(user models.User) *User {
+	return &User{
+		Id:       user.ID,
+		Username: user.Username,
+	}
+}
+
+func toSessionProto(session models.Session, currentID string) *Session {
+	return &Session{
+		Id:        session.ID,
+		ClientIp:  session.ClientIP,
+		CreatedAt: timestamppb.New(session.CreatedAt),
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+		Current:   session.ID == currentID,
+	}
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"log"
+	"os"
+)
+
+// devSecretKey is the well-known signing key allowed in development only
+const devSecretKey = "ThisIsASecretKey"
+
+// AuthSecretKey returns the key tokens are signed with, other services validate them through ValidateToken.
+// It panics when AUTH_SECRET_KEY is unset unless AUTH_DEV_MODE=true allows the development key.
+func AuthSecretKey() []byte {
+	if key := os.Getenv("AUTH_SECRET_KEY"); key != "" {
+		return []byte(key)
+	}
+	if os.Getenv("AUTH_DEV_MODE") == "true" {
+		log.Println("AUTH_SECRET_KEY not set, using the development key")
+		return []byte(devSecretKey)
+	}
+
+	panic("AUTH_SECRET_KEY must be set, or AUTH_DEV_MODE=true for local development")
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// TrustedGateways reads the comma separated TRUSTED_GATEWAYS, the client certificate names or
+// IP addresses of the API gateways whose forwarded client address is used for rate limiting and sessions
+func TrustedGateways() []string {
+	var gateways []string
+	for _, gateway := range strings.Split(os.Getenv("TRUSTED_GATEWAYS"), ",") {
+		if gateway = strings.TrimSpace(gateway); gateway != "" {
+			gateways = append(gateways, gateway)
+		}
+	}
+
+	return gateways
+}
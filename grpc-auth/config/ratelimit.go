@@ -0,0 +1,21 @@
+package config
+
+import (
+	"fmt"
+	"os"
+
+	"ratelimit"
+)
+
+// InitLoginLimiter to throttle login attempts, backed by Redis when RATE_LIMIT_BACKEND=redis
+func InitLoginLimiter() *ratelimit.Limiter {
+	var backend ratelimit.Backend
+	if os.Getenv("RATE_LIMIT_BACKEND") == "redis" {
+		backend = ratelimit.NewRedisBackend(ConnectRedis())
+		fmt.Println("Using Redis rate limiter")
+	} else {
+		backend = ratelimit.NewMemoryBackend()
+	}
+
+	return ratelimit.NewLimiter(backend, ratelimit.DefaultConfig(), nil)
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ConnectRedis to the Redis at REDIS_ADDR shared by every grpc-auth instance
+func ConnectRedis() *redis.Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     os.Getenv("REDIS_ADDR"),
+		Password: "", // no password set
+		DB:       0,  // use default DB
+	})
+
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		panic(err)
+	}
+
+	return rdb
+}
@@ -1 +1,6 @@
-protoc --go_out=plugins=grpc:auth auth.proto
\ No newline at end of file
+go mod download github.com/grpc-ecosystem/grpc-gateway@v1.16.0
+GOOGLEAPIS=$(go env GOMODCACHE)/github.com/grpc-ecosystem/grpc-gateway@v1.16.0/third_party/googleapis
+protoc -I . -I $GOOGLEAPIS --go_out=plugins=grpc,paths=source_relative:auth auth.proto
+protoc -I . -I $GOOGLEAPIS --grpc-gateway_out=paths=source_relative:auth auth.proto
+protoc -I . -I $GOOGLEAPIS --go_out=plugins=grpc,paths=source_relative:authv2 auth_v2.proto
+protoc -I . -I $GOOGLEAPIS --grpc-gateway_out=paths=source_relative:authv2 auth_v2.proto
@@ -4,11 +4,24 @@ go 1.13
 
 require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
-	github.com/golang/protobuf v1.4.2
+	github.com/go-redis/redis/v8 v8.11.4
+	github.com/golang/protobuf v1.5.2
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0
 	github.com/jmoiron/sqlx v1.3.1
 	github.com/lib/pq v1.9.0
+	github.com/prometheus/client_golang v1.9.0
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
-	golang.org/x/net v0.0.0-20190404232315-eb5bcb51f2a3
+	golang.org/x/net v0.0.0-20210428140749-89ef3d95e781
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
 	google.golang.org/grpc v1.35.0
-	google.golang.org/protobuf v1.25.0
+	google.golang.org/protobuf v1.26.0
+	grpc-observability v0.0.0-00010101000000-000000000000
+	grpc-tls v0.0.0-00010101000000-000000000000
+	ratelimit v0.0.0-00010101000000-000000000000
 )
+
+replace grpc-observability => ../grpc-observability
+
+replace grpc-tls => ../grpc-tls
+
+replace ratelimit => ../ratelimit
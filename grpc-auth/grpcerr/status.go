@@ -0,0 +1,74 @@
+package grpcerr
+
+import (
+	"errors"
+	"grpc-auth/repository"
+	"grpc-auth/usecase"
+	"log"
+	"ratelimit"
+
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Status converts an error of the use case or repository layer into a gRPC status error.
+// Unknown errors become Internal without exposing their message to the client.
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var validationErr *usecase.ValidationError
+	var limitedErr *ratelimit.LimitedError
+	switch {
+	case errors.As(err, &validationErr):
+		return BadRequest(validationErr)
+	case errors.As(err, &limitedErr):
+		return ResourceExhausted(limitedErr)
+	case errors.Is(err, repository.ErrUsernameTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, repository.ErrSessionNotFound), errors.Is(err, repository.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, usecase.ErrInvalidCredentials), errors.Is(err, usecase.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		log.Println("Internal error", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// BadRequest returns an InvalidArgument status carrying every field violation
+func BadRequest(validationErr *usecase.ValidationError) error {
+	st := status.New(codes.InvalidArgument, validationErr.Error())
+
+	details := &errdetails.BadRequest{}
+	for _, violation := range validationErr.Violations {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+
+	detailed, err := st.WithDetails(details)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// ResourceExhausted tells the client when it may retry a rate limited call
+func ResourceExhausted(limitedErr *ratelimit.LimitedError) error {
+	st := status.New(codes.ResourceExhausted, limitedErr.Error())
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: ptypes.DurationProto(limitedErr.RetryAfter),
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,34 @@
+package grpcerr
+
+import (
+	"errors"
+	"grpc-auth/repository"
+	"grpc-auth/usecase"
+	"ratelimit"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatus(t *testing.T) {
+	cases := []struct {
+		err  error
+		want codes.Code
+	}{
+		{err: repository.ErrUsernameTaken, want: codes.AlreadyExists},
+		{err: repository.ErrSessionNotFound, want: codes.NotFound},
+		{err: usecase.ErrInvalidCredentials, want: codes.Unauthenticated},
+		{err: usecase.ErrInvalidToken, want: codes.Unauthenticated},
+		{err: &usecase.ValidationError{Violations: []usecase.FieldViolation{{Field: "username", Description: "must not be empty"}}}, want: codes.InvalidArgument},
+		{err: &ratelimit.LimitedError{Reason: "Too many login attempts", RetryAfter: time.Second}, want: codes.ResourceExhausted},
+		{err: errors.New("pq: connection refused"), want: codes.Internal},
+	}
+
+	for _, c := range cases {
+		if got := status.Code(Status(c.err)); got != c.want {
+			t.Fatalf("Status(%v): expected %v, got %v", c.err, c.want, got)
+		}
+	}
+}
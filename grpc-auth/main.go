@@ -6,31 +6,50 @@ import (
 	"net"
 
 	"grpc-auth/auth"
+	"grpc-auth/authv2"
 	"grpc-auth/config"
 	"grpc-auth/repository"
 	"grpc-auth/usecase"
+	"grpc-auth/utils"
+	"grpc-observability/interceptor"
+	"grpc-tls/tlsconfig"
 
 	"google.golang.org/grpc"
 )
 
 func main() {
+	utils.SecretKey = config.AuthSecretKey()
+	utils.TrustedGateways = config.TrustedGateways()
 	db := config.ConnectDB()
 	userRepository := repository.InitUserRepository(db)
-	userUsecase := usecase.InitUserUsecase(userRepository)
+	sessionRepository := repository.InitSessionRepository(db)
+	loginLimiter := config.InitLoginLimiter()
+	userUsecase := usecase.InitUserUsecase(userRepository, sessionRepository, loginLimiter)
 
 	s := auth.InitServer(userUsecase)
+	s2 := authv2.InitServer(userUsecase)
 
-	grpcServer := grpc.NewServer()
+	observer := config.InitObserver()
+	interceptor.ServeMetrics(config.MetricsAddr())
+
+	tlsOptions, stopTLS, err := tlsconfig.ServerOptions(tlsconfig.LoadConfig())
+	if err != nil {
+		log.Fatalf("failed to load TLS certificates: %v", err)
+	}
+	defer stopTLS()
+
+	grpcServer := grpc.NewServer(append(observer.ServerOptions(), tlsOptions...)...)
 
 	auth.RegisterAuthServiceServer(grpcServer, &s)
+	authv2.RegisterAuthServiceServer(grpcServer, &s2)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", 9000))
 	if err != nil {
-		log.Println("failed to listen: %v", err)
+		log.Fatalf("failed to listen: %v", err)
 	}
 	fmt.Println("Listen to port 9000")
 
 	if err := grpcServer.Serve(lis); err != nil {
-		log.Println("failed to serve: %s", err)
+		log.Fatalf("failed to serve: %s", err)
 	}
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Session is one login of a user, its ID is the jti claim of the token issued for it
+type Session struct {
+	ID        string
+	UserID    int64
+	ClientIP  string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"grpc-auth/models"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrSessionNotFound is returned for an unknown, revoked or expired session, or one of another user
+var ErrSessionNotFound = errors.New("session not found")
+
+type sessionRepository struct {
+	db *sqlx.DB
+}
+
+type SessionRepository interface {
+	CreateSession(session models.Session) error
+	GetActiveSession(id string) (models.Session, error)
+	ListActiveSessions(userID int64) ([]models.Session, error)
+	RevokeSession(userID int64, id string) error
+	RevokeOtherSessions(userID int64, keepID string) error
+}
+
+func InitSessionRepository(db *sqlx.DB) SessionRepository {
+	return &sessionRepository{
+		db,
+	}
+}
+
+func (sessionRepository *sessionRepository) CreateSession(session models.Session) error {
+	_, err := sessionRepository.db.Exec(`
+	INSERT INTO sessions (
+		id,
+		user_id,
+		client_ip,
+		created_at,
+		expires_at
+	)
+	VALUES(
+		$1,
+		$2,
+		$3,
+		$4,
+		$5
+	);
+	`,
+		session.ID,
+		session.UserID,
+		session.ClientIP,
+		session.CreatedAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		log.Println("Error create session: ", err)
+	}
+
+	return err
+}
+
+func (sessionRepository *sessionRepository) GetActiveSession(id string) (models.Session, error) {
+	var session models.Session
+
+	err := sessionRepository.db.QueryRow(`
+		SELECT id, user_id, client_ip, created_at, expires_at FROM sessions
+		WHERE id=$1 AND revoked_at IS NULL AND expires_at > now();
+	`, id).Scan(&session.ID, &session.UserID, &session.ClientIP, &session.CreatedAt, &session.ExpiresAt)
+
+	if err == sql.ErrNoRows {
+		return models.Session{}, ErrSessionNotFound
+	}
+	if err != nil {
+		log.Println("Error to get session", err)
+		return models.Session{}, err
+	}
+
+	return session, nil
+}
+
+func (sessionRepository *sessionRepository) ListActiveSessions(userID int64) ([]models.Session, error) {
+	rows, err := sessionRepository.db.Query(`
+		SELECT id, user_id, client_ip, created_at, expires_at FROM sessions
+		WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > now()
+		ORDER BY created_at DESC;
+	`, userID)
+	if err != nil {
+		log.Println("Error to list sessions", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []models.Session{}
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.UserID, &session.ClientIP, &session.CreatedAt, &session.ExpiresAt); err != nil {
+			log.Println("Error to list sessions", err)
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (sessionRepository *sessionRepository) RevokeSession(userID int64, id string) error {
+	result, err := sessionRepository.db.Exec(`
+		UPDATE sessions SET revoked_at = now()
+		WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL AND expires_at > now();
+	`, id, userID)
+	if err != nil {
+		log.Println("Error to revoke session", err)
+		return err
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if revoked == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (sessionRepository *sessionRepository) RevokeOtherSessions(userID int64, keepID string) error {
+	_, err := sessionRepository.db.Exec(`
+		UPDATE sessions SET revoked_at = now()
+		WHERE user_id=$1 AND id<>$2 AND revoked_at IS NULL;
+	`, userID, keepID)
+	if err != nil {
+		log.Println("Error to revoke sessions", err)
+	}
+
+	return err
+}
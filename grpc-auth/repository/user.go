@@ -2,19 +2,34 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
 	"grpc-auth/models"
 	"log"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+var (
+	// ErrUsernameTaken is returned when another user already registered the username
+	ErrUsernameTaken = errors.New("username is already taken")
+	// ErrUserNotFound is returned when no user has the given username or id
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// uniqueViolation is the Postgres error code of a unique constraint violation
+const uniqueViolation = "23505"
+
 type userRepository struct {
 	db *sqlx.DB
 }
 
 type UserRepository interface {
-	CreateUser(username string, password string) (bool, error)
+	CreateUser(username string, password string) (models.User, error)
 	GetUserByUsername(username string) (models.User, error)
+	GetUserByID(id int64) (models.User, error)
+	UpdatePassword(id int64, password string) error
+	DeleteUser(id int64) error
 }
 
 func InitUserRepository(db *sqlx.DB) UserRepository {
@@ -23,34 +38,38 @@ func InitUserRepository(db *sqlx.DB) UserRepository {
 	}
 }
 
-func (userRepository *userRepository) CreateUser(username string, password string) (bool, error) {
+func (userRepository *userRepository) CreateUser(username string, password string) (models.User, error) {
 	var err error
-	var result bool
+	var user models.User
 
 	tx, errTx := userRepository.db.Begin()
 	if errTx != nil {
 		log.Println("Error create user: ", errTx)
-	} else {
-		err = insertUser(tx, username, password)
-		if err != nil {
-			log.Println("Error create user: ", err)
-		}
+		return models.User{}, errTx
 	}
 
+	user, err = insertUser(tx, username, password)
 	if err == nil {
-		result = true
-		tx.Commit()
+		err = tx.Commit()
 	} else {
-		result = false
 		tx.Rollback()
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return models.User{}, ErrUsernameTaken
+	}
+	if err != nil {
 		log.Println("Error create user: ", err)
+		return models.User{}, err
 	}
 
-	return result, err
+	return user, nil
 }
 
-func insertUser(tx *sql.Tx, username string, password string) error {
-	_, err := tx.Exec(`
+func insertUser(tx *sql.Tx, username string, password string) (models.User, error) {
+	user := models.User{Username: username}
+	err := tx.QueryRow(`
 	INSERT INTO users (
 		username,
 		password
@@ -58,13 +77,14 @@ func insertUser(tx *sql.Tx, username string, password string) error {
 	VALUES(
 		$1,
 		$2
-	);
+	)
+	RETURNING id;
 	`,
 		username,
 		password,
-	)
+	).Scan(&user.ID)
 
-	return err
+	return user, err
 }
 
 func (userRepository *userRepository) GetUserByUsername(username string) (models.User, error) {
@@ -77,9 +97,68 @@ func (userRepository *userRepository) GetUserByUsername(username string) (models
 
 	user.ID = id
 
+	if err == sql.ErrNoRows {
+		return models.User{}, ErrUserNotFound
+	}
 	if err != nil {
 		log.Println("Error to get user by username", err)
+		return models.User{}, err
 	}
 
-	return user, err
+	return user, nil
+}
+
+func (userRepository *userRepository) GetUserByID(id int64) (models.User, error) {
+	var user models.User
+
+	err := userRepository.db.QueryRow(`
+		SELECT id, username, password FROM users WHERE id=$1;
+	`, id).Scan(&(user.ID), &(user.Username), &(user.Password))
+
+	if err == sql.ErrNoRows {
+		return models.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		log.Println("Error to get user by id", err)
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func (userRepository *userRepository) UpdatePassword(id int64, password string) error {
+	result, err := userRepository.db.Exec(`
+		UPDATE users SET password=$2 WHERE id=$1;
+	`, id, password)
+	if err != nil {
+		log.Println("Error to update password", err)
+		return err
+	}
+
+	return userAffected(result)
+}
+
+// DeleteUser removes the user, its sessions go with it through the foreign key
+func (userRepository *userRepository) DeleteUser(id int64) error {
+	result, err := userRepository.db.Exec(`
+		DELETE FROM users WHERE id=$1;
+	`, id)
+	if err != nil {
+		log.Println("Error to delete user", err)
+		return err
+	}
+
+	return userAffected(result)
+}
+
+func userAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
 }
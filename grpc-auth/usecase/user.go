@@ -1,76 +1,262 @@
 package usecase
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
 	"grpc-auth/models"
 	"grpc-auth/repository"
 	"grpc-auth/utils"
 	"log"
+	"ratelimit"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrInvalidCredentials is returned for an unknown username or a wrong password alike
+	ErrInvalidCredentials = errors.New("wrong username or password")
+	// ErrInvalidToken is returned for tokens that are malformed, expired, not signed by us
+	// or whose session was revoked
+	ErrInvalidToken = errors.New("invalid token")
 )
 
 type userUsecase struct {
-	userRepository repository.UserRepository
+	userRepository    repository.UserRepository
+	sessionRepository repository.SessionRepository
+	loginLimiter      *ratelimit.Limiter
 }
 
 type UserUsecase interface {
-	Register(username, password string) (bool, error)
-	Login(username, password string) (string, error)
-	ValidateToken(token string) (string, error)
+	Register(username, password string) (models.User, error)
+	Login(username, password, clientIP string) (string, error)
+	ValidateToken(token string) (models.User, error)
+	ChangePassword(token, currentPassword, newPassword, clientIP string) error
+	DeleteAccount(token, password, clientIP string) error
+	ListSessions(token string) ([]models.Session, string, error)
+	RevokeSession(token, sessionID string) error
 }
 
-func InitUserUsecase(userRepository repository.UserRepository) UserUsecase {
+func InitUserUsecase(userRepository repository.UserRepository, sessionRepository repository.SessionRepository, loginLimiter *ratelimit.Limiter) UserUsecase {
 	return &userUsecase{
 		userRepository,
+		sessionRepository,
+		loginLimiter,
 	}
 }
 
-func (userUsecase *userUsecase) Register(username, password string) (bool, error) {
+func (userUsecase *userUsecase) Register(username, password string) (models.User, error) {
+	if err := validateCredentials(username, password); err != nil {
+		return models.User{}, err
+	}
+
+	// Checked before hashing so a taken name fails fast, the unique constraint still
+	// catches concurrent registrations.
+	_, err := userUsecase.userRepository.GetUserByUsername(username)
+	if err == nil {
+		return models.User{}, repository.ErrUsernameTaken
+	}
+	if err != repository.ErrUserNotFound {
+		log.Println("Error to register user: ", err)
+		return models.User{}, err
+	}
+
 	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
 		log.Println("Error to register user: ", err)
-		return false, err
+		return models.User{}, err
 	}
 
-	_, err = userUsecase.userRepository.CreateUser(username, hashedPassword)
+	user, err := userUsecase.userRepository.CreateUser(username, hashedPassword)
 	if err != nil {
 		log.Println("Error to register user: ", err)
-		return false, err
+		return models.User{}, err
 	}
 
-	return true, nil
+	return user, nil
 }
 
-func (userUsecase *userUsecase) Login(username, password string) (string, error) {
+func (userUsecase *userUsecase) Login(username, password, clientIP string) (string, error) {
+	ctx := context.Background()
+	if err := userUsecase.loginLimiter.Allow(ctx, clientIP, username); err != nil {
+		log.Println("Login rejected", err)
+		return "", err
+	}
+
 	user, err := userUsecase.userRepository.GetUserByUsername(username)
-	if err != nil {
+	if err != nil && err != repository.ErrUserNotFound {
 		log.Println("Error to get user by username", err)
+		return "", err
+	}
+	// An unknown username counts as a failed attempt so it can't be told apart from a wrong password.
+	if err == repository.ErrUserNotFound || !utils.CheckPasswordHash(password, user.Password) {
+		log.Println("Wrong username or password")
+		if err := userUsecase.loginLimiter.Failure(ctx, clientIP, username); err != nil {
+			return "", err
+		}
+		return "", ErrInvalidCredentials
+	}
+	if err := userUsecase.loginLimiter.Success(ctx, clientIP, username); err != nil {
+		log.Println("Error to login", err)
+		return "", err
 	}
-	ok := utils.CheckPasswordHash(password, user.Password)
-	if !ok {
-		log.Println("Wrong password")
-		return "", errors.New("Wrong password")
+	sessionID, err := utils.NewSessionID()
+	if err != nil {
+		log.Println("Error to login", err)
+		return "", err
 	}
+	now := time.Now()
+	session := models.Session{
+		ID:        sessionID,
+		UserID:    user.ID,
+		ClientIP:  clientIP,
+		CreatedAt: now,
+		ExpiresAt: now.Add(utils.TokenTTL),
+	}
+	if err := userUsecase.sessionRepository.CreateSession(session); err != nil {
+		log.Println("Error to login", err)
+		return "", err
+	}
+
 	tokenString, err := utils.GenerateToken(models.User{
 		ID:       user.ID,
 		Username: user.Username,
-	})
+	}, session)
 	if err != nil {
 		log.Println("Error to login", err)
+		return "", err
 	}
 	return tokenString, nil
 }
 
-func (userUsecase *userUsecase) ValidateToken(token string) (string, error) {
-	user, err := utils.ParseToken(token)
+func (userUsecase *userUsecase) ValidateToken(token string) (models.User, error) {
+	user, _, err := userUsecase.authenticate(token)
+	if err != nil {
+		return models.User{}, err
+	}
+	return user, nil
+}
+
+// ChangePassword replaces the password after checking the current one and revokes
+// every other session of the user
+func (userUsecase *userUsecase) ChangePassword(token, currentPassword, newPassword, clientIP string) error {
+	user, session, err := userUsecase.authenticate(token)
+	if err != nil {
+		return err
+	}
+
+	stored, err := userUsecase.verifyPassword(user.ID, currentPassword, "current_password", clientIP)
+	if err != nil {
+		return err
+	}
+	if err := validatePasswordChange(stored.Username, currentPassword, newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		log.Println("Error to change password", err)
+		return err
+	}
+	if err := userUsecase.userRepository.UpdatePassword(user.ID, hashedPassword); err != nil {
+		log.Println("Error to change password", err)
+		return err
+	}
+
+	return userUsecase.sessionRepository.RevokeOtherSessions(user.ID, session.ID)
+}
+
+// DeleteAccount removes the user and all its sessions after checking the password
+func (userUsecase *userUsecase) DeleteAccount(token, password, clientIP string) error {
+	user, _, err := userUsecase.authenticate(token)
+	if err != nil {
+		return err
+	}
+
+	if _, err := userUsecase.verifyPassword(user.ID, password, "password", clientIP); err != nil {
+		return err
+	}
+
+	if err := userUsecase.userRepository.DeleteUser(user.ID); err != nil {
+		log.Println("Error to delete account", err)
+		return err
+	}
+	return nil
+}
+
+// ListSessions returns the active sessions of the user and the ID of the token's own session
+func (userUsecase *userUsecase) ListSessions(token string) ([]models.Session, string, error) {
+	user, session, err := userUsecase.authenticate(token)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sessions, err := userUsecase.sessionRepository.ListActiveSessions(user.ID)
+	if err != nil {
+		log.Println("Error to list sessions", err)
+		return nil, "", err
+	}
+	return sessions, session.ID, nil
+}
+
+// RevokeSession signs out one session of the user, which may be the token's own
+func (userUsecase *userUsecase) RevokeSession(token, sessionID string) error {
+	user, _, err := userUsecase.authenticate(token)
+	if err != nil {
+		return err
+	}
+
+	return userUsecase.sessionRepository.RevokeSession(user.ID, sessionID)
+}
+
+// authenticate checks the token signature and that its session is still active
+func (userUsecase *userUsecase) authenticate(token string) (models.User, models.Session, error) {
+	user, sessionID, err := utils.ParseToken(token)
 	if err != nil {
 		log.Println("Error to validate token", err)
-		return "", err
+		return models.User{}, models.Session{}, ErrInvalidToken
+	}
+
+	session, err := userUsecase.sessionRepository.GetActiveSession(sessionID)
+	if err == repository.ErrSessionNotFound || (err == nil && session.UserID != user.ID) {
+		log.Println("Error to validate token, session is not active", sessionID)
+		return models.User{}, models.Session{}, ErrInvalidToken
 	}
-	result, err := json.Marshal(&user)
 	if err != nil {
 		log.Println("Error to validate token", err)
-		return "", err
+		return models.User{}, models.Session{}, err
+	}
+
+	return user, session, nil
+}
+
+// verifyPassword reports a wrong password as a violation of field. Attempts go through the login limiter
+// under the user's ID, so a stolen token can't be used to guess the password.
+func (userUsecase *userUsecase) verifyPassword(userID int64, password, field, clientIP string) (models.User, error) {
+	ctx := context.Background()
+	account := "user:" + strconv.FormatInt(userID, 10)
+	if err := userUsecase.loginLimiter.Allow(ctx, clientIP, account); err != nil {
+		log.Println("Password check rejected", err)
+		return models.User{}, err
+	}
+
+	user, err := userUsecase.userRepository.GetUserByID(userID)
+	if err == repository.ErrUserNotFound {
+		return models.User{}, ErrInvalidToken
+	}
+	if err != nil {
+		log.Println("Error to get user by id", err)
+		return models.User{}, err
+	}
+
+	if !utils.CheckPasswordHash(password, user.Password) {
+		if err := userUsecase.loginLimiter.Failure(ctx, clientIP, account); err != nil {
+			return models.User{}, err
+		}
+		return models.User{}, &ValidationError{Violations: []FieldViolation{{Field: field, Description: "is incorrect"}}}
+	}
+	if err := userUsecase.loginLimiter.Success(ctx, clientIP, account); err != nil {
+		log.Println("Error to check password", err)
+		return models.User{}, err
 	}
-	return string(result), nil
+	return user, nil
 }
@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"grpc-auth/models"
+	"grpc-auth/repository"
+	"grpc-auth/utils"
+	"io/ioutil"
+	"log"
+	"ratelimit"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type fakeUserRepository struct {
+	users map[string]models.User
+	err   error
+}
+
+func (repo *fakeUserRepository) CreateUser(username string, password string) (models.User, error) {
+	if _, ok := repo.users[username]; ok {
+		return models.User{}, repository.ErrUsernameTaken
+	}
+	user := models.User{ID: int64(len(repo.users) + 1), Username: username, Password: password}
+	repo.users[username] = user
+	return user, nil
+}
+
+func (repo *fakeUserRepository) GetUserByUsername(username string) (models.User, error) {
+	if repo.err != nil {
+		return models.User{}, repo.err
+	}
+	user, ok := repo.users[username]
+	if !ok {
+		return models.User{}, repository.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (repo *fakeUserRepository) GetUserByID(id int64) (models.User, error) {
+	for _, user := range repo.users {
+		if user.ID == id {
+			return user, nil
+		}
+	}
+	return models.User{}, repository.ErrUserNotFound
+}
+
+func (repo *fakeUserRepository) UpdatePassword(id int64, password string) error {
+	user, err := repo.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	user.Password = password
+	repo.users[user.Username] = user
+	return nil
+}
+
+func (repo *fakeUserRepository) DeleteUser(id int64) error {
+	user, err := repo.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	delete(repo.users, user.Username)
+	return nil
+}
+
+// fakeSessionRepository keeps active sessions only, revoking deletes them
+type fakeSessionRepository struct {
+	sessions map[string]models.Session
+	users    *fakeUserRepository
+}
+
+func (repo *fakeSessionRepository) CreateSession(session models.Session) error {
+	repo.sessions[session.ID] = session
+	return nil
+}
+
+func (repo *fakeSessionRepository) GetActiveSession(id string) (models.Session, error) {
+	session, ok := repo.sessions[id]
+	if !ok {
+		return models.Session{}, repository.ErrSessionNotFound
+	}
+	// mirrors ON DELETE CASCADE
+	if _, err := repo.users.GetUserByID(session.UserID); err != nil {
+		return models.Session{}, repository.ErrSessionNotFound
+	}
+	return session, nil
+}
+
+func (repo *fakeSessionRepository) ListActiveSessions(userID int64) ([]models.Session, error) {
+	sessions := []models.Session{}
+	for _, session := range repo.sessions {
+		if session.UserID == userID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (repo *fakeSessionRepository) RevokeSession(userID int64, id string) error {
+	if session, ok := repo.sessions[id]; !ok || session.UserID != userID {
+		return repository.ErrSessionNotFound
+	}
+	delete(repo.sessions, id)
+	return nil
+}
+
+func (repo *fakeSessionRepository) RevokeOtherSessions(userID int64, keepID string) error {
+	for id, session := range repo.sessions {
+		if session.UserID == userID && id != keepID {
+			delete(repo.sessions, id)
+		}
+	}
+	return nil
+}
+
+func newTestUsecase(repo *fakeUserRepository) UserUsecase {
+	utils.SecretKey = []byte("test-secret")
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBackend(), ratelimit.DefaultConfig(), log.New(ioutil.Discard, "", 0))
+	sessions := &fakeSessionRepository{sessions: map[string]models.Session{}, users: repo}
+	return InitUserUsecase(repo, sessions, limiter)
+}
+
+func newRepositoryWithAlice(t *testing.T) *fakeUserRepository {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret42"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fakeUserRepository{users: map[string]models.User{
+		"alice": {ID: 1, Username: "alice", Password: string(hash)},
+	}}
+}
+
+func TestLogin(t *testing.T) {
+	dbErr := errors.New("connection refused")
+	cases := []struct {
+		name     string
+		username string
+		password string
+		repoErr  error
+		wantErr  error
+	}{
+		{name: "valid credentials", username: "alice", password: "secret42"},
+		{name: "wrong password", username: "alice", password: "nope", wantErr: ErrInvalidCredentials},
+		{name: "unknown user", username: "bob", password: "secret42", wantErr: ErrInvalidCredentials},
+		{name: "repository failure", username: "alice", password: "secret42", repoErr: dbErr, wantErr: dbErr},
+	}
+
+	for _, c := range cases {
+		repo := newRepositoryWithAlice(t)
+		repo.err = c.repoErr
+
+		token, err := newTestUsecase(repo).Login(c.username, c.password, "127.0.0.1")
+		if err != c.wantErr {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.wantErr, err)
+		}
+		if (err == nil) != (token != "") {
+			t.Fatalf("%s: expected a token only on success, got %q", c.name, token)
+		}
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	usecase := newTestUsecase(newRepositoryWithAlice(t))
+
+	_, err := usecase.Register(" ", "")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Violations) != 2 {
+		t.Fatalf("expected two violations, got %v", err)
+	}
+
+	_, err = usecase.Register("alice", "another1pass")
+	if err != repository.ErrUsernameTaken {
+		t.Fatalf("expected ErrUsernameTaken, got %v", err)
+	}
+}
+
+func TestRegisterPasswordStrength(t *testing.T) {
+	cases := []struct {
+		password string
+		valid    bool
+	}{
+		{password: "correct1horse", valid: true},
+		{password: "short1"},
+		{password: "onlyletters"},
+		{password: "1234567890"},
+		{password: "carol2000x", valid: true},
+		{password: strings.Repeat("a1", 37)},
+	}
+
+	for _, c := range cases {
+		_, err := newTestUsecase(newRepositoryWithAlice(t)).Register("carol2000", c.password)
+		if (err == nil) != c.valid {
+			t.Fatalf("%q: expected valid=%v, got %v", c.password, c.valid, err)
+		}
+	}
+
+	_, err := newTestUsecase(newRepositoryWithAlice(t)).Register("carol2000", "CAROL2000")
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Violations[0].Description != "must not be the username" {
+		t.Fatalf("expected the username to be rejected as password, got %v", err)
+	}
+}
+
+func login(t *testing.T, usecase UserUsecase, password string) string {
+	t.Helper()
+
+	token, err := usecase.Login("alice", password, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return token
+}
+
+func TestRevokeSession(t *testing.T) {
+	usecase := newTestUsecase(newRepositoryWithAlice(t))
+	laptop := login(t, usecase, "secret42")
+	phone := login(t, usecase, "secret42")
+
+	sessions, current, err := usecase.ListSessions(laptop)
+	if err != nil || len(sessions) != 2 {
+		t.Fatalf("expected 2 sessions, got %v %v", sessions, err)
+	}
+
+	var phoneSession string
+	for _, session := range sessions {
+		if session.ID != current {
+			phoneSession = session.ID
+		}
+	}
+	if err := usecase.RevokeSession(laptop, phoneSession); err != nil {
+		t.Fatalf("RevokeSession: %v", err)
+	}
+
+	if _, err := usecase.ValidateToken(phone); err != ErrInvalidToken {
+		t.Fatalf("expected the revoked token to be rejected, got %v", err)
+	}
+	if _, err := usecase.ValidateToken(laptop); err != nil {
+		t.Fatalf("expected the other token to stay valid, got %v", err)
+	}
+	if err := usecase.RevokeSession(laptop, phoneSession); err != repository.ErrSessionNotFound {
+		t.Fatalf("expected ErrSessionNotFound revoking twice, got %v", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	usecase := newTestUsecase(newRepositoryWithAlice(t))
+	laptop := login(t, usecase, "secret42")
+	phone := login(t, usecase, "secret42")
+
+	var validationErr *ValidationError
+	err := usecase.ChangePassword(laptop, "wrong", "new-secret7", "127.0.0.1")
+	if !errors.As(err, &validationErr) || validationErr.Violations[0].Field != "current_password" {
+		t.Fatalf("expected current_password violation, got %v", err)
+	}
+	if err := usecase.ChangePassword(laptop, "secret42", "weak", "127.0.0.1"); !errors.As(err, &validationErr) {
+		t.Fatalf("expected a weak password to be rejected, got %v", err)
+	}
+
+	if err := usecase.ChangePassword(laptop, "secret42", "new-secret7", "127.0.0.1"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if _, err := usecase.ValidateToken(laptop); err != nil {
+		t.Fatalf("expected the changing session to stay valid, got %v", err)
+	}
+	if _, err := usecase.ValidateToken(phone); err != ErrInvalidToken {
+		t.Fatalf("expected other sessions to be revoked, got %v", err)
+	}
+	if _, err := usecase.Login("alice", "secret42", "127.0.0.1"); err != ErrInvalidCredentials {
+		t.Fatalf("expected the old password to be rejected, got %v", err)
+	}
+	login(t, usecase, "new-secret7")
+}
+
+func TestChangePasswordLocksAfterRepeatedFailures(t *testing.T) {
+	usecase := newTestUsecase(newRepositoryWithAlice(t))
+	token := login(t, usecase, "secret42")
+
+	var limitedErr *ratelimit.LimitedError
+	for i := int64(0); i < ratelimit.DefaultConfig().LockoutThreshold; i++ {
+		err := usecase.ChangePassword(token, fmt.Sprintf("guess-%d", i), "new-secret7", "203.0.113.9")
+		if errors.As(err, &limitedErr) {
+			break
+		}
+	}
+	if limitedErr == nil {
+		t.Fatal("expected repeated wrong passwords to be limited")
+	}
+
+	// the right password doesn't get through the lock either
+	if err := usecase.DeleteAccount(token, "secret42", "203.0.113.10"); !errors.As(err, &limitedErr) {
+		t.Fatalf("expected the account to stay locked, got %v", err)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	repo := newRepositoryWithAlice(t)
+	usecase := newTestUsecase(repo)
+	token := login(t, usecase, "secret42")
+
+	var validationErr *ValidationError
+	if err := usecase.DeleteAccount(token, "wrong", "127.0.0.1"); !errors.As(err, &validationErr) {
+		t.Fatalf("expected a wrong password to be rejected, got %v", err)
+	}
+	if err := usecase.DeleteAccount(token, "secret42", "127.0.0.1"); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+
+	if _, ok := repo.users["alice"]; ok {
+		t.Fatal("expected alice to be deleted")
+	}
+	if _, err := usecase.ValidateToken(token); err != ErrInvalidToken {
+		t.Fatalf("expected the token of a deleted user to be rejected, got %v", err)
+	}
+}
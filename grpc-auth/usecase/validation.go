@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	minPasswordLength = 8
+	// maxPasswordLength is where bcrypt stops reading the password
+	maxPasswordLength = 72
+)
+
+// FieldViolation describes why a request field was rejected
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists the request fields that failed validation
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Field+": "+violation.Description)
+	}
+
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+// validateCredentials reports every invalid field of a register request at once
+func validateCredentials(username, password string) error {
+	var violations []FieldViolation
+	if strings.TrimSpace(username) == "" {
+		violations = append(violations, FieldViolation{Field: "username", Description: "must not be empty"})
+	}
+	violations = append(violations, passwordViolations("password", username, password)...)
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// validatePasswordChange checks the new password and that it differs from the current one
+func validatePasswordChange(username, currentPassword, newPassword string) error {
+	violations := passwordViolations("new_password", username, newPassword)
+	if newPassword != "" && newPassword == currentPassword {
+		violations = append(violations, FieldViolation{Field: "new_password", Description: "must differ from the current password"})
+	}
+
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// passwordViolations applies the password strength rules: 8 to 72 bytes, at least one
+// letter and one digit, and not the username
+func passwordViolations(field, username, password string) []FieldViolation {
+	if password == "" {
+		return []FieldViolation{{Field: field, Description: "must not be empty"}}
+	}
+
+	var violations []FieldViolation
+	if len(password) < minPasswordLength {
+		violations = append(violations, FieldViolation{Field: field, Description: "must be at least 8 characters long"})
+	}
+	if len(password) > maxPasswordLength {
+		violations = append(violations, FieldViolation{Field: field, Description: "must be at most 72 bytes long"})
+	}
+	if !strings.ContainsAny(password, "0123456789") || strings.IndexFunc(password, unicode.IsLetter) < 0 {
+		violations = append(violations, FieldViolation{Field: field, Description: "must contain a letter and a digit"})
+	}
+	if strings.EqualFold(strings.TrimSpace(password), strings.TrimSpace(username)) {
+		violations = append(violations, FieldViolation{Field: field, Description: "must not be the username"})
+	}
+
+	return violations
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// BearerToken returns the token of the call's "authorization: Bearer" header, or "" without one
+func BearerToken(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	const prefix = "bearer "
+	if len(values[0]) <= len(prefix) || !strings.EqualFold(values[0][:len(prefix)], prefix) {
+		return ""
+	}
+	return values[0][len(prefix):]
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"net"
+
+	"grpc-tls/tlsconfig"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientIPMetadata is the metadata key the API gateway forwards the address of its HTTP client in
+const ClientIPMetadata = "x-client-ip"
+
+// TrustedGateways are the client certificate names or peer IPs allowed to forward ClientIPMetadata,
+// main sets them from config.TrustedGateways
+var TrustedGateways []string
+
+// PeerIP returns the IP address of the client of a gRPC call
+func PeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// ClientIP returns the IP address of whoever made the request. Calls from a trusted gateway are
+// attributed to the address it forwarded, every other call to its peer address.
+func ClientIP(ctx context.Context) string {
+	peerIP := PeerIP(ctx)
+	if !fromTrustedGateway(ctx, peerIP) {
+		return peerIP
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	forwarded := md.Get(ClientIPMetadata)
+	if len(forwarded) != 1 || net.ParseIP(forwarded[0]) == nil {
+		return peerIP
+	}
+	return forwarded[0]
+}
+
+func fromTrustedGateway(ctx context.Context, peerIP string) bool {
+	names := []string{peerIP}
+	if identity, ok := tlsconfig.PeerIdentity(ctx); ok {
+		names = append(names, identity.Names()...)
+	}
+
+	for _, name := range names {
+		for _, trusted := range TrustedGateways {
+			if name != "" && name == trusted {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientIP(t *testing.T) {
+	TrustedGateways = []string{"10.0.0.2"}
+	defer func() { TrustedGateways = nil }()
+
+	cases := []struct {
+		name      string
+		peer      string
+		forwarded []string
+		want      string
+	}{
+		{name: "direct call", peer: "203.0.113.9", want: "203.0.113.9"},
+		{name: "forwarded by the gateway", peer: "10.0.0.2", forwarded: []string{"198.51.100.7"}, want: "198.51.100.7"},
+		{name: "gateway without forwarded address", peer: "10.0.0.2", want: "10.0.0.2"},
+		{name: "forwarded by anyone else", peer: "203.0.113.9", forwarded: []string{"198.51.100.7"}, want: "203.0.113.9"},
+		{name: "forwarded value is not an address", peer: "10.0.0.2", forwarded: []string{"somewhere"}, want: "10.0.0.2"},
+		{name: "forwarded twice", peer: "10.0.0.2", forwarded: []string{"198.51.100.7", "198.51.100.8"}, want: "10.0.0.2"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(c.peer), Port: 50000}})
+			md := metadata.MD{}
+			for _, value := range c.forwarded {
+				md.Append(ClientIPMetadata, value)
+			}
+			ctx = metadata.NewIncomingContext(ctx, md)
+
+			if got := ClientIP(ctx); got != c.want {
+				t.Fatalf("expected %s, got %s", c.want, got)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"grpc-auth/models"
 	"log"
@@ -10,16 +13,27 @@ import (
 	"github.com/dgrijalva/jwt-go"
 )
 
-var (
-	SecretKey = []byte("ThisIsASecretKey")
-)
+// SecretKey signs every token, main sets it from config.AuthSecretKey
+var SecretKey []byte
+
+// errNoSecretKey keeps tokens from being signed or accepted with an empty key
+var errNoSecretKey = errors.New("token secret key is not configured")
+
+// TokenTTL is how long a token and its session stay valid
+const TokenTTL = 24 * time.Hour
+
+// GenerateToken signs a token for the user's session, the session ID becomes the jti claim
+func GenerateToken(tokenResult models.User, session models.Session) (string, error) {
+	if len(SecretKey) == 0 {
+		return "", errNoSecretKey
+	}
 
-func GenerateToken(tokenResult models.User) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 	claims := token.Claims.(jwt.MapClaims)
 	claims["id"] = tokenResult.ID
 	claims["username"] = tokenResult.Username
-	claims["exp"] = time.Now().Add(time.Hour * 24).Unix()
+	claims["jti"] = session.ID
+	claims["exp"] = session.ExpiresAt.Unix()
 	tokenString, err := token.SignedString(SecretKey)
 	if err != nil {
 		log.Println("Error in generating key")
@@ -28,21 +42,39 @@ func GenerateToken(tokenResult models.User) (string, error) {
 	return tokenString, nil
 }
 
-func ParseToken(tokenStr string) (models.User, error) {
+// ParseToken returns the user and the session ID of a token signed by GenerateToken
+func ParseToken(tokenStr string) (models.User, string, error) {
+	if len(SecretKey) == 0 {
+		return models.User{}, "", errNoSecretKey
+	}
+
 	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
 		return SecretKey, nil
 	})
 	if err != nil {
 		log.Println("Error to parse token", err)
-		return models.User{}, err
+		return models.User{}, "", err
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
 		idStr := fmt.Sprintf("%v", claims["id"])
 		id, _ := strconv.ParseInt(idStr, 10, 64)
-		username := claims["username"].(string)
-		return models.User{Username: username, ID: id}, nil
+		username, _ := claims["username"].(string)
+		sessionID, _ := claims["jti"].(string)
+		if sessionID == "" {
+			return models.User{}, "", errors.New("token has no session")
+		}
+		return models.User{Username: username, ID: id}, sessionID, nil
 	}
 
-	return models.User{}, err
+	return models.User{}, "", errors.New("invalid token claims")
+}
+
+// NewSessionID returns a random token ID
+func NewSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
@@ -0,0 +1,11 @@
+module grpc-observability
+
+go 1.15
+
+require (
+	github.com/prometheus/client_golang v1.9.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/sdk v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
+	google.golang.org/grpc v1.35.0
+)
@@ -0,0 +1,36 @@
+package interceptor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryClientInterceptor forwards the request ID and trace context of ctx to the called server
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingContext(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingContext(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoingContext(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		md.Set(RequestIDKey, requestID)
+	}
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		propagator.Inject(ctx, metadataCarrier(md))
+	}
+
+	return metadata.NewOutgoingContext(ctx, md)
+}
@@ -0,0 +1,173 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// panickingHealth panics on every Check so recovery can be exercised end to end
+type panickingHealth struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (panickingHealth) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	panic("boom")
+}
+
+type testServer struct {
+	client   healthpb.HealthClient
+	logs     *bytes.Buffer
+	observer *Observer
+	close    func()
+}
+
+func newTestServer(t *testing.T, config Config, service healthpb.HealthServer) *testServer {
+	t.Helper()
+
+	logs := &bytes.Buffer{}
+	config.Logger = log.New(logs, "", 0)
+	config.Registerer = prometheus.NewRegistry()
+
+	observer, err := New(config)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(observer.ServerOptions()...)
+	healthpb.RegisterHealthServer(server, service)
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure(),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+	)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+
+	return &testServer{
+		client:   healthpb.NewHealthClient(conn),
+		logs:     logs,
+		observer: observer,
+		close: func() {
+			conn.Close()
+			server.Stop()
+		},
+	}
+}
+
+func TestRequestIDPropagated(t *testing.T) {
+	ts := newTestServer(t, Config{}, health.NewServer())
+	defer ts.close()
+
+	var header metadata.MD
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	if _, err := ts.client.Check(ctx, &healthpb.HealthCheckRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if got := header.Get(RequestIDKey); len(got) != 1 || got[0] != "req-123" {
+		t.Fatalf("expected request id header req-123, got %v", got)
+	}
+	line := ts.logs.String()
+	for _, want := range []string{"method=/grpc.health.v1.Health/Check", "code=OK", "request_id=req-123", "peer="} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected log line to contain %q, got %q", want, line)
+		}
+	}
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	ts := newTestServer(t, Config{}, health.NewServer())
+	defer ts.close()
+
+	var header metadata.MD
+	if _, err := ts.client.Check(context.Background(), &healthpb.HealthCheckRequest{}, grpc.Header(&header)); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+
+	if got := header.Get(RequestIDKey); len(got) != 1 || len(got[0]) != 32 {
+		t.Fatalf("expected a generated request id, got %v", got)
+	}
+}
+
+func TestMetricsPerMethodAndCode(t *testing.T) {
+	ts := newTestServer(t, Config{}, health.NewServer())
+	defer ts.close()
+
+	ts.client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	_, err := ts.client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "missing"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	m := ts.observer.metrics
+	if got := testutil.ToFloat64(m.handled.WithLabelValues("/grpc.health.v1.Health/Check", "OK")); got != 1 {
+		t.Fatalf("expected 1 OK call, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.handled.WithLabelValues("/grpc.health.v1.Health/Check", "NotFound")); got != 1 {
+		t.Fatalf("expected 1 NotFound call, got %v", got)
+	}
+}
+
+func TestPanicRecovered(t *testing.T) {
+	ts := newTestServer(t, Config{}, panickingHealth{})
+	defer ts.close()
+
+	_, err := ts.client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("expected Internal, got %v", err)
+	}
+
+	m := ts.observer.metrics
+	if got := testutil.ToFloat64(m.panics.WithLabelValues("/grpc.health.v1.Health/Check")); got != 1 {
+		t.Fatalf("expected 1 panic, got %v", got)
+	}
+	if got := testutil.ToFloat64(m.handled.WithLabelValues("/grpc.health.v1.Health/Check", "Internal")); got != 1 {
+		t.Fatalf("expected the panic counted as Internal, got %v", got)
+	}
+	if !strings.Contains(ts.logs.String(), `panic="boom"`) {
+		t.Fatalf("expected the panic to be logged, got %q", ts.logs.String())
+	}
+}
+
+func TestTracingSpans(t *testing.T) {
+	provider, exporter := NewInMemoryTracerProvider()
+	ts := newTestServer(t, Config{TracerProvider: provider}, health.NewServer())
+	defer ts.close()
+
+	parentCtx, parent := provider.Tracer("test").Start(context.Background(), "caller")
+	ts.client.Check(parentCtx, &healthpb.HealthCheckRequest{Service: "missing"})
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected server and caller spans, got %d", len(spans))
+	}
+	server := spans[0]
+	if server.Name != "/grpc.health.v1.Health/Check" {
+		t.Fatalf("expected span named after the method, got %q", server.Name)
+	}
+	if server.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("expected the server span to continue the caller trace")
+	}
+	if server.Status.Code.String() != "Error" {
+		t.Fatalf("expected an error status, got %v", server.Status.Code)
+	}
+}
@@ -0,0 +1,47 @@
+package interceptor
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryLogging writes one key=value line per call with its method, code, duration and peer
+func (o *Observer) UnaryLogging() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		o.logCall(ctx, info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+func (o *Observer) StreamLogging() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		o.logCall(stream.Context(), info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func (o *Observer) logCall(ctx context.Context, method string, start time.Time, err error) {
+	st := status.Convert(err)
+	peerAddr := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		peerAddr = p.Addr.String()
+	}
+
+	if err != nil {
+		o.logger.Printf("method=%s code=%s duration=%s peer=%s request_id=%s error=%q",
+			method, st.Code(), time.Since(start), peerAddr, RequestIDFromContext(ctx), st.Message())
+		return
+	}
+	o.logger.Printf("method=%s code=%s duration=%s peer=%s request_id=%s",
+		method, st.Code(), time.Since(start), peerAddr, RequestIDFromContext(ctx))
+}
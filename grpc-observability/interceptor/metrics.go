@@ -0,0 +1,90 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+type metrics struct {
+	handled  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	panics   *prometheus.CounterVec
+}
+
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Number of RPCs completed on the server, by method and code.",
+		}, []string{"grpc_method", "grpc_code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Time taken by the server to complete an RPC, by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"grpc_method"}),
+		panics: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_panics_total",
+			Help: "Number of handler panics recovered, by method.",
+		}, []string{"grpc_method"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.handled, m.duration, m.panics} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *metrics) observe(method string, start time.Time, err error) {
+	m.handled.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.duration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// UnaryMetrics counts calls per method and code and records their duration
+func (o *Observer) UnaryMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if o.metrics == nil {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		o.metrics.observe(info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+func (o *Observer) StreamMetrics() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if o.metrics == nil {
+			return handler(srv, stream)
+		}
+
+		start := time.Now()
+		err := handler(srv, stream)
+		o.metrics.observe(info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+// ServeMetrics exposes the default Prometheus registry on addr at /metrics in the background
+func ServeMetrics(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Println("metrics server stopped: ", err)
+		}
+	}()
+}
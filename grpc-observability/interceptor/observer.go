@@ -0,0 +1,85 @@
+// Package interceptor adds request IDs, tracing, structured logging, Prometheus
+// metrics and panic recovery to gRPC servers and clients.
+package interceptor
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+)
+
+type Config struct {
+	// Logger receives one line per call, defaults to stdout
+	Logger *log.Logger
+	// Registerer receives the call metrics, nil disables them
+	Registerer prometheus.Registerer
+	// TracerProvider creates the call spans, defaults to the global provider
+	TracerProvider trace.TracerProvider
+}
+
+// Observer holds the state shared by the interceptors of one server
+type Observer struct {
+	logger  *log.Logger
+	metrics *metrics
+	tracer  trace.Tracer
+}
+
+func New(config Config) (*Observer, error) {
+	if config.Logger == nil {
+		config.Logger = log.New(os.Stdout, "[grpc] ", log.LstdFlags|log.LUTC)
+	}
+	if config.TracerProvider == nil {
+		config.TracerProvider = otel.GetTracerProvider()
+	}
+
+	var m *metrics
+	if config.Registerer != nil {
+		var err error
+		if m, err = newMetrics(config.Registerer); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Observer{
+		logger:  config.Logger,
+		metrics: m,
+		tracer:  config.TracerProvider.Tracer("grpc-observability/interceptor"),
+	}, nil
+}
+
+// ServerOptions chains every interceptor in order: request ID, tracing, logging, metrics
+// and recovery. Recovery runs innermost so a panic is logged and counted as Internal.
+// Interceptors added later with grpc.ChainUnaryInterceptor run after these.
+func (o *Observer) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			o.UnaryRequestID(),
+			o.UnaryTracing(),
+			o.UnaryLogging(),
+			o.UnaryMetrics(),
+			o.UnaryRecovery(),
+		),
+		grpc.ChainStreamInterceptor(
+			o.StreamRequestID(),
+			o.StreamTracing(),
+			o.StreamLogging(),
+			o.StreamMetrics(),
+			o.StreamRecovery(),
+		),
+	}
+}
+
+// wrappedStream replaces the context of a server stream
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *wrappedStream) Context() context.Context {
+	return stream.ctx
+}
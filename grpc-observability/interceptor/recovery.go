@@ -0,0 +1,44 @@
+package interceptor
+
+import (
+	"context"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryRecovery turns a handler panic into an Internal error instead of crashing the server
+func (o *Observer) UnaryRecovery() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = o.recovered(ctx, info.FullMethod, r)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+func (o *Observer) StreamRecovery() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = o.recovered(stream.Context(), info.FullMethod, r)
+			}
+		}()
+
+		return handler(srv, stream)
+	}
+}
+
+func (o *Observer) recovered(ctx context.Context, method string, r interface{}) error {
+	o.logger.Printf("method=%s request_id=%s panic=%q\n%s", method, RequestIDFromContext(ctx), r, debug.Stack())
+	if o.metrics != nil {
+		o.metrics.panics.WithLabelValues(method).Inc()
+	}
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,63 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDKey is the metadata key carrying the request ID in both directions
+const RequestIDKey = "x-request-id"
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the request ID of the current call, empty outside a call
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// ContextWithRequestID makes outgoing calls made with the client interceptors reuse requestID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// UnaryRequestID takes the request ID from the incoming metadata or generates one,
+// stores it in the context and echoes it in the response header
+func (o *Observer) UnaryRequestID() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx = withIncomingRequestID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, RequestIDFromContext(ctx)))
+
+		return handler(ctx, req)
+	}
+}
+
+func (o *Observer) StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := withIncomingRequestID(stream.Context())
+		stream.SetHeader(metadata.Pairs(RequestIDKey, RequestIDFromContext(ctx)))
+
+		return handler(srv, &wrappedStream{stream, ctx})
+	}
+}
+
+func withIncomingRequestID(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIDKey); len(values) > 0 && values[0] != "" {
+		return ContextWithRequestID(ctx, values[0])
+	}
+
+	return ContextWithRequestID(ctx, newRequestID())
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,92 @@
+package interceptor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// propagator reads and writes the W3C traceparent metadata
+var propagator = propagation.TraceContext{}
+
+// metadataCarrier adapts gRPC metadata to the OpenTelemetry propagation API
+type metadataCarrier metadata.MD
+
+func (carrier metadataCarrier) Get(key string) string {
+	values := metadata.MD(carrier).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (carrier metadataCarrier) Set(key, value string) {
+	metadata.MD(carrier).Set(key, value)
+}
+
+func (carrier metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(carrier))
+	for key := range carrier {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// NewInMemoryTracerProvider records every finished span in the returned exporter, for tests
+func NewInMemoryTracerProvider() (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return provider, exporter
+}
+
+// UnaryTracing starts a server span per call, continuing the trace of the caller if any
+func (o *Observer) UnaryTracing() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, span := o.startSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+
+		return resp, err
+	}
+}
+
+func (o *Observer) StreamTracing() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := o.startSpan(stream.Context(), info.FullMethod)
+		err := handler(srv, &wrappedStream{stream, ctx})
+		endSpan(span, err)
+
+		return err
+	}
+}
+
+func (o *Observer) startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = propagator.Extract(ctx, metadataCarrier(md.Copy()))
+
+	return o.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "grpc"),
+			attribute.String("rpc.method", method),
+			attribute.String("request_id", RequestIDFromContext(ctx)),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(attribute.Int64("rpc.grpc.status_code", int64(code)))
+	if err != nil {
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+	span.End()
+}
@@ -0,0 +1,51 @@
+// Command devca writes a development CA plus server and client certificates for
+// running the gRPC services with mutual TLS locally.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"grpc-tls/tlsconfig"
+)
+
+func main() {
+	out := flag.String("out", "certs", "directory the certificates are written to")
+	hosts := flag.String("hosts", "localhost,127.0.0.1", "comma separated host names and IPs of the server certificate")
+	clients := flag.String("clients", "api-gateway", "comma separated names to issue client certificates for")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0755); err != nil {
+		log.Fatalf("failed to create %s: %v", *out, err)
+	}
+
+	ca, err := tlsconfig.NewDevCA(*out)
+	if err != nil {
+		log.Fatalf("failed to create CA: %v", err)
+	}
+	if err := ca.IssueServer(*out, "server", split(*hosts)); err != nil {
+		log.Fatalf("failed to issue server certificate: %v", err)
+	}
+	for _, client := range split(*clients) {
+		if err := ca.IssueClient(*out, client); err != nil {
+			log.Fatalf("failed to issue client certificate %s: %v", client, err)
+		}
+	}
+
+	fmt.Println("Wrote certificates to", *out)
+	fmt.Println("Servers: TLS_CERT_FILE=server.pem TLS_KEY_FILE=server-key.pem TLS_CA_FILE=ca.pem")
+	fmt.Println("Clients: TLS_CERT_FILE=<client>.pem TLS_KEY_FILE=<client>-key.pem TLS_CA_FILE=ca.pem")
+}
+
+func split(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
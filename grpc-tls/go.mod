@@ -0,0 +1,5 @@
+module grpc-tls
+
+go 1.15
+
+require google.golang.org/grpc v1.35.0
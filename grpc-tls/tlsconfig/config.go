@@ -0,0 +1,49 @@
+// Package tlsconfig builds gRPC transport credentials with optional mutual TLS,
+// reloads certificates when their files change and exposes the client certificate
+// identity of a call.
+package tlsconfig
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultReloadInterval is how often certificate files are checked for changes
+const defaultReloadInterval = 10 * time.Second
+
+type Config struct {
+	// CertFile and KeyFile are the PEM key pair presented to the other side
+	CertFile string
+	KeyFile  string
+	// CAFile verifies the other side: client certificates on a server, which turns on
+	// mutual TLS, and the server certificate on a client
+	CAFile string
+	// ServerName overrides the host name a client verifies, defaults to the dialed host
+	ServerName string
+	// AllowedPeers restricts a mutual TLS server to these client certificate names
+	AllowedPeers []string
+	// ReloadInterval is how often the files are checked for changes
+	ReloadInterval time.Duration
+}
+
+// LoadConfig reads TLS_CERT_FILE, TLS_KEY_FILE, TLS_CA_FILE, TLS_SERVER_NAME and the
+// comma separated TLS_ALLOWED_PEERS from the environment. Everything is optional,
+// without them servers and clients keep using plaintext.
+func LoadConfig() Config {
+	config := Config{
+		CertFile:       os.Getenv("TLS_CERT_FILE"),
+		KeyFile:        os.Getenv("TLS_KEY_FILE"),
+		CAFile:         os.Getenv("TLS_CA_FILE"),
+		ServerName:     os.Getenv("TLS_SERVER_NAME"),
+		ReloadInterval: defaultReloadInterval,
+	}
+
+	for _, peer := range strings.Split(os.Getenv("TLS_ALLOWED_PEERS"), ",") {
+		if peer = strings.TrimSpace(peer); peer != "" {
+			config.AllowedPeers = append(config.AllowedPeers, peer)
+		}
+	}
+
+	return config
+}
@@ -0,0 +1,113 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// ErrMissingKeyPair is returned when only one of CertFile and KeyFile is set
+var ErrMissingKeyPair = errors.New("TLS needs both a certificate and a key file")
+
+// ServerOptions returns the options serving TLS with config, or none when no certificate
+// is configured. The returned function stops watching the certificate files.
+func ServerOptions(config Config) ([]grpc.ServerOption, func(), error) {
+	if config.CertFile == "" && config.KeyFile == "" {
+		return nil, func() {}, nil
+	}
+
+	creds, stop, err := ServerCredentials(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	options := []grpc.ServerOption{grpc.Creds(creds)}
+	if len(config.AllowedPeers) > 0 {
+		options = append(options,
+			grpc.ChainUnaryInterceptor(UnaryPeerInterceptor(config.AllowedPeers)),
+			grpc.ChainStreamInterceptor(StreamPeerInterceptor(config.AllowedPeers)),
+		)
+	}
+
+	return options, stop, nil
+}
+
+// ServerCredentials serves the configured key pair and, when CAFile is set, requires
+// client certificates signed by it. Both are picked up again when their files change.
+func ServerCredentials(config Config) (credentials.TransportCredentials, func(), error) {
+	if config.CertFile == "" || config.KeyFile == "" {
+		return nil, nil, ErrMissingKeyPair
+	}
+
+	r, err := newReloader(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			serverConfig := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.certificate()},
+				NextProtos:   []string{"h2"},
+			}
+			if pool := r.certPool(); pool != nil {
+				serverConfig.ClientCAs = pool
+				serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+			return serverConfig, nil
+		},
+	}
+
+	return credentials.NewTLS(tlsConfig), r.watch(), nil
+}
+
+// DialOption returns TLS credentials when CAFile is set and plaintext otherwise.
+// The returned function stops watching the certificate files.
+func DialOption(config Config) (grpc.DialOption, func(), error) {
+	if config.CAFile == "" {
+		return grpc.WithInsecure(), func() {}, nil
+	}
+
+	creds, stop, err := ClientCredentials(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return grpc.WithTransportCredentials(creds), stop, nil
+}
+
+// ClientCredentials verifies the server against CAFile and presents the configured
+// key pair, if any, for mutual TLS. The key pair is reloaded when its files change,
+// the CA is read once.
+func ClientCredentials(config Config) (credentials.TransportCredentials, func(), error) {
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return nil, nil, ErrMissingKeyPair
+	}
+
+	r, err := newReloader(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    r.certPool(),
+		ServerName: config.ServerName,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.certificate(); cert != nil {
+				return cert, nil
+			}
+			return &tls.Certificate{}, nil
+		},
+	}
+	if tlsConfig.RootCAs == nil {
+		tlsConfig.RootCAs, _ = x509.SystemCertPool()
+	}
+
+	return credentials.NewTLS(tlsConfig), r.watch(), nil
+}
@@ -0,0 +1,132 @@
+package tlsconfig
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+)
+
+// devValidity is how long development certificates stay valid
+const devValidity = 365 * 24 * time.Hour
+
+type DevCA struct {
+	cert *x509.Certificate
+	key  crypto.Signer
+}
+
+// NewDevCA creates a self-signed CA for local development and writes it to
+// dir/ca.pem and dir/ca-key.pem
+func NewDevCA(dir string) (*DevCA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := certificateTemplate("grpc dev CA")
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writePair(dir, "ca", der, key); err != nil {
+		return nil, err
+	}
+
+	return &DevCA{cert: cert, key: key}, nil
+}
+
+// IssueServer writes dir/<name>.pem and dir/<name>-key.pem valid for hosts,
+// which may be DNS names or IP addresses
+func (ca *DevCA) IssueServer(dir, name string, hosts []string) error {
+	template, err := certificateTemplate(name)
+	if err != nil {
+		return err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	return ca.issue(dir, name, template)
+}
+
+// IssueClient writes dir/<name>.pem and dir/<name>-key.pem with name as common name,
+// the identity servers match against their allowed peers
+func (ca *DevCA) IssueClient(dir, name string) error {
+	template, err := certificateTemplate(name)
+	if err != nil {
+		return err
+	}
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	return ca.issue(dir, name, template)
+}
+
+func (ca *DevCA) issue(dir, name string, template *x509.Certificate) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	if err != nil {
+		return err
+	}
+
+	return writePair(dir, name, der, key)
+}
+
+func certificateTemplate(commonName string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(devValidity),
+	}, nil
+}
+
+func writePair(dir, name string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	if err := ioutil.WriteFile(filepath.Join(dir, name+".pem"), certPEM, 0644); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPEM, 0600)
+}
@@ -0,0 +1,86 @@
+package tlsconfig
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Identity is who a verified client certificate was issued to
+type Identity struct {
+	CommonName string
+	DNSNames   []string
+	URIs       []string
+}
+
+// Names lists every name the identity can be matched by
+func (identity Identity) Names() []string {
+	names := append([]string{identity.CommonName}, identity.DNSNames...)
+	return append(names, identity.URIs...)
+}
+
+// PeerIdentity returns the identity of the verified client certificate of the call,
+// it is false for plaintext calls and TLS calls without a client certificate
+func PeerIdentity(ctx context.Context) (Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return Identity{}, false
+	}
+
+	leaf := tlsInfo.State.VerifiedChains[0][0]
+	identity := Identity{
+		CommonName: leaf.Subject.CommonName,
+		DNSNames:   leaf.DNSNames,
+	}
+	for _, uri := range leaf.URIs {
+		identity.URIs = append(identity.URIs, uri.String())
+	}
+
+	return identity, true
+}
+
+// UnaryPeerInterceptor admits only calls whose client certificate matches one of allowed
+func UnaryPeerInterceptor(allowed []string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorizePeer(ctx, allowed); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func StreamPeerInterceptor(allowed []string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorizePeer(stream.Context(), allowed); err != nil {
+			return err
+		}
+
+		return handler(srv, stream)
+	}
+}
+
+func authorizePeer(ctx context.Context, allowed []string) error {
+	identity, ok := PeerIdentity(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing client certificate")
+	}
+
+	for _, name := range identity.Names() {
+		for _, allowedName := range allowed {
+			if name == allowedName {
+				return nil
+			}
+		}
+	}
+
+	return status.Errorf(codes.PermissionDenied, "client %q is not allowed", identity.CommonName)
+}
@@ -0,0 +1,161 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// reloader keeps the latest key pair and CA pool loaded from the configured files
+type reloader struct {
+	config Config
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+func newReloader(config Config) (*reloader, error) {
+	r := &reloader{config: config}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *reloader) load() error {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		return err
+	}
+
+	var cert *tls.Certificate
+	if r.config.CertFile != "" {
+		pair, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+		if err != nil {
+			return fmt.Errorf("load key pair: %w", err)
+		}
+		cert = &pair
+	}
+
+	var pool *x509.CertPool
+	if r.config.CAFile != "" {
+		if pool, err = loadCertPool(r.config.CAFile); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pool, r.modTimes = cert, pool, modTimes
+	r.mu.Unlock()
+
+	return nil
+}
+
+// reloadIfChanged loads the files again when any of them was modified, a broken
+// update is logged and the previous certificates stay in use
+func (r *reloader) reloadIfChanged() {
+	modTimes, err := r.statFiles()
+	if err != nil {
+		log.Println("Error checking certificates", err)
+		return
+	}
+
+	r.mu.RLock()
+	changed := false
+	for file, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[file]) {
+			changed = true
+		}
+	}
+	r.mu.RUnlock()
+	if !changed {
+		return
+	}
+
+	if err := r.load(); err != nil {
+		log.Println("Error reloading certificates, keeping the previous ones", err)
+		return
+	}
+	log.Println("Reloaded TLS certificates")
+}
+
+// watch polls the files until the returned function is called
+func (r *reloader) watch() func() {
+	interval := r.config.ReloadInterval
+	if interval <= 0 {
+		interval = defaultReloadInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				r.reloadIfChanged()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+func (r *reloader) certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert
+}
+
+func (r *reloader) certPool() *x509.CertPool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+func (r *reloader) statFiles() (map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+	for _, file := range []string{r.config.CertFile, r.config.KeyFile, r.config.CAFile} {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificates found in " + file)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,206 @@
+package tlsconfig
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newDevCerts(t *testing.T) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "tlsconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := NewDevCA(dir)
+	if err != nil {
+		t.Fatalf("NewDevCA: %v", err)
+	}
+	if err := ca.IssueServer(dir, "server", []string{"localhost"}); err != nil {
+		t.Fatalf("IssueServer: %v", err)
+	}
+	for _, client := range []string{"api-gateway", "intruder"} {
+		if err := ca.IssueClient(dir, client); err != nil {
+			t.Fatalf("IssueClient: %v", err)
+		}
+	}
+
+	return dir
+}
+
+func serverConfig(dir string) Config {
+	return Config{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server-key.pem"),
+		CAFile:       filepath.Join(dir, "ca.pem"),
+		AllowedPeers: []string{"api-gateway"},
+	}
+}
+
+func clientConfig(dir, name string) Config {
+	return Config{
+		CertFile:   filepath.Join(dir, name+".pem"),
+		KeyFile:    filepath.Join(dir, name+"-key.pem"),
+		CAFile:     filepath.Join(dir, "ca.pem"),
+		ServerName: "localhost",
+	}
+}
+
+// serve starts a health server with options and records the identity of every call
+func serve(t *testing.T, options []grpc.ServerOption, identities chan<- Identity) (*bufconn.Listener, func()) {
+	t.Helper()
+
+	record := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if identity, ok := PeerIdentity(ctx); ok {
+			identities <- identity
+		}
+		return handler(ctx, req)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(append(options, grpc.ChainUnaryInterceptor(record))...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+
+	return listener, server.Stop
+}
+
+func check(t *testing.T, listener *bufconn.Listener, option grpc.DialOption) error {
+	t.Helper()
+
+	conn, err := grpc.Dial("localhost",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		option,
+	)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestMutualTLSPeerIdentity(t *testing.T) {
+	dir := newDevCerts(t)
+	defer os.RemoveAll(dir)
+
+	options, stop, err := ServerOptions(serverConfig(dir))
+	if err != nil {
+		t.Fatalf("ServerOptions: %v", err)
+	}
+	defer stop()
+
+	identities := make(chan Identity, 1)
+	listener, stopServer := serve(t, options, identities)
+	defer stopServer()
+
+	option, stopClient, err := DialOption(clientConfig(dir, "api-gateway"))
+	if err != nil {
+		t.Fatalf("DialOption: %v", err)
+	}
+	defer stopClient()
+
+	if err := check(t, listener, option); err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if identity := <-identities; identity.CommonName != "api-gateway" {
+		t.Fatalf("expected api-gateway identity, got %+v", identity)
+	}
+
+	intruder, stopIntruder, _ := DialOption(clientConfig(dir, "intruder"))
+	defer stopIntruder()
+	if err := check(t, listener, intruder); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for a client outside the allowed peers, got %v", err)
+	}
+
+	anonymous, stopAnonymous, _ := DialOption(Config{CAFile: filepath.Join(dir, "ca.pem"), ServerName: "localhost"})
+	defer stopAnonymous()
+	if err := check(t, listener, anonymous); err == nil {
+		t.Fatal("expected the handshake to fail without a client certificate")
+	}
+}
+
+func TestServerOptionsPlaintextWithoutCertificate(t *testing.T) {
+	options, stop, err := ServerOptions(Config{})
+	if err != nil || len(options) != 0 {
+		t.Fatalf("expected no options, got %v %v", options, err)
+	}
+	stop()
+
+	if _, _, err := ServerOptions(Config{CertFile: "server.pem"}); err != ErrMissingKeyPair {
+		t.Fatalf("expected ErrMissingKeyPair, got %v", err)
+	}
+}
+
+func TestServerCertificateReload(t *testing.T) {
+	dir := newDevCerts(t)
+	defer os.RemoveAll(dir)
+
+	config := serverConfig(dir)
+	config.ReloadInterval = 10 * time.Millisecond
+	options, stop, err := ServerOptions(config)
+	if err != nil {
+		t.Fatalf("ServerOptions: %v", err)
+	}
+	defer stop()
+
+	listener, stopServer := serve(t, options, make(chan Identity, 10))
+	defer stopServer()
+
+	// rotate everything to a new CA, only a reloaded server accepts the new client
+	rotated, err := ioutil.TempDir("", "tlsconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(rotated)
+	ca, err := NewDevCA(rotated)
+	if err != nil {
+		t.Fatalf("NewDevCA: %v", err)
+	}
+	ca.IssueServer(rotated, "server", []string{"localhost"})
+	ca.IssueClient(rotated, "api-gateway")
+
+	option, stopClient, err := DialOption(clientConfig(rotated, "api-gateway"))
+	if err != nil {
+		t.Fatalf("DialOption: %v", err)
+	}
+	defer stopClient()
+	if err := check(t, listener, option); err == nil {
+		t.Fatal("expected the rotated client to be rejected before the reload")
+	}
+
+	later := time.Now().Add(time.Minute)
+	for _, name := range []string{"server.pem", "server-key.pem", "ca.pem"} {
+		data, _ := ioutil.ReadFile(filepath.Join(rotated, name))
+		ioutil.WriteFile(filepath.Join(dir, name), data, 0600)
+		os.Chtimes(filepath.Join(dir, name), later, later)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		err := check(t, listener, option)
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the server to reload its certificates, got %v", err)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"grpc-todo/models"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type userKey struct{}
+
+// ContextWithUser returns a copy of ctx carrying the authenticated user
+func ContextWithUser(ctx context.Context, user models.User) context.Context {
+	return context.WithValue(ctx, userKey{}, user)
+}
+
+// UserFromContext returns the user put in the context by the interceptors
+func UserFromContext(ctx context.Context) (models.User, bool) {
+	user, ok := ctx.Value(userKey{}).(models.User)
+	return user, ok
+}
+
+// RequireUser returns the authenticated user or an Unauthenticated status when the
+// call did not go through the interceptors
+func RequireUser(ctx context.Context) (models.User, error) {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return models.User{}, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	return user, nil
+}
+
+// UnaryServerInterceptor rejects calls without a valid bearer token and passes the user to the handler
+func UnaryServerInterceptor(validator TokenValidator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, validator)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of UnaryServerInterceptor
+func StreamServerInterceptor(validator TokenValidator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(stream.Context(), validator)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{stream, ctx})
+	}
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *authenticatedStream) Context() context.Context {
+	return stream.ctx
+}
+
+func authenticate(ctx context.Context, validator TokenValidator) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	const prefix = "bearer "
+	if len(values[0]) <= len(prefix) || !strings.EqualFold(values[0][:len(prefix)], prefix) {
+		return nil, status.Error(codes.Unauthenticated, "malformed authorization header")
+	}
+
+	user, err := validator.Validate(ctx, values[0][len(prefix):])
+	if errors.Is(err, ErrInvalidToken) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		// grpc-auth being unreachable is not the caller's fault
+		return nil, status.Convert(err).Err()
+	}
+
+	return ContextWithUser(ctx, user), nil
+}
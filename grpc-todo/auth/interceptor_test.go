@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testSecret = []byte("test-secret")
+
+// signToken builds a token the way grpc-auth's GenerateToken does
+func signToken(t *testing.T, secret []byte, method jwt.SigningMethod, expiresAt time.Time) string {
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"id":       42,
+		"username": "alice",
+		"exp":      expiresAt.Unix(),
+	})
+	key := interface{}(secret)
+	if method == jwt.SigningMethodNone {
+		key = jwt.UnsafeAllowNoneSignatureType
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	valid := signToken(t, testSecret, jwt.SigningMethodHS256, time.Now().Add(time.Hour))
+	cases := []struct {
+		name     string
+		header   string
+		wantCode codes.Code
+	}{
+		{name: "valid token", header: "Bearer " + valid, wantCode: codes.OK},
+		{name: "lowercase scheme", header: "bearer " + valid, wantCode: codes.OK},
+		{name: "missing header", wantCode: codes.Unauthenticated},
+		{name: "wrong scheme", header: "Basic " + valid, wantCode: codes.Unauthenticated},
+		{name: "garbage", header: "Bearer not-a-token", wantCode: codes.Unauthenticated},
+		{name: "other secret", header: "Bearer " + signToken(t, []byte("other"), jwt.SigningMethodHS256, time.Now().Add(time.Hour)), wantCode: codes.Unauthenticated},
+		{name: "expired", header: "Bearer " + signToken(t, testSecret, jwt.SigningMethodHS256, time.Now().Add(-time.Minute)), wantCode: codes.Unauthenticated},
+		{name: "unsigned", header: "Bearer " + signToken(t, testSecret, jwt.SigningMethodNone, time.Now().Add(time.Hour)), wantCode: codes.Unauthenticated},
+	}
+
+	interceptor := UnaryServerInterceptor(InitTokenValidator(testSecret))
+	for _, c := range cases {
+		ctx := context.Background()
+		if c.header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", c.header))
+		}
+
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			user, ok := UserFromContext(ctx)
+			if !ok || user.ID != 42 || user.Username != "alice" {
+				t.Fatalf("%s: unexpected user %+v", c.name, user)
+			}
+			return "ok", nil
+		}
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/todo.TodoService/GetTodos"}, handler)
+		if got := status.Code(err); got != c.wantCode {
+			t.Fatalf("%s: expected %v, got %v (%v)", c.name, c.wantCode, got, err)
+		}
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (stream *fakeServerStream) Context() context.Context {
+	return stream.ctx
+}
+
+func TestStreamServerInterceptorInjectsUser(t *testing.T) {
+	token := signToken(t, testSecret, jwt.SigningMethodHS256, time.Now().Add(time.Hour))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	interceptor := StreamServerInterceptor(InitTokenValidator(testSecret))
+
+	called := false
+	err := interceptor(nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		called = true
+		user, err := RequireUser(stream.Context())
+		if err != nil || user.ID != 42 {
+			t.Fatalf("unexpected user %+v: %v", user, err)
+		}
+		return nil
+	})
+	if err != nil || !called {
+		t.Fatalf("expected handler to run, got %v", err)
+	}
+
+	err = interceptor(nil, &fakeServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+		t.Fatal("handler must not run without a token")
+		return nil
+	})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"grpc-todo/models"
+	"sync"
+	"time"
+
+	"grpc-auth/authv2"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// validateTimeout bounds the ValidateToken call made for one request
+	validateTimeout = 2 * time.Second
+	// maxCachedTokens keeps the cache small, it is cleared when full
+	maxCachedTokens = 10000
+)
+
+type cachedUser struct {
+	user    models.User
+	expires time.Time
+}
+
+type authServiceValidator struct {
+	client   authv2.AuthServiceClient
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedUser
+}
+
+// InitAuthServiceValidator asks grpc-auth whether a token is valid, so tokens of revoked
+// sessions and deleted users are rejected. Valid tokens are cached for cacheTTL, which
+// is how long a revocation may take to reach grpc-todo; zero disables the cache.
+func InitAuthServiceValidator(client authv2.AuthServiceClient, cacheTTL time.Duration) TokenValidator {
+	return &authServiceValidator{
+		client:   client,
+		cacheTTL: cacheTTL,
+		cache:    map[string]cachedUser{},
+	}
+}
+
+func (validator *authServiceValidator) Validate(ctx context.Context, token string) (models.User, error) {
+	now := time.Now()
+	if user, ok := validator.cached(token, now); ok {
+		return user, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, validateTimeout)
+	defer cancel()
+
+	response, err := validator.client.ValidateToken(ctx, &authv2.ValidateTokenRequest{Token: token})
+	if status.Code(err) == codes.Unauthenticated {
+		return models.User{}, ErrInvalidToken
+	}
+	if err != nil {
+		return models.User{}, status.Error(codes.Unavailable, fmt.Sprintf("validate token: %v", status.Convert(err).Message()))
+	}
+
+	user := models.User{ID: response.Id, Username: response.Username}
+	validator.store(token, user, now)
+	return user, nil
+}
+
+func (validator *authServiceValidator) cached(token string, now time.Time) (models.User, bool) {
+	validator.mu.Lock()
+	defer validator.mu.Unlock()
+
+	entry, ok := validator.cache[token]
+	if !ok || now.After(entry.expires) {
+		delete(validator.cache, token)
+		return models.User{}, false
+	}
+	return entry.user, true
+}
+
+func (validator *authServiceValidator) store(token string, user models.User, now time.Time) {
+	if validator.cacheTTL <= 0 {
+		return
+	}
+
+	validator.mu.Lock()
+	defer validator.mu.Unlock()
+
+	if len(validator.cache) >= maxCachedTokens {
+		validator.cache = map[string]cachedUser{}
+	}
+	validator.cache[token] = cachedUser{user: user, expires: now.Add(validator.cacheTTL)}
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"grpc-auth/authv2"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeAuthService accepts the tokens in sessions until they are revoked
+type fakeAuthService struct {
+	authv2.UnimplementedAuthServiceServer
+
+	mu       sync.Mutex
+	sessions map[string]bool
+	calls    int
+	down     bool
+}
+
+func (s *fakeAuthService) ValidateToken(ctx context.Context, request *authv2.ValidateTokenRequest) (*authv2.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.down {
+		return nil, status.Error(codes.Unavailable, "connection refused")
+	}
+	if !s.sessions[request.Token] {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+	return &authv2.User{Id: 42, Username: "alice"}, nil
+}
+
+func (s *fakeAuthService) revoke(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, token)
+}
+
+func dialAuthService(t *testing.T, service *fakeAuthService) authv2.AuthServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	authv2.RegisterAuthServiceServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return authv2.NewAuthServiceClient(conn)
+}
+
+func callWithToken(interceptor grpc.UnaryServerInterceptor, token string) error {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+token))
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/todo.v2.TodoService/GetTodo"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, err := RequireUser(ctx); err != nil {
+			return nil, err
+		}
+		return "ok", nil
+	})
+	return err
+}
+
+func TestRevokedTokenRejected(t *testing.T) {
+	service := &fakeAuthService{sessions: map[string]bool{"laptop": true}}
+	interceptor := UnaryServerInterceptor(InitAuthServiceValidator(dialAuthService(t, service), 0))
+
+	if err := callWithToken(interceptor, "laptop"); err != nil {
+		t.Fatalf("expected the token to be accepted, got %v", err)
+	}
+
+	service.revoke("laptop")
+	if err := callWithToken(interceptor, "laptop"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected the revoked token to be rejected, got %v", err)
+	}
+}
+
+func TestAuthServiceValidatorCache(t *testing.T) {
+	service := &fakeAuthService{sessions: map[string]bool{"laptop": true}}
+	validator := InitAuthServiceValidator(dialAuthService(t, service), 50*time.Millisecond)
+	interceptor := UnaryServerInterceptor(validator)
+
+	callWithToken(interceptor, "laptop")
+	service.revoke("laptop")
+	if err := callWithToken(interceptor, "laptop"); err != nil {
+		t.Fatalf("expected the cached token to be accepted, got %v", err)
+	}
+	if service.calls != 1 {
+		t.Fatalf("expected a single ValidateToken call, got %d", service.calls)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if err := callWithToken(interceptor, "laptop"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected the revocation to apply once the cache expired, got %v", err)
+	}
+
+	// rejections are never cached
+	if err := callWithToken(interceptor, "laptop"); status.Code(err) != codes.Unauthenticated || service.calls != 3 {
+		t.Fatalf("expected another ValidateToken call, got %v after %d calls", err, service.calls)
+	}
+}
+
+func TestAuthServiceUnavailable(t *testing.T) {
+	service := &fakeAuthService{sessions: map[string]bool{"laptop": true}, down: true}
+	interceptor := UnaryServerInterceptor(InitAuthServiceValidator(dialAuthService(t, service), 0))
+
+	if err := callWithToken(interceptor, "laptop"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable while grpc-auth is down, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"grpc-todo/models"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, expired or not signed by grpc-auth
+var ErrInvalidToken = errors.New("invalid token")
+
+type tokenValidator struct {
+	secretKey []byte
+}
+
+// TokenValidator turns a bearer token issued by grpc-auth into the calling user
+type TokenValidator interface {
+	Validate(ctx context.Context, token string) (models.User, error)
+}
+
+// InitTokenValidator checks signatures and expiry only, so revoked sessions stay valid
+// until their token expires. Use InitAuthServiceValidator to honour revocation.
+func InitTokenValidator(secretKey []byte) TokenValidator {
+	return &tokenValidator{
+		secretKey,
+	}
+}
+
+func (validator *tokenValidator) Validate(ctx context.Context, tokenStr string) (models.User, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return validator.secretKey, nil
+	})
+	if err != nil || !token.Valid {
+		return models.User{}, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return models.User{}, ErrInvalidToken
+	}
+
+	// JSON numbers decode as float64
+	id, ok := claims["id"].(float64)
+	if !ok || id <= 0 {
+		return models.User{}, ErrInvalidToken
+	}
+	username, _ := claims["username"].(string)
+
+	return models.User{ID: int64(id), Username: username}, nil
+}
@@ -0,0 +1,82 @@
+package broker
+
+import (
+	"grpc-todo/models"
+	"log"
+	"sync"
+)
+
+// subscriberBuffer is how many events a watcher may lag behind before it is dropped
+const subscriberBuffer = 64
+
+type subscriber struct {
+	events chan models.TodoEvent
+}
+
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[*subscriber]struct{}
+}
+
+// Broker fans todo events out to the streams watching the owning user
+type Broker interface {
+	Publish(event models.TodoEvent)
+	Subscribe(userID int64) (<-chan models.TodoEvent, func())
+}
+
+func InitBroker() Broker {
+	return &broker{
+		subscribers: map[int64]map[*subscriber]struct{}{},
+	}
+}
+
+// Publish delivers the event to every subscriber of the todo's user without blocking.
+// A subscriber whose buffer is full is dropped and its channel closed.
+func (b *broker) Publish(event models.TodoEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers[event.Todo.UserID] {
+		select {
+		case sub.events <- event:
+		default:
+			log.Println("Dropping slow todo watcher of user", event.Todo.UserID)
+			b.remove(event.Todo.UserID, sub)
+		}
+	}
+}
+
+// Subscribe returns the user's event channel and a function that unsubscribes.
+// The channel is closed on unsubscribe or when the subscriber falls behind.
+func (b *broker) Subscribe(userID int64) (<-chan models.TodoEvent, func()) {
+	sub := &subscriber{events: make(chan models.TodoEvent, subscriberBuffer)}
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = map[*subscriber]struct{}{}
+	}
+	b.subscribers[userID][sub] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(userID, sub)
+	}
+
+	return sub.events, unsubscribe
+}
+
+// remove must be called with mu held
+func (b *broker) remove(userID int64, sub *subscriber) {
+	subs := b.subscribers[userID]
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.events)
+	if len(subs) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
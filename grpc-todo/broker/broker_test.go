@@ -0,0 +1,46 @@
+package broker
+
+import (
+	"grpc-todo/models"
+	"testing"
+)
+
+func TestPublishReachesOnlyOwner(t *testing.T) {
+	b := InitBroker()
+	mine, unsubscribeMine := b.Subscribe(1)
+	defer unsubscribeMine()
+	theirs, unsubscribeTheirs := b.Subscribe(2)
+	defer unsubscribeTheirs()
+
+	b.Publish(models.TodoEvent{Type: models.TodoCreated, Todo: models.Todo{ID: 10, UserID: 1}})
+
+	event := <-mine
+	if event.Todo.ID != 10 {
+		t.Fatalf("expected todo 10, got %d", event.Todo.ID)
+	}
+	select {
+	case event := <-theirs:
+		t.Fatalf("unexpected event for another user: %+v", event)
+	default:
+	}
+}
+
+func TestSlowSubscriberIsDropped(t *testing.T) {
+	b := InitBroker()
+	events, unsubscribe := b.Subscribe(1)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(models.TodoEvent{Type: models.TodoUpdated, Todo: models.Todo{ID: int64(i), UserID: 1}})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Fatalf("expected %d buffered events before eviction, got %d", subscriberBuffer, received)
+	}
+
+	// Unsubscribing after eviction must not close the channel twice.
+	unsubscribe()
+}
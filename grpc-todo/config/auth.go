@@ -0,0 +1,58 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"grpc-observability/interceptor"
+	"grpc-tls/tlsconfig"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultAuthAddr = "localhost:9000"
+	// defaultAuthCacheTTL is how long a revoked token may still be accepted
+	defaultAuthCacheTTL = 30 * time.Second
+)
+
+// ConnectAuthService dials grpc-auth at AUTH_GRPC_ADDR, which validates every bearer token.
+// AUTH_GRPC_CA_FILE, AUTH_GRPC_CERT_FILE and AUTH_GRPC_KEY_FILE turn on (mutual) TLS.
+// The returned function stops watching the certificate files.
+func ConnectAuthService() (*grpc.ClientConn, func()) {
+	addr := os.Getenv("AUTH_GRPC_ADDR")
+	if addr == "" {
+		addr = defaultAuthAddr
+	}
+
+	credentials, stopTLS, err := tlsconfig.DialOption(tlsconfig.Config{
+		CAFile:   os.Getenv("AUTH_GRPC_CA_FILE"),
+		CertFile: os.Getenv("AUTH_GRPC_CERT_FILE"),
+		KeyFile:  os.Getenv("AUTH_GRPC_KEY_FILE"),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	conn, err := grpc.Dial(addr, credentials, grpc.WithUnaryInterceptor(interceptor.UnaryClientInterceptor()))
+	if err != nil {
+		stopTLS()
+		panic(err)
+	}
+
+	return conn, stopTLS
+}
+
+// AuthCacheTTL returns how long validated tokens are cached, set with AUTH_CACHE_TTL
+func AuthCacheTTL() time.Duration {
+	if value := os.Getenv("AUTH_CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err == nil {
+			return ttl
+		}
+		log.Println("Invalid AUTH_CACHE_TTL, using the default", err)
+	}
+
+	return defaultAuthCacheTTL
+}
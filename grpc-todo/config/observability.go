@@ -0,0 +1,35 @@
+package config
+
+import (
+	"log"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"grpc-observability/interceptor"
+)
+
+// defaultMetricsAddr is where Prometheus scrapes this service unless METRICS_ADDR is set
+const defaultMetricsAddr = ":9100"
+
+// InitObserver builds the logging, metrics, tracing and recovery interceptors for the server
+func InitObserver() *interceptor.Observer {
+	observer, err := interceptor.New(interceptor.Config{
+		Registerer: prometheus.DefaultRegisterer,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return observer
+}
+
+// MetricsAddr returns the address the /metrics endpoint listens on
+func MetricsAddr() string {
+	if addr := os.Getenv("METRICS_ADDR"); addr != "" {
+		return addr
+	}
+
+	log.Println("METRICS_ADDR not set, serving metrics on", defaultMetricsAddr)
+	return defaultMetricsAddr
+}
@@ -1 +1,5 @@
-protoc --go_out=plugins=grpc:todo todo.proto
\ No newline at end of file
+go mod download github.com/grpc-ecosystem/grpc-gateway@v1.16.0
+GOOGLEAPIS=$(go env GOMODCACHE)/github.com/grpc-ecosystem/grpc-gateway@v1.16.0/third_party/googleapis
+protoc --go_out=plugins=grpc:todo todo.proto
+protoc -I . -I $GOOGLEAPIS --go_out=plugins=grpc,paths=source_relative:todov2 todo_v2.proto
+protoc -I . -I $GOOGLEAPIS --grpc-gateway_out=paths=source_relative:todov2 todo_v2.proto
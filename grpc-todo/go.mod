@@ -3,9 +3,25 @@ module grpc-todo
 go 1.15
 
 require (
-	github.com/golang/protobuf v1.4.3
+	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/golang/protobuf v1.5.2
+	github.com/grpc-ecosystem/grpc-gateway v1.16.0
 	github.com/jmoiron/sqlx v1.3.1
 	github.com/lib/pq v1.9.0
+	github.com/prometheus/client_golang v1.9.0
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
 	google.golang.org/grpc v1.35.0
-	google.golang.org/protobuf v1.25.0
+	google.golang.org/protobuf v1.26.0
+	grpc-auth v0.0.0-00010101000000-000000000000
+	grpc-observability v0.0.0-00010101000000-000000000000
+	grpc-tls v0.0.0-00010101000000-000000000000
+	ratelimit v0.0.0-00010101000000-000000000000
 )
+
+replace grpc-observability => ../grpc-observability
+
+replace grpc-tls => ../grpc-tls
+
+replace grpc-auth => ../grpc-auth
+
+replace ratelimit => ../ratelimit
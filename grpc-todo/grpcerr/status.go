@@ -0,0 +1,65 @@
+package grpcerr
+
+import (
+	"errors"
+	"grpc-todo/auth"
+	"grpc-todo/repository"
+	"grpc-todo/usecase"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Status converts an error of the use case or repository layer into a gRPC status error.
+// Unknown errors become Internal without exposing their message to the client.
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var validationErr *usecase.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return BadRequest(validationErr)
+	case errors.Is(err, repository.ErrTodoNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrTodoForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, auth.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	default:
+		log.Println("Internal error", err)
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+// BadRequest returns an InvalidArgument status carrying every field violation
+func BadRequest(validationErr *usecase.ValidationError) error {
+	st := status.New(codes.InvalidArgument, validationErr.Error())
+
+	details := &errdetails.BadRequest{}
+	for _, violation := range validationErr.Violations {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+
+	detailed, err := st.WithDetails(details)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// InvalidArgument rejects a single request field
+func InvalidArgument(field, description string) error {
+	return BadRequest(&usecase.ValidationError{
+		Violations: []usecase.FieldViolation{{Field: field, Description: description}},
+	})
+}
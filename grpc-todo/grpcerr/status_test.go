@@ -0,0 +1,48 @@
+package grpcerr
+
+import (
+	"errors"
+	"fmt"
+	"grpc-todo/repository"
+	"grpc-todo/usecase"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatus(t *testing.T) {
+	cases := []struct {
+		err  error
+		want codes.Code
+	}{
+		{err: repository.ErrTodoNotFound, want: codes.NotFound},
+		{err: fmt.Errorf("update: %w", repository.ErrTodoForbidden), want: codes.PermissionDenied},
+		{err: usecase.ErrTitleRequired, want: codes.InvalidArgument},
+		{err: status.Error(codes.Unauthenticated, "missing bearer token"), want: codes.Unauthenticated},
+		{err: errors.New("pq: connection refused"), want: codes.Internal},
+	}
+
+	for _, c := range cases {
+		if got := status.Code(Status(c.err)); got != c.want {
+			t.Fatalf("Status(%v): expected %v, got %v", c.err, c.want, got)
+		}
+	}
+
+	if st, _ := status.FromError(Status(errors.New("pq: connection refused"))); st.Message() != "internal error" {
+		t.Fatalf("internal errors must not leak, got %q", st.Message())
+	}
+}
+
+func TestBadRequestDetails(t *testing.T) {
+	st, _ := status.FromError(Status(usecase.ErrInvalidPageToken))
+
+	if len(st.Details()) != 1 {
+		t.Fatalf("expected one detail, got %v", st.Details())
+	}
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok || len(badRequest.FieldViolations) != 1 || badRequest.FieldViolations[0].Field != "page_token" {
+		t.Fatalf("expected page_token violation, got %v", st.Details()[0])
+	}
+}
@@ -2,34 +2,63 @@ package main
 
 import (
 	"fmt"
+	"grpc-todo/auth"
+	"grpc-todo/broker"
 	"grpc-todo/config"
 	"grpc-todo/repository"
 	"grpc-todo/todo"
+	"grpc-todo/todov2"
 	"grpc-todo/usecase"
 	"log"
 	"net"
 
+	"grpc-auth/authv2"
+	"grpc-observability/interceptor"
+	"grpc-tls/tlsconfig"
+
 	"google.golang.org/grpc"
 )
 
 func main() {
 	db := config.ConnectDB()
 	TodoRepository := repository.InitTodoRepository(db)
-	todoUsecase := usecase.InitUserUsecase(TodoRepository)
+	todoBroker := broker.InitBroker()
+	todoUsecase := usecase.InitUserUsecase(TodoRepository, todoBroker)
 
 	s := todo.InitServer(todoUsecase)
+	s2 := todov2.InitServer(todoUsecase)
+
+	authConn, stopAuthTLS := config.ConnectAuthService()
+	defer stopAuthTLS()
+	defer authConn.Close()
+	tokenValidator := auth.InitAuthServiceValidator(authv2.NewAuthServiceClient(authConn), config.AuthCacheTTL())
+	observer := config.InitObserver()
+	interceptor.ServeMetrics(config.MetricsAddr())
+
+	tlsOptions, stopTLS, err := tlsconfig.ServerOptions(tlsconfig.LoadConfig())
+	if err != nil {
+		log.Fatalf("failed to load TLS certificates: %v", err)
+	}
+	defer stopTLS()
 
-	grpcServer := grpc.NewServer()
+	// client certificate and token checks run after the observability chain so
+	// rejected calls are still logged and counted
+	options := append(observer.ServerOptions(), tlsOptions...)
+	grpcServer := grpc.NewServer(append(options,
+		grpc.ChainUnaryInterceptor(auth.UnaryServerInterceptor(tokenValidator)),
+		grpc.ChainStreamInterceptor(auth.StreamServerInterceptor(tokenValidator)),
+	)...)
 
 	todo.RegisterTodoServiceServer(grpcServer, &s)
+	todov2.RegisterTodoServiceServer(grpcServer, &s2)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", 3000))
 	if err != nil {
-		log.Println("failed to listen: %v", err)
+		log.Fatalf("failed to listen: %v", err)
 	}
 	fmt.Println("Listen to port 3000")
 
 	if err := grpcServer.Serve(lis); err != nil {
-		log.Println("failed to serve: %s", err)
+		log.Fatalf("failed to serve: %s", err)
 	}
 }
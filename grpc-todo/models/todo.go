@@ -1,8 +1,85 @@
 package models
 
+import "time"
+
+// TodoFieldTitle and TodoFieldDescription are the todo fields a client may update
+const (
+	TodoFieldTitle       = "title"
+	TodoFieldDescription = "description"
+)
+
+// TodoUpdatableFields lists every field accepted in an update mask
+var TodoUpdatableFields = []string{TodoFieldTitle, TodoFieldDescription}
+
 type Todo struct {
-	ID          int64  `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	UserID      int64  `json:"userID"`
+	ID          int64         `json:"id"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	UserID      int64         `json:"userID"`
+	CreatedAt   time.Time     `json:"createdAt"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+	Version     VersionVector `json:"version"`
+}
+
+// TodoEventType tells watchers what happened to a todo
+type TodoEventType int
+
+const (
+	TodoCreated TodoEventType = iota + 1
+	TodoUpdated
+	TodoDeleted
+)
+
+// TodoEvent is published to the user's watchers after every successful write.
+// Origin is the replica that made the change, ServerReplicaID for unary RPCs.
+type TodoEvent struct {
+	Type   TodoEventType
+	Todo   Todo
+	Origin string
+}
+
+// TodoChange is a single edit exchanged over SyncTodos. A zero Todo.ID creates a new todo.
+type TodoChange struct {
+	Todo    Todo
+	Deleted bool
+}
+
+// SyncOutcome is the result of reconciling a client change with the stored todo
+type SyncOutcome int
+
+const (
+	// SyncUnchanged means the server already had this exact version
+	SyncUnchanged SyncOutcome = iota + 1
+	// SyncApplied means the client change won and was stored
+	SyncApplied
+	// SyncRejected means the stored version won and the client must adopt it
+	SyncRejected
+)
+
+// ResolveTodoChange decides between the stored todo and an incoming change for it.
+// A change whose version descends from the stored one wins, a stale change loses, and
+// concurrent edits fall back to last-writer-wins on UpdatedAt with ties going to the server.
+// write reports whether resolved differs from what is stored.
+func ResolveTodoChange(current Todo, incoming TodoChange) (resolved TodoChange, outcome SyncOutcome, write bool) {
+	incoming.Todo.ID = current.ID
+	incoming.Todo.UserID = current.UserID
+	incoming.Todo.CreatedAt = current.CreatedAt
+
+	switch incoming.Todo.Version.Compare(current.Version) {
+	case VersionEqual:
+		return TodoChange{Todo: current}, SyncUnchanged, false
+	case VersionAfter:
+		return incoming, SyncApplied, true
+	case VersionBefore:
+		return TodoChange{Todo: current}, SyncRejected, false
+	}
+
+	merged := current.Version.Merge(incoming.Todo.Version)
+	if incoming.Todo.UpdatedAt.After(current.UpdatedAt) {
+		incoming.Todo.Version = merged
+		return incoming, SyncApplied, true
+	}
+
+	current.Version = merged
+	return TodoChange{Todo: current}, SyncRejected, true
 }
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVersionVectorCompare(t *testing.T) {
+	cases := []struct {
+		a    VersionVector
+		b    VersionVector
+		want VersionOrder
+	}{
+		{a: VersionVector{}, b: VersionVector{}, want: VersionEqual},
+		{a: VersionVector{"server": 1}, b: VersionVector{"server": 1}, want: VersionEqual},
+		{a: VersionVector{"server": 1}, b: VersionVector{"server": 1, "phone": 1}, want: VersionBefore},
+		{a: VersionVector{"server": 2, "phone": 1}, b: VersionVector{"server": 1, "phone": 1}, want: VersionAfter},
+		{a: VersionVector{"server": 2}, b: VersionVector{"server": 1, "phone": 1}, want: VersionConcurrent},
+		{a: VersionVector{"phone": 0}, b: VersionVector{}, want: VersionEqual},
+	}
+
+	for _, c := range cases {
+		if got := c.a.Compare(c.b); got != c.want {
+			t.Fatalf("%v.Compare(%v): expected %v, got %v", c.a, c.b, c.want, got)
+		}
+	}
+}
+
+func TestResolveTodoChange(t *testing.T) {
+	earlier := time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+	current := Todo{
+		ID:        7,
+		Title:     "server title",
+		UserID:    3,
+		CreatedAt: earlier,
+		UpdatedAt: earlier,
+		Version:   VersionVector{"server": 2, "phone": 1},
+	}
+
+	cases := []struct {
+		name        string
+		incoming    TodoChange
+		wantOutcome SyncOutcome
+		wantWrite   bool
+		wantTitle   string
+		wantVersion VersionVector
+	}{
+		{
+			name:        "retry of stored version",
+			incoming:    TodoChange{Todo: Todo{Title: "server title", Version: VersionVector{"server": 2, "phone": 1}}},
+			wantOutcome: SyncUnchanged,
+			wantTitle:   "server title",
+			wantVersion: VersionVector{"server": 2, "phone": 1},
+		},
+		{
+			name:        "descendant edit wins",
+			incoming:    TodoChange{Todo: Todo{Title: "phone title", UpdatedAt: earlier, Version: VersionVector{"server": 2, "phone": 2}}},
+			wantOutcome: SyncApplied,
+			wantWrite:   true,
+			wantTitle:   "phone title",
+			wantVersion: VersionVector{"server": 2, "phone": 2},
+		},
+		{
+			name:        "stale edit loses",
+			incoming:    TodoChange{Todo: Todo{Title: "old title", UpdatedAt: later, Version: VersionVector{"server": 1, "phone": 1}}},
+			wantOutcome: SyncRejected,
+			wantTitle:   "server title",
+			wantVersion: VersionVector{"server": 2, "phone": 1},
+		},
+		{
+			name:        "concurrent newer edit wins",
+			incoming:    TodoChange{Todo: Todo{Title: "laptop title", UpdatedAt: later, Version: VersionVector{"server": 1, "laptop": 1}}},
+			wantOutcome: SyncApplied,
+			wantWrite:   true,
+			wantTitle:   "laptop title",
+			wantVersion: VersionVector{"server": 2, "phone": 1, "laptop": 1},
+		},
+		{
+			name:        "concurrent edit at same time loses",
+			incoming:    TodoChange{Todo: Todo{Title: "laptop title", UpdatedAt: earlier, Version: VersionVector{"server": 1, "laptop": 1}}},
+			wantOutcome: SyncRejected,
+			wantWrite:   true,
+			wantTitle:   "server title",
+			wantVersion: VersionVector{"server": 2, "phone": 1, "laptop": 1},
+		},
+	}
+
+	for _, c := range cases {
+		resolved, outcome, write := ResolveTodoChange(current, c.incoming)
+		if outcome != c.wantOutcome || write != c.wantWrite {
+			t.Fatalf("%s: expected outcome %v write %v, got %v %v", c.name, c.wantOutcome, c.wantWrite, outcome, write)
+		}
+		if resolved.Todo.Title != c.wantTitle {
+			t.Fatalf("%s: expected title %q, got %q", c.name, c.wantTitle, resolved.Todo.Title)
+		}
+		if resolved.Todo.Version.Compare(c.wantVersion) != VersionEqual {
+			t.Fatalf("%s: expected version %v, got %v", c.name, c.wantVersion, resolved.Todo.Version)
+		}
+		if resolved.Todo.ID != current.ID || resolved.Todo.UserID != current.UserID {
+			t.Fatalf("%s: resolved todo lost its identity: %+v", c.name, resolved.Todo)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package models
+
+// User is the caller identified by the bearer token of a request
+type User struct {
+	ID       int64
+	Username string
+}
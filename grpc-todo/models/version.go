@@ -0,0 +1,111 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// ServerReplicaID is the version vector entry bumped by writes that do not come from a sync client
+const ServerReplicaID = "server"
+
+// VersionOrder is the causal relation between two version vectors
+type VersionOrder int
+
+const (
+	VersionEqual VersionOrder = iota
+	VersionBefore
+	VersionAfter
+	VersionConcurrent
+)
+
+// VersionVector counts the edits each replica has made to a todo
+type VersionVector map[string]int64
+
+// Increment returns a copy of v with the replica's counter bumped
+func (v VersionVector) Increment(replicaID string) VersionVector {
+	result := v.copy()
+	result[replicaID]++
+
+	return result
+}
+
+// Merge returns the element-wise maximum of v and other
+func (v VersionVector) Merge(other VersionVector) VersionVector {
+	result := v.copy()
+	for replicaID, counter := range other {
+		if counter > result[replicaID] {
+			result[replicaID] = counter
+		}
+	}
+
+	return result
+}
+
+// Compare reports whether v happened before, after, concurrently with or is equal to other
+func (v VersionVector) Compare(other VersionVector) VersionOrder {
+	less, greater := false, false
+	for replicaID, counter := range v {
+		if counter > other[replicaID] {
+			greater = true
+		}
+	}
+	for replicaID, counter := range other {
+		if counter > v[replicaID] {
+			less = true
+		}
+	}
+
+	switch {
+	case less && greater:
+		return VersionConcurrent
+	case less:
+		return VersionBefore
+	case greater:
+		return VersionAfter
+	default:
+		return VersionEqual
+	}
+}
+
+func (v VersionVector) copy() VersionVector {
+	result := VersionVector{}
+	for replicaID, counter := range v {
+		result[replicaID] = counter
+	}
+
+	return result
+}
+
+// Value stores the vector as a JSON object
+func (v VersionVector) Value() (driver.Value, error) {
+	if v == nil {
+		v = VersionVector{}
+	}
+
+	return json.Marshal(v)
+}
+
+// Scan reads a vector stored as a JSON object
+func (v *VersionVector) Scan(src interface{}) error {
+	var raw []byte
+	switch value := src.(type) {
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	case nil:
+		*v = VersionVector{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into VersionVector", src)
+	}
+
+	result := VersionVector{}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return err
+	}
+	*v = result
+
+	return nil
+}
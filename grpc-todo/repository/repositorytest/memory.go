@@ -0,0 +1,140 @@
+// Package repositorytest provides an in-memory TodoRepository for tests of the layers above it
+package repositorytest
+
+import (
+	"grpc-todo/models"
+	"grpc-todo/repository"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryRepository struct {
+	mu     sync.Mutex
+	nextID int64
+	todos  map[int64]models.Todo
+}
+
+// InitMemoryRepository returns an empty repository that mirrors the postgres one's behaviour
+func InitMemoryRepository() repository.TodoRepository {
+	return &memoryRepository{
+		todos: map[int64]models.Todo{},
+	}
+}
+
+func (r *memoryRepository) CreateTodo(todo models.Todo) (models.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	todo.ID = r.nextID
+	todo.CreatedAt = time.Now()
+	todo.Version = todo.Version.Merge(nil)
+	r.todos[todo.ID] = todo
+
+	return todo, nil
+}
+
+func (r *memoryRepository) GetTodo(userID int64, id int64) (models.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.lookup(userID, id)
+}
+
+func (r *memoryRepository) GetTodos(userID int64) ([]models.Todo, error) {
+	return r.ListTodos(userID, 0, math.MaxInt32)
+}
+
+func (r *memoryRepository) ListTodos(userID int64, afterID int64, limit int) ([]models.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	todos := []models.Todo{}
+	for _, todo := range r.todos {
+		if todo.UserID == userID && todo.ID > afterID {
+			todos = append(todos, todo)
+		}
+	}
+	sort.Slice(todos, func(i, j int) bool { return todos[i].ID < todos[j].ID })
+	if len(todos) > limit {
+		todos = todos[:limit]
+	}
+
+	return todos, nil
+}
+
+func (r *memoryRepository) UpdateTodo(userID int64, todo models.Todo, fields []string) (models.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated, err := r.lookup(userID, todo.ID)
+	if err != nil {
+		return models.Todo{}, err
+	}
+	for _, field := range fields {
+		switch field {
+		case models.TodoFieldTitle:
+			updated.Title = todo.Title
+		case models.TodoFieldDescription:
+			updated.Description = todo.Description
+		}
+	}
+	updated.UpdatedAt = time.Now()
+	updated.Version = updated.Version.Increment(models.ServerReplicaID)
+	r.todos[updated.ID] = updated
+
+	return updated, nil
+}
+
+func (r *memoryRepository) DeleteTodo(userID int64, id int64) (models.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted, err := r.lookup(userID, id)
+	if err != nil {
+		return models.Todo{}, err
+	}
+	delete(r.todos, id)
+
+	return deleted, nil
+}
+
+func (r *memoryRepository) SyncTodo(userID int64, change models.TodoChange) (models.TodoChange, models.SyncOutcome, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, err := r.lookup(userID, change.Todo.ID)
+	if err == repository.ErrTodoNotFound {
+		gone := models.Todo{ID: change.Todo.ID, UserID: userID}
+		return models.TodoChange{Todo: gone, Deleted: true}, models.SyncRejected, false, nil
+	}
+	if err != nil {
+		return models.TodoChange{}, 0, false, err
+	}
+
+	resolved, outcome, written := models.ResolveTodoChange(current, change)
+	if written {
+		if resolved.Deleted {
+			delete(r.todos, current.ID)
+		} else {
+			r.todos[current.ID] = resolved.Todo
+		}
+	}
+
+	return resolved, outcome, written, nil
+}
+
+// lookup must be called with mu held
+func (r *memoryRepository) lookup(userID int64, id int64) (models.Todo, error) {
+	todo, ok := r.todos[id]
+	if !ok {
+		return models.Todo{}, repository.ErrTodoNotFound
+	}
+	if todo.UserID != userID {
+		return models.Todo{}, repository.ErrTodoForbidden
+	}
+
+	return todo, nil
+}
@@ -2,21 +2,36 @@ package repository
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"grpc-todo/models"
 	"log"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 )
 
+var (
+	// ErrTodoNotFound is returned when no todo matches the given id
+	ErrTodoNotFound = errors.New("todo not found")
+	// ErrTodoForbidden is returned when the todo belongs to another user
+	ErrTodoForbidden = errors.New("todo belongs to another user")
+)
+
+const todoColumns = `id, title, description, user_id, created_at, updated_at, version`
+
 type todoRepository struct {
 	db *sqlx.DB
 }
 
 type TodoRepository interface {
-	CreateTodo(todo models.Todo) (bool, error)
+	CreateTodo(todo models.Todo) (models.Todo, error)
+	GetTodo(userID int64, id int64) (models.Todo, error)
 	GetTodos(userID int64) ([]models.Todo, error)
-	UpdateTodo(todo models.Todo) (bool, error)
-	DeleteTodo(id int64) (bool, error)
+	ListTodos(userID int64, afterID int64, limit int) ([]models.Todo, error)
+	UpdateTodo(userID int64, todo models.Todo, fields []string) (models.Todo, error)
+	DeleteTodo(userID int64, id int64) (models.Todo, error)
+	SyncTodo(userID int64, change models.TodoChange) (models.TodoChange, models.SyncOutcome, bool, error)
 }
 
 func InitTodoRepository(db *sqlx.DB) TodoRepository {
@@ -25,57 +40,98 @@ func InitTodoRepository(db *sqlx.DB) TodoRepository {
 	}
 }
 
-func (todoRepository *todoRepository) CreateTodo(todo models.Todo) (bool, error) {
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTodo(row rowScanner) (models.Todo, error) {
+	var todo models.Todo
+	err := row.Scan(
+		&todo.ID,
+		&todo.Title,
+		&todo.Description,
+		&todo.UserID,
+		&todo.CreatedAt,
+		&todo.UpdatedAt,
+		&todo.Version,
+	)
+
+	return todo, err
+}
+
+func (todoRepository *todoRepository) CreateTodo(todo models.Todo) (models.Todo, error) {
 	var err error
-	var result bool
+	var created models.Todo
 
 	tx, errTx := todoRepository.db.Begin()
 	if errTx != nil {
 		log.Println("Error create todo: ", errTx)
-	} else {
-		err = insertTodo(tx, todo)
-		if err != nil {
-			log.Println("Error create todo: ", err)
-		}
+		return models.Todo{}, errTx
 	}
 
+	created, err = insertTodo(tx, todo)
 	if err == nil {
-		result = true
-		tx.Commit()
+		err = tx.Commit()
 	} else {
-		result = false
 		tx.Rollback()
+	}
+
+	if err != nil {
 		log.Println("Error create todo: ", err)
+		return models.Todo{}, err
 	}
 
-	return result, err
+	return created, nil
 }
 
-func insertTodo(tx *sql.Tx, todo models.Todo) error {
-	_, err := tx.Exec(`
+func insertTodo(tx *sql.Tx, todo models.Todo) (models.Todo, error) {
+	row := tx.QueryRow(`
 	INSERT INTO todos (
 		title,
 		description,
-		user_id
+		user_id,
+		updated_at,
+		version
 	)
 	VALUES(
 		$1,
 		$2,
-		$3
-	);
+		$3,
+		$4,
+		$5
+	)
+	RETURNING `+todoColumns+`;
 	`,
 		todo.Title,
 		todo.Description,
 		todo.UserID,
+		todo.UpdatedAt,
+		todo.Version,
 	)
 
-	return err
+	return scanTodo(row)
+}
+
+func (todoRepository *todoRepository) GetTodo(userID int64, id int64) (models.Todo, error) {
+	row := todoRepository.db.QueryRow(`
+		SELECT `+todoColumns+` FROM todos WHERE id=$1 AND user_id=$2;
+	`, id, userID)
+
+	todo, err := scanTodo(row)
+	if err == sql.ErrNoRows {
+		return models.Todo{}, missingTodoError(todoRepository.db, id)
+	}
+	if err != nil {
+		log.Println("Error get todo", err)
+		return models.Todo{}, err
+	}
+
+	return todo, nil
 }
 
 func (todoRepository *todoRepository) GetTodos(userID int64) ([]models.Todo, error) {
-	var todos []models.Todo
 	rows, err := todoRepository.db.Query(`
-		SELECT id, title, description FROM todos WHERE user_id=$1;
+		SELECT `+todoColumns+` FROM todos WHERE user_id=$1 ORDER BY id;
 	`, userID)
 
 	if err != nil {
@@ -83,10 +139,32 @@ func (todoRepository *todoRepository) GetTodos(userID int64) ([]models.Todo, err
 		return []models.Todo{}, err
 	}
 
+	return scanTodos(rows)
+}
+
+// ListTodos returns at most limit todos of the user with an id greater than afterID, ordered by id
+func (todoRepository *todoRepository) ListTodos(userID int64, afterID int64, limit int) ([]models.Todo, error) {
+	rows, err := todoRepository.db.Query(`
+		SELECT `+todoColumns+` FROM todos
+		WHERE user_id=$1 AND id>$2
+		ORDER BY id
+		LIMIT $3;
+	`, userID, afterID, limit)
+
+	if err != nil {
+		log.Println("Error list todos", err)
+		return []models.Todo{}, err
+	}
+
+	return scanTodos(rows)
+}
+
+func scanTodos(rows *sql.Rows) ([]models.Todo, error) {
+	todos := []models.Todo{}
+
 	defer rows.Close()
 	for rows.Next() {
-		var todo models.Todo
-		err = rows.Scan(&(todo.ID), &(todo.Title), &(todo.Description))
+		todo, err := scanTodo(rows)
 		if err != nil {
 			log.Println("Error get todos", err)
 			return []models.Todo{}, err
@@ -95,81 +173,199 @@ func (todoRepository *todoRepository) GetTodos(userID int64) ([]models.Todo, err
 		todos = append(todos, todo)
 	}
 
-	return todos, nil
+	return todos, rows.Err()
 }
 
-func (todoRepository *todoRepository) UpdateTodo(todo models.Todo) (bool, error) {
+// UpdateTodo writes only the given fields of the user's todo, bumps updated_at and the server's version counter
+func (todoRepository *todoRepository) UpdateTodo(userID int64, todo models.Todo, fields []string) (models.Todo, error) {
 	var err error
-	var result bool
+	var updated models.Todo
 
 	tx, errTx := todoRepository.db.Begin()
 	if errTx != nil {
 		log.Println("Error update todo: ", errTx)
-	} else {
-		err = updateTodo(tx, todo)
-		if err != nil {
-			log.Println("Error update todo: ", err)
-		}
+		return models.Todo{}, errTx
 	}
 
+	updated, err = updateTodo(tx, userID, todo, fields)
+	if err == sql.ErrNoRows {
+		err = missingTodoError(tx, todo.ID)
+	}
 	if err == nil {
-		result = true
-		tx.Commit()
+		err = tx.Commit()
 	} else {
-		result = false
 		tx.Rollback()
+	}
+
+	if err == ErrTodoNotFound || err == ErrTodoForbidden {
+		return models.Todo{}, err
+	}
+	if err != nil {
 		log.Println("Error update todo: ", err)
+		return models.Todo{}, err
 	}
 
-	return result, err
+	return updated, nil
 }
 
-func updateTodo(tx *sql.Tx, todo models.Todo) error {
-	_, err := tx.Exec(`
+func updateTodo(tx *sql.Tx, userID int64, todo models.Todo, fields []string) (models.Todo, error) {
+	sets := []string{
+		"updated_at=now()",
+		"version=version || jsonb_build_object('" + models.ServerReplicaID + "', COALESCE((version->>'" + models.ServerReplicaID + "')::bigint, 0) + 1)",
+	}
+	args := []interface{}{}
+	for _, field := range fields {
+		switch field {
+		case models.TodoFieldTitle:
+			args = append(args, todo.Title)
+		case models.TodoFieldDescription:
+			args = append(args, todo.Description)
+		default:
+			return models.Todo{}, fmt.Errorf("unknown todo field %q", field)
+		}
+		sets = append(sets, fmt.Sprintf("%s=$%d", field, len(args)))
+	}
+	args = append(args, todo.ID, userID)
+
+	row := tx.QueryRow(`
 	UPDATE todos
-	SET
-		title=$1,
-		description=$2
-	WHERE id=$3
-	
-	`,
-		todo.Title,
-		todo.Description,
-		todo.ID,
-	)
+	SET `+strings.Join(sets, ", ")+`
+	WHERE id=$`+fmt.Sprint(len(args)-1)+` AND user_id=$`+fmt.Sprint(len(args))+`
+	RETURNING `+todoColumns+`;
+	`, args...)
 
-	return err
+	return scanTodo(row)
 }
 
-func (todoRepository *todoRepository) DeleteTodo(id int64) (bool, error) {
+func (todoRepository *todoRepository) DeleteTodo(userID int64, id int64) (models.Todo, error) {
 	var err error
-	var result bool
+	var deleted models.Todo
 
 	tx, errTx := todoRepository.db.Begin()
 	if errTx != nil {
-		log.Println("Error to delete todo", err)
-	} else {
-		err = deleteTodo(tx, id)
+		log.Println("Error to delete todo", errTx)
+		return models.Todo{}, errTx
 	}
 
+	deleted, err = deleteTodo(tx, userID, id)
+	if err == sql.ErrNoRows {
+		err = missingTodoError(tx, id)
+	}
 	if err == nil {
-		result = true
-		tx.Commit()
+		err = tx.Commit()
 	} else {
-		result = false
 		tx.Rollback()
+	}
+
+	if err == ErrTodoNotFound || err == ErrTodoForbidden {
+		return models.Todo{}, err
+	}
+	if err != nil {
 		log.Println("Error to delete todo", err)
+		return models.Todo{}, err
 	}
 
-	return result, err
+	return deleted, nil
 }
 
-func deleteTodo(tx *sql.Tx, id int64) error {
-	_, err := tx.Exec(`
+func deleteTodo(tx *sql.Tx, userID int64, id int64) (models.Todo, error) {
+	row := tx.QueryRow(`
 		DELETE FROM todos
-		WHERE id=$1
-	`, id,
+		WHERE id=$1 AND user_id=$2
+		RETURNING `+todoColumns+`
+	`, id, userID,
 	)
 
-	return err
+	return scanTodo(row)
+}
+
+// SyncTodo reconciles a change from a sync client with the user's stored todo under a row lock.
+// A change for a todo that no longer exists is answered with a deletion.
+// written reports whether the stored todo changed, which a server win can also do.
+func (todoRepository *todoRepository) SyncTodo(userID int64, change models.TodoChange) (resolved models.TodoChange, outcome models.SyncOutcome, written bool, err error) {
+	tx, err := todoRepository.db.Begin()
+	if err != nil {
+		log.Println("Error sync todo: ", err)
+		return models.TodoChange{}, 0, false, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRow(`
+		SELECT `+todoColumns+` FROM todos
+		WHERE id=$1 AND user_id=$2
+		FOR UPDATE;
+	`, change.Todo.ID, userID)
+
+	current, err := scanTodo(row)
+	if err == sql.ErrNoRows {
+		if err = missingTodoError(tx, change.Todo.ID); err != ErrTodoNotFound {
+			return models.TodoChange{}, 0, false, err
+		}
+		gone := models.Todo{ID: change.Todo.ID, UserID: userID}
+		return models.TodoChange{Todo: gone, Deleted: true}, models.SyncRejected, false, nil
+	}
+	if err != nil {
+		log.Println("Error sync todo: ", err)
+		return models.TodoChange{}, 0, false, err
+	}
+
+	resolved, outcome, written = models.ResolveTodoChange(current, change)
+	if written {
+		if resolved.Deleted {
+			_, err = deleteTodo(tx, userID, current.ID)
+		} else {
+			resolved.Todo, err = replaceTodo(tx, resolved.Todo)
+		}
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		log.Println("Error sync todo: ", err)
+		return models.TodoChange{}, 0, false, err
+	}
+
+	return resolved, outcome, written, nil
+}
+
+func replaceTodo(tx *sql.Tx, todo models.Todo) (models.Todo, error) {
+	row := tx.QueryRow(`
+	UPDATE todos
+	SET
+		title=$1,
+		description=$2,
+		updated_at=$3,
+		version=$4
+	WHERE id=$5 AND user_id=$6
+	RETURNING `+todoColumns+`;
+	`,
+		todo.Title,
+		todo.Description,
+		todo.UpdatedAt,
+		todo.Version,
+		todo.ID,
+		todo.UserID,
+	)
+
+	return scanTodo(row)
+}
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// missingTodoError tells a todo that does not exist from one owned by someone else,
+// after a query scoped to the caller matched no row
+func missingTodoError(q queryer, id int64) error {
+	var exists bool
+	err := q.QueryRow(`SELECT EXISTS(SELECT 1 FROM todos WHERE id=$1)`, id).Scan(&exists)
+	if err != nil {
+		log.Println("Error check todo", err)
+		return err
+	}
+	if exists {
+		return ErrTodoForbidden
+	}
+
+	return ErrTodoNotFound
 }
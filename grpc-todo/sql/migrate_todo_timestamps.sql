@@ -0,0 +1,6 @@
+-- Adds creation and modification times to todos, required by the v2 TodoService.
+ALTER TABLE todos
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS todos_user_id_id_idx ON todos (user_id, id);
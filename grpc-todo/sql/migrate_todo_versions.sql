@@ -0,0 +1,3 @@
+-- Adds per-replica edit counters used by SyncTodos to detect conflicting edits.
+ALTER TABLE todos
+    ADD COLUMN IF NOT EXISTS version JSONB NOT NULL DEFAULT '{"server": 1}';
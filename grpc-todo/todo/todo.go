@@ -2,7 +2,9 @@ package todo
 
 import (
 	context "context"
-	"fmt"
+	"encoding/json"
+	"grpc-todo/auth"
+	"grpc-todo/grpcerr"
 	"grpc-todo/models"
 	"grpc-todo/usecase"
 	"log"
@@ -20,52 +22,78 @@ func InitServer(todoUsecase usecase.TodoUsecase) Server {
 
 func (s *Server) CreateTodo(ctx context.Context, request *CreateTodoRequest) (*CreateTodoResponse, error) {
 	log.Println("Create todo")
+	user, err := auth.RequireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	todo := models.Todo{
 		Title:       request.Title,
 		Description: request.Description,
-		UserID:      request.UserID,
+		UserID:      user.ID,
 	}
-	_, err := s.todoUsecase.CreateTodo(todo)
+	_, err = s.todoUsecase.CreateTodo(todo)
 	if err != nil {
 		log.Println("Error create todo", err)
-		return &CreateTodoResponse{Success: false, Message: fmt.Sprintf("%v", err)}, err
+		return nil, grpcerr.Status(err)
 	}
 	return &CreateTodoResponse{Success: true, Message: "Success to create todo"}, nil
 }
 
 func (s *Server) GetTodos(ctx context.Context, request *GetTodosRequest) (*GetTodosResponse, error) {
 	log.Println("Get todo")
-	todos, err := s.todoUsecase.GetTodos(request.UserID)
+	user, err := auth.RequireUser(ctx)
 	if err != nil {
-		log.Println("Error create todo", err)
-		return &GetTodosResponse{Success: false, Message: fmt.Sprintf("%v", err)}, err
+		return nil, err
 	}
-	return &GetTodosResponse{Success: true, Message: "Success to get todos", Data: todos}, nil
+
+	todos, err := s.todoUsecase.GetTodos(user.ID)
+	if err != nil {
+		log.Println("Error get todos", err)
+		return nil, grpcerr.Status(err)
+	}
+
+	data, err := json.Marshal(todos)
+	if err != nil {
+		log.Println("Error encode todos", err)
+		return nil, grpcerr.Status(err)
+	}
+	return &GetTodosResponse{Success: true, Message: "Success to get todos", Data: string(data)}, nil
 }
 
 func (s *Server) UpdateTodo(ctx context.Context, request *UpdateTodoRequest) (*UpdateTodoResponse, error) {
 	log.Println("Update todo")
+	user, err := auth.RequireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	todo := models.Todo{
 		Title:       request.Title,
 		Description: request.Description,
 		ID:          request.Id,
 	}
-	_, err := s.todoUsecase.UpdateTodo(todo)
+	_, err = s.todoUsecase.UpdateTodo(user.ID, todo, models.TodoUpdatableFields)
 	if err != nil {
 		log.Println("Error update todo", err)
-		return &UpdateTodoResponse{Success: false, Message: "Failed to update"}, err
+		return nil, grpcerr.Status(err)
 	}
 
-	return &UpdateTodoResponse{Success: true, Message: "Success to update todo"}, err
+	return &UpdateTodoResponse{Success: true, Message: "Success to update todo"}, nil
 }
 
 func (s *Server) DeleteTodo(ctx context.Context, request *DeleteTodoRequest) (*DeleteTodoResponse, error) {
 	log.Println("Delete todo")
-	_, err := s.todoUsecase.DeleteTodo(request.Id)
+	user, err := auth.RequireUser(ctx)
 	if err != nil {
-		log.Println("Error update todo", err)
-		return &DeleteTodoResponse{Success: false, Message: "Failed to delete"}, err
+		return nil, err
+	}
+
+	_, err = s.todoUsecase.DeleteTodo(user.ID, request.Id)
+	if err != nil {
+		log.Println("Error delete todo", err)
+		return nil, grpcerr.Status(err)
 	}
 
-	return &DeleteTodoResponse{Success: true, Message: "Success to delete todo"}, err
+	return &DeleteTodoResponse{Success: true, Message: "Success to delete todo"}, nil
 }
@@ -0,0 +1,215 @@
+// Package todoclient is the Go client of grpc-todo. It wraps the generated v2
+// TodoServiceClient with per-call deadlines, bearer token authentication,
+// retries of idempotent reads and typed results.
+package todoclient
+
+import (
+	"context"
+	"grpc-todo/models"
+	"grpc-todo/todov2"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+const (
+	defaultTimeout        = 5 * time.Second
+	defaultMaxAttempts    = 4
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 2 * time.Second
+)
+
+// TokenSource returns the bearer token to attach to a call
+type TokenSource func(ctx context.Context) (string, error)
+
+// StaticToken always attaches token, as returned by grpc-auth's Login
+func StaticToken(token string) TokenSource {
+	return func(context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+type Config struct {
+	// Timeout is the deadline of each attempt, the caller's context bounds the whole call
+	Timeout time.Duration
+	// MaxAttempts caps how often an idempotent call is tried when the server is unavailable
+	MaxAttempts int
+	// InitialBackoff doubles after every failed attempt up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Token is attached as the authorization header of every call when set
+	Token TokenSource
+}
+
+type todoClient struct {
+	client todov2.TodoServiceClient
+	conn   *grpc.ClientConn
+	config Config
+}
+
+// TodoClient calls grpc-todo for the user of the configured token.
+// GetTodo, ListTodos and GetTodos are retried while the server is unavailable.
+type TodoClient interface {
+	CreateTodo(ctx context.Context, title, description string) (models.Todo, error)
+	GetTodo(ctx context.Context, id int64) (models.Todo, error)
+	ListTodos(ctx context.Context, pageSize int, pageToken string) ([]models.Todo, string, error)
+	GetTodos(ctx context.Context) ([]models.Todo, error)
+	UpdateTodo(ctx context.Context, todo models.Todo, fields ...string) (models.Todo, error)
+	DeleteTodo(ctx context.Context, id int64) error
+	Close() error
+}
+
+// Dial connects to grpc-todo at target, the connection is closed by Close.
+// Without transport options in opts the connection is plaintext.
+func Dial(target string, config Config, opts ...grpc.DialOption) (TodoClient, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithInsecure()}
+	}
+
+	conn, err := grpc.Dial(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	client := InitTodoClient(conn, config).(*todoClient)
+	client.conn = conn
+	return client, nil
+}
+
+// InitTodoClient uses an existing connection, which Close leaves open
+func InitTodoClient(conn *grpc.ClientConn, config Config) TodoClient {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+
+	return &todoClient{
+		client: todov2.NewTodoServiceClient(conn),
+		config: config,
+	}
+}
+
+func (c *todoClient) CreateTodo(ctx context.Context, title, description string) (models.Todo, error) {
+	var todo *todov2.Todo
+	err := c.call(ctx, func(ctx context.Context) error {
+		var err error
+		todo, err = c.client.CreateTodo(ctx, &todov2.CreateTodoRequest{Title: title, Description: description})
+		return err
+	})
+	if err != nil {
+		return models.Todo{}, err
+	}
+	return todov2.FromProto(todo), nil
+}
+
+func (c *todoClient) GetTodo(ctx context.Context, id int64) (models.Todo, error) {
+	var todo *todov2.Todo
+	err := c.retry(ctx, func(ctx context.Context) error {
+		var err error
+		todo, err = c.client.GetTodo(ctx, &todov2.GetTodoRequest{Id: id})
+		return err
+	})
+	if err != nil {
+		return models.Todo{}, err
+	}
+	return todov2.FromProto(todo), nil
+}
+
+// ListTodos returns one page and the token of the next, which is empty on the last page.
+// A zero pageSize uses the server default.
+func (c *todoClient) ListTodos(ctx context.Context, pageSize int, pageToken string) ([]models.Todo, string, error) {
+	var response *todov2.ListTodosResponse
+	err := c.retry(ctx, func(ctx context.Context) error {
+		var err error
+		response, err = c.client.ListTodos(ctx, &todov2.ListTodosRequest{PageSize: int32(pageSize), PageToken: pageToken})
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	todos := make([]models.Todo, 0, len(response.Todos))
+	for _, todo := range response.Todos {
+		todos = append(todos, todov2.FromProto(todo))
+	}
+	return todos, response.NextPageToken, nil
+}
+
+// GetTodos reads every page of ListTodos
+func (c *todoClient) GetTodos(ctx context.Context) ([]models.Todo, error) {
+	todos := []models.Todo{}
+	pageToken := ""
+	for {
+		page, next, err := c.ListTodos(ctx, 0, pageToken)
+		if err != nil {
+			return nil, err
+		}
+
+		todos = append(todos, page...)
+		if next == "" {
+			return todos, nil
+		}
+		pageToken = next
+	}
+}
+
+// UpdateTodo writes the given fields of todo, every updatable field when none are given
+func (c *todoClient) UpdateTodo(ctx context.Context, t models.Todo, fields ...string) (models.Todo, error) {
+	if len(fields) == 0 {
+		fields = models.TodoUpdatableFields
+	}
+
+	var todo *todov2.Todo
+	err := c.call(ctx, func(ctx context.Context) error {
+		var err error
+		todo, err = c.client.UpdateTodo(ctx, &todov2.UpdateTodoRequest{
+			Todo:       &todov2.Todo{Id: t.ID, Title: t.Title, Description: t.Description},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: fields},
+		})
+		return err
+	})
+	if err != nil {
+		return models.Todo{}, err
+	}
+	return todov2.FromProto(todo), nil
+}
+
+func (c *todoClient) DeleteTodo(ctx context.Context, id int64) error {
+	return c.call(ctx, func(ctx context.Context) error {
+		_, err := c.client.DeleteTodo(ctx, &todov2.DeleteTodoRequest{Id: id})
+		return err
+	})
+}
+
+func (c *todoClient) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// call runs one attempt with the configured deadline and token
+func (c *todoClient) call(ctx context.Context, attempt func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	if c.config.Token != nil {
+		token, err := c.config.Token(ctx)
+		if err != nil {
+			return err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+
+	return attempt(ctx)
+}
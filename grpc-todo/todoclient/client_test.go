@@ -0,0 +1,238 @@
+package todoclient
+
+import (
+	"context"
+	"grpc-todo/todov2"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// fakeServer fails the first failures calls of every RPC with code, then succeeds
+type fakeServer struct {
+	todov2.UnimplementedTodoServiceServer
+
+	mu         sync.Mutex
+	calls      int
+	failures   int
+	code       codes.Code
+	delay      time.Duration
+	tokens     []string
+	updateMask []string
+}
+
+func (s *fakeServer) attempt(ctx context.Context) error {
+	s.mu.Lock()
+	s.calls++
+	calls := s.calls
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.tokens = append(s.tokens, md.Get("authorization")...)
+	s.mu.Unlock()
+
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if calls <= s.failures {
+		return status.Error(s.code, "try again")
+	}
+	return nil
+}
+
+var createdAt = time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+
+func protoTodo(id int64, title string) *todov2.Todo {
+	return &todov2.Todo{Id: id, Title: title, UserId: 42, CreatedAt: timestamppb.New(createdAt), Version: map[string]int64{"server": 1}}
+}
+
+func (s *fakeServer) CreateTodo(ctx context.Context, request *todov2.CreateTodoRequest) (*todov2.Todo, error) {
+	if err := s.attempt(ctx); err != nil {
+		return nil, err
+	}
+	return protoTodo(3, request.Title), nil
+}
+
+func (s *fakeServer) GetTodo(ctx context.Context, request *todov2.GetTodoRequest) (*todov2.Todo, error) {
+	if err := s.attempt(ctx); err != nil {
+		return nil, err
+	}
+	return protoTodo(request.Id, "write sdk"), nil
+}
+
+// ListTodos serves todos 1 and 2 on separate pages
+func (s *fakeServer) ListTodos(ctx context.Context, request *todov2.ListTodosRequest) (*todov2.ListTodosResponse, error) {
+	if err := s.attempt(ctx); err != nil {
+		return nil, err
+	}
+	if request.PageToken == "" {
+		return &todov2.ListTodosResponse{Todos: []*todov2.Todo{protoTodo(1, "write sdk")}, NextPageToken: "page-2"}, nil
+	}
+	return &todov2.ListTodosResponse{Todos: []*todov2.Todo{protoTodo(2, "test sdk")}}, nil
+}
+
+func (s *fakeServer) UpdateTodo(ctx context.Context, request *todov2.UpdateTodoRequest) (*todov2.Todo, error) {
+	if err := s.attempt(ctx); err != nil {
+		return nil, err
+	}
+	s.updateMask = request.UpdateMask.GetPaths()
+	return protoTodo(request.Todo.Id, request.Todo.Title), nil
+}
+
+func startServer(t *testing.T, server *fakeServer, config Config) (TodoClient, func()) {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	todov2.RegisterTodoServiceServer(grpcServer, server)
+	go grpcServer.Serve(listener)
+
+	client, err := Dial("bufnet", config,
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+
+	return client, func() {
+		client.Close()
+		grpcServer.Stop()
+	}
+}
+
+var fastRetries = Config{InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond}
+
+func TestGetTodoRetriesUnavailable(t *testing.T) {
+	server := &fakeServer{failures: 2, code: codes.Unavailable}
+	config := fastRetries
+	config.Token = StaticToken("secret")
+	client, stop := startServer(t, server, config)
+	defer stop()
+
+	todo, err := client.GetTodo(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetTodo: %v", err)
+	}
+	if todo.ID != 7 || todo.Title != "write sdk" || todo.UserID != 42 || !todo.CreatedAt.Equal(createdAt) || todo.Version["server"] != 1 {
+		t.Fatalf("expected the converted todo, got %+v", todo)
+	}
+	if server.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", server.calls)
+	}
+	for _, token := range server.tokens {
+		if token != "Bearer secret" {
+			t.Fatalf("expected every attempt to carry the token, got %q", token)
+		}
+	}
+}
+
+func TestGetTodosReadsEveryPage(t *testing.T) {
+	server := &fakeServer{failures: 1, code: codes.Unavailable}
+	client, stop := startServer(t, server, fastRetries)
+	defer stop()
+
+	todos, err := client.GetTodos(context.Background())
+	if err != nil {
+		t.Fatalf("GetTodos: %v", err)
+	}
+	if len(todos) != 2 || todos[0].ID != 1 || todos[1].ID != 2 {
+		t.Fatalf("expected todos 1 and 2, got %+v", todos)
+	}
+	if server.calls != 3 {
+		t.Fatalf("expected a retried first page and a second page, got %d calls", server.calls)
+	}
+}
+
+func TestListTodosGivesUpAfterMaxAttempts(t *testing.T) {
+	server := &fakeServer{failures: 10, code: codes.Unavailable}
+	config := fastRetries
+	config.MaxAttempts = 3
+	client, stop := startServer(t, server, config)
+	defer stop()
+
+	if _, _, err := client.ListTodos(context.Background(), 10, ""); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if server.calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", server.calls)
+	}
+}
+
+func TestReadsDoNotRetryOtherCodes(t *testing.T) {
+	server := &fakeServer{failures: 1, code: codes.Unauthenticated}
+	client, stop := startServer(t, server, fastRetries)
+	defer stop()
+
+	if _, err := client.GetTodo(context.Background(), 7); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+	if server.calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", server.calls)
+	}
+}
+
+func TestWritesAreNotRetried(t *testing.T) {
+	server := &fakeServer{failures: 1, code: codes.Unavailable}
+	client, stop := startServer(t, server, fastRetries)
+	defer stop()
+
+	if _, err := client.CreateTodo(context.Background(), "title", "description"); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected Unavailable, got %v", err)
+	}
+	if server.calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", server.calls)
+	}
+	if len(server.tokens) != 0 {
+		t.Fatalf("expected no token without a TokenSource, got %v", server.tokens)
+	}
+
+	todo, err := client.CreateTodo(context.Background(), "title", "description")
+	if err != nil || todo.ID != 3 || todo.Title != "title" {
+		t.Fatalf("expected the created todo, got %+v %v", todo, err)
+	}
+}
+
+func TestUpdateTodoMask(t *testing.T) {
+	server := &fakeServer{}
+	client, stop := startServer(t, server, fastRetries)
+	defer stop()
+
+	if _, err := client.UpdateTodo(context.Background(), todov2.FromProto(protoTodo(1, "renamed")), "title"); err != nil {
+		t.Fatalf("UpdateTodo: %v", err)
+	}
+	if len(server.updateMask) != 1 || server.updateMask[0] != "title" {
+		t.Fatalf("expected update mask [title], got %v", server.updateMask)
+	}
+
+	client.UpdateTodo(context.Background(), todov2.FromProto(protoTodo(1, "renamed")))
+	if len(server.updateMask) != 2 {
+		t.Fatalf("expected every updatable field by default, got %v", server.updateMask)
+	}
+}
+
+func TestCallDeadline(t *testing.T) {
+	server := &fakeServer{delay: time.Second}
+	config := fastRetries
+	config.Timeout = 20 * time.Millisecond
+	client, stop := startServer(t, server, config)
+	defer stop()
+
+	start := time.Now()
+	if _, err := client.GetTodo(context.Background(), 7); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected the call to stop at its deadline, took %s", elapsed)
+	}
+}
@@ -0,0 +1,34 @@
+package todoclient
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// retry repeats attempt while it fails with Unavailable, sleeping InitialBackoff,
+// then twice as long each time up to MaxBackoff. Only idempotent calls may be retried.
+func (c *todoClient) retry(ctx context.Context, attempt func(ctx context.Context) error) error {
+	backoff := c.config.InitialBackoff
+
+	for i := 1; ; i++ {
+		err := c.call(ctx, attempt)
+		if status.Code(err) != codes.Unavailable || i >= c.config.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		if backoff *= 2; backoff > c.config.MaxBackoff {
+			backoff = c.config.MaxBackoff
+		}
+	}
+}
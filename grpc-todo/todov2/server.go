@@ -0,0 +1,142 @@
+package todov2
+
+import (
+	context "context"
+	"grpc-todo/auth"
+	"grpc-todo/grpcerr"
+	"grpc-todo/models"
+	"grpc-todo/usecase"
+	"log"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Server struct {
+	todoUsecase usecase.TodoUsecase
+}
+
+func InitServer(todoUsecase usecase.TodoUsecase) Server {
+	return Server{
+		todoUsecase,
+	}
+}
+
+func (s *Server) CreateTodo(ctx context.Context, request *CreateTodoRequest) (*Todo, error) {
+	user, err := auth.RequireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := s.todoUsecase.CreateTodo(models.Todo{
+		Title:       request.Title,
+		Description: request.Description,
+		UserID:      user.ID,
+	})
+	if err != nil {
+		log.Println("Error create todo", err)
+		return nil, grpcerr.Status(err)
+	}
+
+	return toProto(todo), nil
+}
+
+func (s *Server) GetTodo(ctx context.Context, request *GetTodoRequest) (*Todo, error) {
+	user, err := auth.RequireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	todo, err := s.todoUsecase.GetTodo(user.ID, request.Id)
+	if err != nil {
+		log.Println("Error get todo", err)
+		return nil, grpcerr.Status(err)
+	}
+
+	return toProto(todo), nil
+}
+
+func (s *Server) ListTodos(ctx context.Context, request *ListTodosRequest) (*ListTodosResponse, error) {
+	user, err := auth.RequireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	todos, nextPageToken, err := s.todoUsecase.ListTodos(user.ID, int(request.PageSize), request.PageToken)
+	if err != nil {
+		log.Println("Error list todos", err)
+		return nil, grpcerr.Status(err)
+	}
+
+	response := &ListTodosResponse{
+		Todos:         make([]*Todo, 0, len(todos)),
+		NextPageToken: nextPageToken,
+	}
+	for _, todo := range todos {
+		response.Todos = append(response.Todos, toProto(todo))
+	}
+
+	return response, nil
+}
+
+func (s *Server) UpdateTodo(ctx context.Context, request *UpdateTodoRequest) (*Todo, error) {
+	user, err := auth.RequireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if request.Todo == nil {
+		return nil, grpcerr.InvalidArgument("todo", "is required")
+	}
+
+	todo, err := s.todoUsecase.UpdateTodo(user.ID, models.Todo{
+		ID:          request.Todo.Id,
+		Title:       request.Todo.Title,
+		Description: request.Todo.Description,
+	}, request.UpdateMask.GetPaths())
+	if err != nil {
+		log.Println("Error update todo", err)
+		return nil, grpcerr.Status(err)
+	}
+
+	return toProto(todo), nil
+}
+
+func (s *Server) DeleteTodo(ctx context.Context, request *DeleteTodoRequest) (*emptypb.Empty, error) {
+	user, err := auth.RequireUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.todoUsecase.DeleteTodo(user.ID, request.Id)
+	if err != nil {
+		log.Println("Error delete todo", err)
+		return nil, grpcerr.Status(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// FromProto converts a todo returned by the service back into the model, for Go clients
+func FromProto(todo *Todo) models.Todo {
+	return models.Todo{
+		ID:          todo.GetId(),
+		Title:       todo.GetTitle(),
+		Description: todo.GetDescription(),
+		UserID:      todo.GetUserId(),
+		CreatedAt:   fromTimestamp(todo.GetCreatedAt()),
+		UpdatedAt:   fromTimestamp(todo.GetUpdatedAt()),
+		Version:     models.VersionVector(todo.GetVersion()),
+	}
+}
+
+func toProto(todo models.Todo) *Todo {
+	return &Todo{
+		Id:          todo.ID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		UserId:      todo.UserID,
+		CreatedAt:   timestamppb.New(todo.CreatedAt),
+		UpdatedAt:   timestamppb.New(todo.UpdatedAt),
+		Version:     todo.Version,
+	}
+}
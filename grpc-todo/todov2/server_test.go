@@ -0,0 +1,237 @@
+package todov2
+
+import (
+	"context"
+	"grpc-todo/auth"
+	"grpc-todo/broker"
+	"grpc-todo/models"
+	"grpc-todo/repository/repositorytest"
+	"grpc-todo/usecase"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+var testUser = models.User{ID: 1, Username: "alice"}
+
+// subscribedBroker reports each subscription so tests can publish only once a stream is listening
+type subscribedBroker struct {
+	broker.Broker
+	subscribed chan struct{}
+}
+
+func (b *subscribedBroker) Subscribe(userID int64) (<-chan models.TodoEvent, func()) {
+	events, unsubscribe := b.Broker.Subscribe(userID)
+	b.subscribed <- struct{}{}
+	return events, unsubscribe
+}
+
+type testService struct {
+	client     TodoServiceClient
+	subscribed chan struct{}
+}
+
+// startServer serves the v2 API over bufconn with every call authenticated as testUser
+func startServer(t *testing.T) testService {
+	t.Helper()
+
+	b := &subscribedBroker{Broker: broker.InitBroker(), subscribed: make(chan struct{}, 8)}
+	server := InitServer(usecase.InitUserUsecase(repositorytest.InitMemoryRepository(), b))
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			return handler(auth.ContextWithUser(ctx, testUser), req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			return handler(srv, &userStream{stream})
+		}),
+	)
+	RegisterTodoServiceServer(grpcServer, &server)
+	go grpcServer.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+
+	return testService{client: NewTodoServiceClient(conn), subscribed: b.subscribed}
+}
+
+type userStream struct {
+	grpc.ServerStream
+}
+
+func (stream *userStream) Context() context.Context {
+	return auth.ContextWithUser(stream.ServerStream.Context(), testUser)
+}
+
+func (s testService) waitSubscribed(t *testing.T) {
+	t.Helper()
+
+	select {
+	case <-s.subscribed:
+	case <-time.After(time.Second):
+		t.Fatal("stream never subscribed to todo events")
+	}
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestListTodosPageTokens(t *testing.T) {
+	service := startServer(t)
+	ctx := testContext(t)
+	for _, title := range []string{"one", "two", "three"} {
+		if _, err := service.client.CreateTodo(ctx, &CreateTodoRequest{Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, err := service.client.ListTodos(ctx, &ListTodosRequest{PageSize: 2})
+	if err != nil || len(first.Todos) != 2 || first.NextPageToken == "" {
+		t.Fatalf("expected a full first page with a token, got %v %v", first, err)
+	}
+	second, err := service.client.ListTodos(ctx, &ListTodosRequest{PageSize: 2, PageToken: first.NextPageToken})
+	if err != nil || len(second.Todos) != 1 || second.Todos[0].Title != "three" || second.NextPageToken != "" {
+		t.Fatalf("expected the last todo without a token, got %v %v", second, err)
+	}
+
+	_, err = service.client.ListTodos(ctx, &ListTodosRequest{PageToken: "bogus"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for a foreign token, got %v", err)
+	}
+}
+
+func TestUpdateTodoMask(t *testing.T) {
+	service := startServer(t)
+	ctx := testContext(t)
+	created, _ := service.client.CreateTodo(ctx, &CreateTodoRequest{Title: "title", Description: "description"})
+
+	updated, err := service.client.UpdateTodo(ctx, &UpdateTodoRequest{
+		Todo:       &Todo{Id: created.Id, Title: "renamed", Description: "ignored"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+	})
+	if err != nil || updated.Title != "renamed" || updated.Description != "description" {
+		t.Fatalf("expected only the title to change, got %v %v", updated, err)
+	}
+
+	_, err = service.client.UpdateTodo(ctx, &UpdateTodoRequest{
+		Todo:       &Todo{Id: created.Id, Title: "renamed"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user_id"}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for an unknown field, got %v", err)
+	}
+}
+
+func TestWatchTodos(t *testing.T) {
+	service := startServer(t)
+	ctx := testContext(t)
+
+	stream, err := service.client.WatchTodos(ctx, &WatchTodosRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.waitSubscribed(t)
+
+	created, _ := service.client.CreateTodo(ctx, &CreateTodoRequest{Title: "watched"})
+	service.client.DeleteTodo(ctx, &DeleteTodoRequest{Id: created.Id})
+
+	for _, want := range []TodoEvent_Type{TodoEvent_CREATED, TodoEvent_DELETED} {
+		event, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if event.Type != want || event.Todo.Id != created.Id {
+			t.Fatalf("expected %v of todo %d, got %v", want, created.Id, event)
+		}
+	}
+}
+
+func recvSync(t *testing.T, stream TodoService_SyncTodosClient, kind SyncTodosResponse_Kind) *SyncTodosResponse {
+	t.Helper()
+
+	response, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv: %v", err)
+	}
+	if response.Kind != kind {
+		t.Fatalf("expected %v, got %v", kind, response)
+	}
+	return response
+}
+
+func TestSyncTodos(t *testing.T) {
+	service := startServer(t)
+	ctx := testContext(t)
+	existing, _ := service.client.CreateTodo(ctx, &CreateTodoRequest{Title: "existing"})
+
+	stream, err := service.client.SyncTodos(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&SyncTodosRequest{
+		ReplicaId: "phone",
+		ClientRef: "local-1",
+		Change:    &TodoChange{Todo: &Todo{Title: "offline"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if snapshot := recvSync(t, stream, SyncTodosResponse_REMOTE); snapshot.Change.Todo.Id != existing.Id {
+		t.Fatalf("expected the stored todo first, got %v", snapshot)
+	}
+	accepted := recvSync(t, stream, SyncTodosResponse_ACCEPTED)
+	if accepted.ClientRef != "local-1" || accepted.Change.Todo.Id == 0 || accepted.Change.Todo.Version["phone"] != 1 {
+		t.Fatalf("expected the created todo for local-1, got %v", accepted)
+	}
+
+	// A stale edit from an older version is answered with the stored todo.
+	stale := &Todo{Id: existing.Id, Title: "stale", Version: map[string]int64{}}
+	stream.Send(&SyncTodosRequest{ReplicaId: "phone", ClientRef: "local-2", Change: &TodoChange{Todo: stale}})
+	if conflict := recvSync(t, stream, SyncTodosResponse_CONFLICT); conflict.Change.Todo.Title != "existing" {
+		t.Fatalf("expected the stored todo to win, got %v", conflict)
+	}
+
+	// After the client half-closes it still receives changes made elsewhere.
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+	// Let the server read the half-close before anything else happens.
+	time.Sleep(50 * time.Millisecond)
+	service.client.UpdateTodo(ctx, &UpdateTodoRequest{Todo: &Todo{Id: existing.Id, Title: "renamed"}})
+	if remote := recvSync(t, stream, SyncTodosResponse_REMOTE); remote.Change.Todo.Title != "renamed" {
+		t.Fatalf("expected the remote rename, got %v", remote)
+	}
+}
+
+func TestSyncTodosRejectsReplicaChange(t *testing.T) {
+	service := startServer(t)
+	ctx := testContext(t)
+
+	stream, _ := service.client.SyncTodos(ctx)
+	stream.Send(&SyncTodosRequest{ReplicaId: "phone"})
+	stream.Send(&SyncTodosRequest{ReplicaId: "laptop", Change: &TodoChange{Todo: &Todo{Title: "x"}}})
+
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
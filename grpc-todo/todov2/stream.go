@@ -0,0 +1,235 @@
+package todov2
+
+import (
+	context "context"
+	"grpc-todo/auth"
+	"grpc-todo/grpcerr"
+	"grpc-todo/models"
+	"grpc-todo/usecase"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var errWatcherEvicted = status.Error(codes.ResourceExhausted, "watcher fell behind, reload todos and watch again")
+
+func (s *Server) WatchTodos(request *WatchTodosRequest, stream TodoService_WatchTodosServer) error {
+	user, err := auth.RequireUser(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	events, unsubscribe := s.todoUsecase.WatchTodos(user.ID)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return errWatcherEvicted
+			}
+			if err := stream.Send(toEventProto(event)); err != nil {
+				log.Println("Error send todo event", err)
+				return err
+			}
+		}
+	}
+}
+
+func (s *Server) SyncTodos(stream TodoService_SyncTodosServer) error {
+	user, err := auth.RequireUser(stream.Context())
+	if err != nil {
+		return err
+	}
+
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if first.ReplicaId == "" || first.ReplicaId == models.ServerReplicaID {
+		return grpcerr.Status(usecase.ErrInvalidReplica)
+	}
+
+	replicaID, userID := first.ReplicaId, user.ID
+	events, unsubscribe := s.todoUsecase.WatchTodos(userID)
+	defer unsubscribe()
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	// Send is not safe for concurrent use and both loops below write to the stream.
+	// Holding mu after cancel keeps either loop from sending once the handler has returned.
+	var mu sync.Mutex
+	send := func(response *SyncTodosResponse) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		return stream.Send(response)
+	}
+	defer func() {
+		cancel()
+		mu.Lock()
+		mu.Unlock()
+	}()
+
+	todos, err := s.todoUsecase.GetTodos(userID)
+	if err != nil {
+		log.Println("Error sync todos", err)
+		return grpcerr.Status(err)
+	}
+	for _, todo := range todos {
+		err = send(&SyncTodosResponse{
+			Kind:   SyncTodosResponse_REMOTE,
+			Change: &TodoChange{Todo: toProto(todo)},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	relayed := make(chan error, 1)
+	go func() {
+		relayed <- s.relayTodoEvents(ctx, replicaID, events, send)
+	}()
+	applied := make(chan error, 1)
+	go func() {
+		applied <- s.applyTodoChanges(stream, userID, first, send)
+	}()
+
+	select {
+	case err := <-relayed:
+		return err
+	case err := <-applied:
+		if err != nil {
+			return err
+		}
+		// The client has no more changes to send but keeps receiving remote ones until it cancels.
+		return <-relayed
+	}
+}
+
+// applyTodoChanges answers every change the client sends until it closes its side of the stream
+func (s *Server) applyTodoChanges(stream TodoService_SyncTodosServer, userID int64, request *SyncTodosRequest, send func(*SyncTodosResponse) error) error {
+	replicaID := request.ReplicaId
+
+	for {
+		if request.ReplicaId != replicaID {
+			return grpcerr.InvalidArgument("replica_id", "cannot change within a stream")
+		}
+
+		if request.Change != nil {
+			change, outcome, err := s.todoUsecase.SyncTodo(replicaID, userID, fromChangeProto(request.Change))
+			if err != nil {
+				log.Println("Error sync todo", err)
+				return grpcerr.Status(err)
+			}
+
+			kind := SyncTodosResponse_ACCEPTED
+			if outcome == models.SyncRejected {
+				kind = SyncTodosResponse_CONFLICT
+			}
+			err = send(&SyncTodosResponse{
+				Kind:      kind,
+				Change:    toChangeProto(change),
+				ClientRef: request.ClientRef,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		request, err = stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// relayTodoEvents forwards changes made by other replicas; the client's own changes are answered directly
+func (s *Server) relayTodoEvents(ctx context.Context, replicaID string, events <-chan models.TodoEvent, send func(*SyncTodosResponse) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-events:
+			if !ok {
+				return errWatcherEvicted
+			}
+			if event.Origin == replicaID {
+				continue
+			}
+
+			err := send(&SyncTodosResponse{
+				Kind: SyncTodosResponse_REMOTE,
+				Change: &TodoChange{
+					Todo:    toProto(event.Todo),
+					Deleted: event.Type == models.TodoDeleted,
+				},
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func toEventProto(event models.TodoEvent) *TodoEvent {
+	eventType := TodoEvent_TYPE_UNSPECIFIED
+	switch event.Type {
+	case models.TodoCreated:
+		eventType = TodoEvent_CREATED
+	case models.TodoUpdated:
+		eventType = TodoEvent_UPDATED
+	case models.TodoDeleted:
+		eventType = TodoEvent_DELETED
+	}
+
+	return &TodoEvent{
+		Type: eventType,
+		Todo: toProto(event.Todo),
+	}
+}
+
+func toChangeProto(change models.TodoChange) *TodoChange {
+	return &TodoChange{
+		Todo:    toProto(change.Todo),
+		Deleted: change.Deleted,
+	}
+}
+
+func fromChangeProto(change *TodoChange) models.TodoChange {
+	todo := change.GetTodo()
+
+	return models.TodoChange{
+		Todo: models.Todo{
+			ID:          todo.GetId(),
+			Title:       todo.GetTitle(),
+			Description: todo.GetDescription(),
+			UpdatedAt:   fromTimestamp(todo.GetUpdatedAt()),
+			Version:     models.VersionVector(todo.GetVersion()),
+		},
+		Deleted: change.Deleted,
+	}
+}
+
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+
+	return ts.AsTime()
+}
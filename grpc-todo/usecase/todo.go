@@ -1,67 +1,263 @@
 package usecase
 
 import (
-	"encoding/json"
+	"encoding/base64"
+	"grpc-todo/broker"
 	"grpc-todo/models"
 	"grpc-todo/repository"
 	"log"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+var (
+	// ErrInvalidPageToken is returned when a page token was not issued by ListTodos
+	ErrInvalidPageToken = invalidField("page_token", "was not returned by a previous ListTodos call")
+	// ErrInvalidUpdateMask is returned when an update mask names a field that cannot be updated
+	ErrInvalidUpdateMask = invalidField("update_mask", "only title and description can be updated")
+	// ErrInvalidReplica is returned when a sync client does not identify itself
+	ErrInvalidReplica = invalidField("replica_id", "is required and cannot be \"server\"")
+	// ErrTitleRequired is returned when a todo would be stored without a title
+	ErrTitleRequired = invalidField("title", "must not be empty")
 )
 
 type todoUsecase struct {
 	todoRepository repository.TodoRepository
+	broker         broker.Broker
 }
 
 type TodoUsecase interface {
-	CreateTodo(todo models.Todo) (bool, error)
-	GetTodos(userID int64) (string, error)
-	UpdateTodo(todo models.Todo) (bool, error)
-	DeleteTodo(id int64) (bool, error)
+	CreateTodo(todo models.Todo) (models.Todo, error)
+	GetTodo(userID int64, id int64) (models.Todo, error)
+	GetTodos(userID int64) ([]models.Todo, error)
+	ListTodos(userID int64, pageSize int, pageToken string) ([]models.Todo, string, error)
+	UpdateTodo(userID int64, todo models.Todo, fields []string) (models.Todo, error)
+	DeleteTodo(userID int64, id int64) (bool, error)
+	WatchTodos(userID int64) (<-chan models.TodoEvent, func())
+	SyncTodo(replicaID string, userID int64, change models.TodoChange) (models.TodoChange, models.SyncOutcome, error)
 }
 
-func InitUserUsecase(todoRepository repository.TodoRepository) TodoUsecase {
+func InitUserUsecase(todoRepository repository.TodoRepository, broker broker.Broker) TodoUsecase {
 	return &todoUsecase{
 		todoRepository,
+		broker,
 	}
 }
 
-func (todoUsecase *todoUsecase) CreateTodo(todo models.Todo) (bool, error) {
-	_, err := todoUsecase.todoRepository.CreateTodo(todo)
+func (todoUsecase *todoUsecase) CreateTodo(todo models.Todo) (models.Todo, error) {
+	if err := validateTodo(todo, models.TodoUpdatableFields); err != nil {
+		return models.Todo{}, err
+	}
+
+	todo.UpdatedAt = time.Now()
+	todo.Version = models.VersionVector{}.Increment(models.ServerReplicaID)
+
+	created, err := todoUsecase.todoRepository.CreateTodo(todo)
 	if err != nil {
 		log.Println("Error create todo", err)
-		return false, err
+		return models.Todo{}, err
 	}
 
-	return true, nil
+	todoUsecase.publish(models.TodoCreated, created, models.ServerReplicaID)
+	return created, nil
 }
 
-func (todoUsecase *todoUsecase) GetTodos(userID int64) (string, error) {
+func (todoUsecase *todoUsecase) GetTodo(userID int64, id int64) (models.Todo, error) {
+	todo, err := todoUsecase.todoRepository.GetTodo(userID, id)
+	if err != nil {
+		log.Println("Error get todo", err)
+		return models.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+func (todoUsecase *todoUsecase) GetTodos(userID int64) ([]models.Todo, error) {
 	todos, err := todoUsecase.todoRepository.GetTodos(userID)
 	if err != nil {
 		log.Println("Error get todos", err)
-		return "", err
+		return []models.Todo{}, err
+	}
+
+	return todos, nil
+}
+
+// ListTodos returns one page of the user's todos and the token for the next page,
+// which is empty once the last page has been reached
+func (todoUsecase *todoUsecase) ListTodos(userID int64, pageSize int, pageToken string) ([]models.Todo, string, error) {
+	afterID, err := decodePageToken(pageToken)
+	if err != nil {
+		return []models.Todo{}, "", err
 	}
 
-	result, err := json.Marshal(todos)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
 
-	return string(result), nil
+	// Fetch one extra row to learn whether another page exists.
+	todos, err := todoUsecase.todoRepository.ListTodos(userID, afterID, pageSize+1)
+	if err != nil {
+		log.Println("Error list todos", err)
+		return []models.Todo{}, "", err
+	}
+
+	nextPageToken := ""
+	if len(todos) > pageSize {
+		todos = todos[:pageSize]
+		nextPageToken = encodePageToken(todos[pageSize-1].ID)
+	}
+
+	return todos, nextPageToken, nil
 }
 
-func (todoUsecase *todoUsecase) UpdateTodo(todo models.Todo) (bool, error) {
-	_, err := todoUsecase.todoRepository.UpdateTodo(todo)
+// UpdateTodo writes the given fields of the user's todo, or every updatable field when fields is empty
+func (todoUsecase *todoUsecase) UpdateTodo(userID int64, todo models.Todo, fields []string) (models.Todo, error) {
+	fields, err := normalizeFields(fields)
+	if err != nil {
+		return models.Todo{}, err
+	}
+	if err := validateTodo(todo, fields); err != nil {
+		return models.Todo{}, err
+	}
+
+	updated, err := todoUsecase.todoRepository.UpdateTodo(userID, todo, fields)
 	if err != nil {
 		log.Println("Error update todo", err)
-		return false, err
+		return models.Todo{}, err
 	}
 
-	return true, nil
+	todoUsecase.publish(models.TodoUpdated, updated, models.ServerReplicaID)
+	return updated, nil
 }
 
-func (todoUsecase *todoUsecase) DeleteTodo(id int64) (bool, error) {
-	_, err := todoUsecase.todoRepository.DeleteTodo(id)
+func (todoUsecase *todoUsecase) DeleteTodo(userID int64, id int64) (bool, error) {
+	deleted, err := todoUsecase.todoRepository.DeleteTodo(userID, id)
 	if err != nil {
 		log.Println("Error delete todo", err)
 		return false, err
 	}
 
+	todoUsecase.publish(models.TodoDeleted, deleted, models.ServerReplicaID)
 	return true, nil
 }
+
+// WatchTodos subscribes to the user's todo events; call the returned function to stop
+func (todoUsecase *todoUsecase) WatchTodos(userID int64) (<-chan models.TodoEvent, func()) {
+	return todoUsecase.broker.Subscribe(userID)
+}
+
+// SyncTodo applies a change made offline by a sync client and returns the state the
+// client should keep. Every write, including a server win that merged versions, is
+// published to the user's other watchers.
+func (todoUsecase *todoUsecase) SyncTodo(replicaID string, userID int64, change models.TodoChange) (models.TodoChange, models.SyncOutcome, error) {
+	if replicaID == "" || replicaID == models.ServerReplicaID {
+		return models.TodoChange{}, 0, ErrInvalidReplica
+	}
+	if !change.Deleted {
+		if err := validateTodo(change.Todo, models.TodoUpdatableFields); err != nil {
+			return models.TodoChange{}, 0, err
+		}
+	}
+
+	if change.Todo.ID == 0 {
+		if change.Deleted {
+			return change, models.SyncUnchanged, nil
+		}
+
+		todo := change.Todo
+		todo.UserID = userID
+		if len(todo.Version) == 0 {
+			todo.Version = models.VersionVector{}.Increment(replicaID)
+		}
+		if todo.UpdatedAt.IsZero() {
+			todo.UpdatedAt = time.Now()
+		}
+
+		created, err := todoUsecase.todoRepository.CreateTodo(todo)
+		if err != nil {
+			log.Println("Error sync todo", err)
+			return models.TodoChange{}, 0, err
+		}
+
+		todoUsecase.publish(models.TodoCreated, created, replicaID)
+		return models.TodoChange{Todo: created}, models.SyncApplied, nil
+	}
+
+	resolved, outcome, written, err := todoUsecase.todoRepository.SyncTodo(userID, change)
+	if err != nil {
+		log.Println("Error sync todo", err)
+		return models.TodoChange{}, 0, err
+	}
+
+	if written {
+		eventType := models.TodoUpdated
+		if resolved.Deleted {
+			eventType = models.TodoDeleted
+		}
+		todoUsecase.publish(eventType, resolved.Todo, replicaID)
+	}
+
+	return resolved, outcome, nil
+}
+
+func (todoUsecase *todoUsecase) publish(eventType models.TodoEventType, todo models.Todo, origin string) {
+	todoUsecase.broker.Publish(models.TodoEvent{
+		Type:   eventType,
+		Todo:   todo,
+		Origin: origin,
+	})
+}
+
+func normalizeFields(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return models.TodoUpdatableFields, nil
+	}
+
+	requested := map[string]bool{}
+	for _, field := range fields {
+		requested[field] = true
+	}
+
+	result := []string{}
+	for _, field := range models.TodoUpdatableFields {
+		if requested[field] {
+			result = append(result, field)
+			delete(requested, field)
+		}
+	}
+	if len(requested) > 0 {
+		return nil, ErrInvalidUpdateMask
+	}
+
+	return result, nil
+}
+
+func encodePageToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, ErrInvalidPageToken
+	}
+
+	lastID, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || lastID < 0 {
+		return 0, ErrInvalidPageToken
+	}
+
+	return lastID, nil
+}
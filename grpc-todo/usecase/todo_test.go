@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"grpc-todo/broker"
+	"grpc-todo/models"
+	"grpc-todo/repository/repositorytest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestUsecase(t *testing.T) (TodoUsecase, <-chan models.TodoEvent) {
+	t.Helper()
+
+	b := broker.InitBroker()
+	events, unsubscribe := b.Subscribe(1)
+	t.Cleanup(unsubscribe)
+
+	return InitUserUsecase(repositorytest.InitMemoryRepository(), b), events
+}
+
+func expectEvent(t *testing.T, events <-chan models.TodoEvent, eventType models.TodoEventType, origin string) models.TodoEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		if event.Type != eventType || event.Origin != origin {
+			t.Fatalf("expected event %v from %q, got %+v", eventType, origin, event)
+		}
+		return event
+	default:
+		t.Fatalf("expected event %v from %q, got none", eventType, origin)
+	}
+	return models.TodoEvent{}
+}
+
+func expectNoEvent(t *testing.T, events <-chan models.TodoEvent) {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+}
+
+func TestListTodosPages(t *testing.T) {
+	todoUsecase, _ := newTestUsecase(t)
+	for i := 0; i < 5; i++ {
+		if _, err := todoUsecase.CreateTodo(models.Todo{Title: "todo", UserID: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	todoUsecase.CreateTodo(models.Todo{Title: "someone else's", UserID: 2})
+
+	ids := []int64{}
+	pageToken := ""
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatalf("expected three pages, token %q is still set", pageToken)
+		}
+		todos, next, err := todoUsecase.ListTodos(1, 2, pageToken)
+		if err != nil {
+			t.Fatalf("ListTodos: %v", err)
+		}
+		for _, todo := range todos {
+			ids = append(ids, todo.ID)
+		}
+		if next == "" {
+			break
+		}
+		pageToken = next
+	}
+
+	if !reflect.DeepEqual(ids, []int64{1, 2, 3, 4, 5}) {
+		t.Fatalf("expected todos 1 to 5 once each, got %v", ids)
+	}
+}
+
+func TestListTodosRejectsForeignPageTokens(t *testing.T) {
+	todoUsecase, _ := newTestUsecase(t)
+
+	for _, token := range []string{"not base64!", "eA", "LTE"} {
+		if _, _, err := todoUsecase.ListTodos(1, 10, token); err != ErrInvalidPageToken {
+			t.Fatalf("token %q: expected ErrInvalidPageToken, got %v", token, err)
+		}
+	}
+}
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	for _, id := range []int64{0, 1, 1 << 40} {
+		got, err := decodePageToken(encodePageToken(id))
+		if err != nil || got != id {
+			t.Fatalf("expected %d, got %d (%v)", id, got, err)
+		}
+	}
+}
+
+func TestNormalizeFields(t *testing.T) {
+	cases := []struct {
+		name    string
+		fields  []string
+		want    []string
+		wantErr error
+	}{
+		{name: "empty means all", fields: nil, want: models.TodoUpdatableFields},
+		{name: "canonical order", fields: []string{"description", "title"}, want: []string{"title", "description"}},
+		{name: "duplicates", fields: []string{"title", "title"}, want: []string{"title"}},
+		{name: "unknown field", fields: []string{"title", "user_id"}, wantErr: ErrInvalidUpdateMask},
+	}
+
+	for _, c := range cases {
+		got, err := normalizeFields(c.fields)
+		if err != c.wantErr || !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("%s: expected %v %v, got %v %v", c.name, c.want, c.wantErr, got, err)
+		}
+	}
+}
+
+func TestSyncTodoPublishesWrites(t *testing.T) {
+	todoUsecase, events := newTestUsecase(t)
+	now := time.Now()
+
+	created, outcome, err := todoUsecase.SyncTodo("phone", 1, models.TodoChange{Todo: models.Todo{Title: "offline", UpdatedAt: now}})
+	if err != nil || outcome != models.SyncApplied || created.Todo.ID == 0 {
+		t.Fatalf("expected the todo to be created, got %+v %v %v", created, outcome, err)
+	}
+	expectEvent(t, events, models.TodoCreated, "phone")
+
+	// Resending the stored version changes nothing.
+	_, outcome, _ = todoUsecase.SyncTodo("phone", 1, created)
+	if outcome != models.SyncUnchanged {
+		t.Fatalf("expected SyncUnchanged, got %v", outcome)
+	}
+	expectNoEvent(t, events)
+
+	// A concurrent edit that is older than the stored one loses, but the merged version is still written.
+	stale := created
+	stale.Todo.Title = "stale"
+	stale.Todo.UpdatedAt = now.Add(-time.Hour)
+	stale.Todo.Version = models.VersionVector{"laptop": 1}
+	resolved, outcome, err := todoUsecase.SyncTodo("laptop", 1, stale)
+	if err != nil || outcome != models.SyncRejected || resolved.Todo.Title != "offline" {
+		t.Fatalf("expected the server to win, got %+v %v %v", resolved, outcome, err)
+	}
+	event := expectEvent(t, events, models.TodoUpdated, "laptop")
+	if event.Todo.Version["laptop"] != 1 || event.Todo.Version["phone"] != 1 {
+		t.Fatalf("expected the merged version to be published, got %v", event.Todo.Version)
+	}
+
+	// A change that descends from the stored version deletes the todo.
+	deletion := resolved
+	deletion.Deleted = true
+	deletion.Todo.Version = resolved.Todo.Version.Increment("phone")
+	if _, outcome, _ = todoUsecase.SyncTodo("phone", 1, deletion); outcome != models.SyncApplied {
+		t.Fatalf("expected the deletion to apply, got %v", outcome)
+	}
+	expectEvent(t, events, models.TodoDeleted, "phone")
+
+	// The todo is gone, so further edits are answered with its deletion.
+	resolved, outcome, _ = todoUsecase.SyncTodo("laptop", 1, stale)
+	if outcome != models.SyncRejected || !resolved.Deleted {
+		t.Fatalf("expected a deletion, got %+v %v", resolved, outcome)
+	}
+	expectNoEvent(t, events)
+}
+
+func TestSyncTodoRequiresReplica(t *testing.T) {
+	todoUsecase, _ := newTestUsecase(t)
+
+	for _, replicaID := range []string{"", models.ServerReplicaID} {
+		if _, _, err := todoUsecase.SyncTodo(replicaID, 1, models.TodoChange{Todo: models.Todo{Title: "x"}}); err != ErrInvalidReplica {
+			t.Fatalf("replica %q: expected ErrInvalidReplica, got %v", replicaID, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"grpc-todo/models"
+	"strings"
+)
+
+// FieldViolation describes why a request field was rejected
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError lists the request fields that failed validation
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, violation := range e.Violations {
+		messages = append(messages, violation.Field+": "+violation.Description)
+	}
+
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+func invalidField(field, description string) *ValidationError {
+	return &ValidationError{
+		Violations: []FieldViolation{{Field: field, Description: description}},
+	}
+}
+
+// validateTodo checks the fields of todo that are about to be written
+func validateTodo(todo models.Todo, fields []string) error {
+	for _, field := range fields {
+		if field == models.TodoFieldTitle && strings.TrimSpace(todo.Title) == "" {
+			return ErrTitleRequired
+		}
+	}
+
+	return nil
+}
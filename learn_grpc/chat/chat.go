@@ -1,15 +1,86 @@
 package chat
 
 import (
+	"io"
 	"log"
 
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+var errMemberEvicted = status.Error(codes.ResourceExhausted, "member fell behind and was removed from the room, join again")
+
 type Server struct {
+	hub *hub
+}
+
+func InitServer() Server {
+	return Server{
+		hub: newHub(),
+	}
 }
 
 func (s *Server) SayHello(ctx context.Context, in *Message) (*Message, error) {
 	log.Printf("Receive message body from client: %s", in.Body)
 	return &Message{Body: "Hello From the Server!"}, nil
 }
+
+// Join expects a JoinRequest first, then relays the client's messages to the room
+// and streams the room history, messages and presence events back until the client leaves.
+func (s *Server) Join(stream ChatService_JoinServer) error {
+	first, err := stream.Recv()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	join := first.GetJoin()
+	if join == nil || join.Room == "" || join.User == "" {
+		return status.Error(codes.InvalidArgument, "first message must join a room with a user name")
+	}
+
+	m, err := s.hub.join(join.Room, join.User)
+	if err == errUserTaken {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	defer s.hub.leave(join.Room, m)
+	log.Printf("%s joined room %s", join.User, join.Room)
+
+	// Recv and Send may run concurrently, only this handler goroutine sends.
+	received := make(chan error, 1)
+	go func() {
+		for {
+			request, err := stream.Recv()
+			if err == io.EOF {
+				received <- nil
+				return
+			}
+			if err != nil {
+				received <- err
+				return
+			}
+			if body := request.GetBody(); body != "" {
+				s.hub.publish(join.Room, m, body)
+			}
+		}
+	}()
+
+	for {
+		select {
+		case err := <-received:
+			log.Printf("%s left room %s", join.User, join.Room)
+			return err
+		case event, ok := <-m.events:
+			if !ok {
+				return errMemberEvicted
+			}
+			if err := stream.Send(event); err != nil {
+				log.Println("Error send chat event", err)
+				return err
+			}
+		}
+	}
+}
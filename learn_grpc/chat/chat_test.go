@@ -0,0 +1,186 @@
+package chat
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestHubFanOutAndHistory(t *testing.T) {
+	h := newHub()
+
+	alice, err := h.join("go", "alice")
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	h.publish("go", alice, "first")
+
+	bob, err := h.join("go", "bob")
+	if err != nil {
+		t.Fatalf("join: %v", err)
+	}
+	h.publish("go", bob, "second")
+
+	// alice: own JOINED, first, bob JOINED, second
+	want := []struct {
+		eventType ChatEvent_Type
+		user      string
+		body      string
+	}{
+		{ChatEvent_JOINED, "alice", ""},
+		{ChatEvent_MESSAGE, "alice", "first"},
+		{ChatEvent_JOINED, "bob", ""},
+		{ChatEvent_MESSAGE, "bob", "second"},
+	}
+	for i, w := range want {
+		event := <-alice.events
+		if event.Type != w.eventType || event.User != w.user || event.Body != w.body {
+			t.Fatalf("alice event %d: expected %v %s %q, got %v %s %q", i, w.eventType, w.user, w.body, event.Type, event.User, event.Body)
+		}
+	}
+
+	replay := <-bob.events
+	if !replay.History || replay.Body != "first" || replay.Sequence != 1 {
+		t.Fatalf("expected first message replayed to bob, got %v", replay)
+	}
+	joined := <-bob.events
+	if joined.Type != ChatEvent_JOINED || len(joined.Members) != 2 {
+		t.Fatalf("expected bob JOINED with 2 members, got %v", joined)
+	}
+	if second := <-bob.events; second.History || second.Sequence != 2 {
+		t.Fatalf("expected live second message, got %v", second)
+	}
+}
+
+func TestHubHistoryBounded(t *testing.T) {
+	h := newHub()
+	alice, _ := h.join("go", "alice")
+	for i := 0; i < historySize+10; i++ {
+		h.publish("go", alice, "spam")
+		<-alice.events
+	}
+
+	bob, _ := h.join("go", "bob")
+	if first := <-bob.events; first.Sequence != 11 {
+		t.Fatalf("expected replay to start at sequence 11, got %d", first.Sequence)
+	}
+}
+
+func TestHubEvictsSlowMember(t *testing.T) {
+	h := newHub()
+	slow, _ := h.join("go", "slow")
+	fast, _ := h.join("go", "fast")
+
+	var left *ChatEvent
+	for i := 0; i < memberBuffer; i++ {
+		h.publish("go", fast, "hello")
+		for len(fast.events) > 0 {
+			if event := <-fast.events; event.Type == ChatEvent_LEFT {
+				left = event
+			}
+		}
+	}
+
+	for range slow.events {
+	}
+	if _, ok := h.rooms["go"].members[slow]; ok {
+		t.Fatal("expected slow member to be evicted")
+	}
+	if left == nil || left.User != "slow" || len(left.Members) != 1 {
+		t.Fatalf("expected fast member to see slow leave, got %v", left)
+	}
+
+	h.leave("go", slow)
+	if len(h.rooms["go"].members) != 1 {
+		t.Fatal("expected leave after eviction to be a no-op")
+	}
+}
+
+func TestHubAnnouncesEachEvictionOnce(t *testing.T) {
+	h := newHub()
+	slow1, _ := h.join("go", "slow1")
+	h.join("go", "slow2")
+	fast, _ := h.join("go", "fast")
+	// both slow members now lag by the same amount and overflow on the same publish
+	<-slow1.events
+
+	lefts := map[string]int{}
+	for i := 0; i < memberBuffer; i++ {
+		h.publish("go", fast, "hello")
+		for len(fast.events) > 0 {
+			if event := <-fast.events; event.Type == ChatEvent_LEFT {
+				lefts[event.User]++
+			}
+		}
+	}
+
+	if lefts["slow1"] != 1 || lefts["slow2"] != 1 {
+		t.Fatalf("expected one LEFT per evicted member, got %v", lefts)
+	}
+}
+
+func TestHubRejectsDuplicateUser(t *testing.T) {
+	h := newHub()
+	h.join("go", "alice")
+	if _, err := h.join("go", "alice"); err != errUserTaken {
+		t.Fatalf("expected errUserTaken, got %v", err)
+	}
+	if _, err := h.join("rust", "alice"); err != nil {
+		t.Fatalf("expected the name to be free in another room, got %v", err)
+	}
+}
+
+func TestJoinStream(t *testing.T) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	s := InitServer()
+	RegisterChatServiceServer(server, &s)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer conn.Close()
+	client := NewChatServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.Join(ctx)
+	if err != nil {
+		t.Fatalf("Join: %v", err)
+	}
+	stream.Send(&ChatRequest{Payload: &ChatRequest_Body{Body: "no handshake"}})
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument without a join, got %v", err)
+	}
+
+	stream, _ = client.Join(ctx)
+	stream.Send(&ChatRequest{Payload: &ChatRequest_Join{Join: &JoinRequest{Room: "go", User: "alice"}}})
+	if event, err := stream.Recv(); err != nil || event.Type != ChatEvent_JOINED {
+		t.Fatalf("expected JOINED, got %v %v", event, err)
+	}
+
+	stream.Send(&ChatRequest{Payload: &ChatRequest_Body{Body: "hi"}})
+	event, err := stream.Recv()
+	if err != nil || event.Body != "hi" || event.User != "alice" {
+		t.Fatalf("expected own message echoed, got %v %v", event, err)
+	}
+
+	duplicate, _ := client.Join(ctx)
+	duplicate.Send(&ChatRequest{Payload: &ChatRequest_Join{Join: &JoinRequest{Room: "go", User: "alice"}}})
+	if _, err := duplicate.Recv(); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", err)
+	}
+}
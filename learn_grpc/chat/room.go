@@ -0,0 +1,166 @@
+package chat
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// historySize is how many messages a room replays to a member that joins it
+	historySize = 50
+	// memberBuffer is how many events a member may lag behind before it is evicted.
+	// It must hold the whole history replay plus the member's own JOINED event.
+	memberBuffer = 64
+)
+
+var errUserTaken = errors.New("user name already taken in this room")
+
+type member struct {
+	user   string
+	events chan *ChatEvent
+}
+
+type room struct {
+	name     string
+	members  map[*member]struct{}
+	history  []*ChatEvent
+	sequence int64
+}
+
+// hub keeps the rooms in memory, a room keeps its history after the last member leaves
+type hub struct {
+	mu    sync.Mutex
+	rooms map[string]*room
+}
+
+func newHub() *hub {
+	return &hub{
+		rooms: map[string]*room{},
+	}
+}
+
+// join adds user to the room, queues the room history for them and announces them to everyone.
+// The returned member's channel is closed when it leaves or is evicted for falling behind.
+func (h *hub) join(roomName, user string) (*member, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.rooms[roomName]
+	if r == nil {
+		r = &room{name: roomName, members: map[*member]struct{}{}}
+		h.rooms[roomName] = r
+	}
+	for m := range r.members {
+		if m.user == user {
+			return nil, errUserTaken
+		}
+	}
+
+	m := &member{user: user, events: make(chan *ChatEvent, memberBuffer)}
+	for _, event := range r.history {
+		replay := proto.Clone(event).(*ChatEvent)
+		replay.History = true
+		m.events <- replay
+	}
+	r.members[m] = struct{}{}
+
+	h.broadcast(r, r.presence(ChatEvent_JOINED, user))
+	return m, nil
+}
+
+// leave removes the member and announces it, it is a no-op for an evicted member
+func (h *hub) leave(roomName string, m *member) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.rooms[roomName]
+	if r == nil || !r.remove(m) {
+		return
+	}
+	h.broadcast(r, r.presence(ChatEvent_LEFT, m.user))
+}
+
+// publish appends the message to the room history and delivers it to every member, sender included
+func (h *hub) publish(roomName string, m *member, body string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	r := h.rooms[roomName]
+	if r == nil {
+		return
+	}
+	if _, ok := r.members[m]; !ok {
+		return
+	}
+
+	r.sequence++
+	event := &ChatEvent{
+		Type:     ChatEvent_MESSAGE,
+		Room:     r.name,
+		User:     m.user,
+		Body:     body,
+		Sequence: r.sequence,
+		SentAt:   timestamppb.Now(),
+	}
+
+	r.history = append(r.history, event)
+	if len(r.history) > historySize {
+		r.history = r.history[len(r.history)-historySize:]
+	}
+
+	h.broadcast(r, event)
+}
+
+// broadcast delivers the event without blocking and evicts members whose buffer is full.
+// Must be called with mu held.
+func (h *hub) broadcast(r *room, event *ChatEvent) {
+	var evicted []*member
+	for m := range r.members {
+		select {
+		case m.events <- event:
+		default:
+			evicted = append(evicted, m)
+		}
+	}
+
+	// The LEFT broadcast of one member may already have evicted the next one.
+	for _, m := range evicted {
+		if r.remove(m) {
+			log.Printf("Evicting slow chat member %s from room %s", m.user, r.name)
+			h.broadcast(r, r.presence(ChatEvent_LEFT, m.user))
+		}
+	}
+}
+
+// remove reports whether m was still a member, it must be called with the hub mutex held
+func (r *room) remove(m *member) bool {
+	if _, ok := r.members[m]; !ok {
+		return false
+	}
+
+	delete(r.members, m)
+	close(m.events)
+	return true
+}
+
+func (r *room) presence(eventType ChatEvent_Type, user string) *ChatEvent {
+	members := make([]string, 0, len(r.members))
+	for m := range r.members {
+		members = append(members, m.user)
+	}
+	sort.Strings(members)
+
+	return &ChatEvent{
+		Type:     eventType,
+		Room:     r.name,
+		User:     user,
+		Sequence: r.sequence,
+		SentAt:   timestamppb.Now(),
+		Members:  members,
+	}
+}
@@ -1,16 +1,43 @@
 package main
 
 import (
+	"bufio"
+	"flag"
+	"fmt"
+	"grpc-tls/tlsconfig"
+	"io"
 	"learn-grpc/chat"
 	"log"
+	"os"
+	"strings"
 
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
 
 func main() {
+	addr := flag.String("addr", ":9000", "chat server address")
+	room := flag.String("room", "lobby", "room to join")
+	user := flag.String("user", os.Getenv("USER"), "name shown to the room")
+	caFile := flag.String("ca", "", "CA certificate verifying the server, plaintext when empty")
+	certFile := flag.String("cert", "", "client certificate for mutual TLS")
+	keyFile := flag.String("key", "", "client key for mutual TLS")
+	serverName := flag.String("server-name", "", "host name expected in the server certificate")
+	flag.Parse()
+
+	credentials, stopTLS, err := tlsconfig.DialOption(tlsconfig.Config{
+		CertFile:   *certFile,
+		KeyFile:    *keyFile,
+		CAFile:     *caFile,
+		ServerName: *serverName,
+	})
+	if err != nil {
+		log.Fatalf("failed to load TLS certificates: %s", err)
+	}
+	defer stopTLS()
+
 	var conn *grpc.ClientConn
-	conn, err := grpc.Dial(":9000", grpc.WithInsecure())
+	conn, err = grpc.Dial(*addr, credentials)
 	if err != nil {
 		log.Fatalf("did not connect: %s", err)
 	}
@@ -18,9 +45,65 @@ func main() {
 
 	c := chat.NewChatServiceClient(conn)
 
-	response, err := c.SayHello(context.Background(), &chat.Message{Body: "Hello from client!"})
+	stream, err := c.Join(context.Background())
 	if err != nil {
-		log.Fatalf("error when calling SayHello: %s", err)
+		log.Fatalf("error when calling Join: %s", err)
+	}
+	join := &chat.ChatRequest{Payload: &chat.ChatRequest_Join{Join: &chat.JoinRequest{Room: *room, User: *user}}}
+	if err := stream.Send(join); err != nil {
+		log.Fatalf("error when joining %s: %s", *room, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			event, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				log.Printf("disconnected: %s", err)
+				return
+			}
+			printEvent(event)
+		}
+	}()
+
+	fmt.Printf("Joined %s as %s, type /quit to leave\n", *room, *user)
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "/quit" {
+				break
+			}
+			if line == "" {
+				continue
+			}
+			if err := stream.Send(&chat.ChatRequest{Payload: &chat.ChatRequest_Body{Body: line}}); err != nil {
+				break
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	<-done
+}
+
+func printEvent(event *chat.ChatEvent) {
+	at := event.SentAt.AsTime().Local().Format("15:04")
+
+	switch event.Type {
+	case chat.ChatEvent_JOINED:
+		fmt.Printf("%s * %s joined (%s)\n", at, event.User, strings.Join(event.Members, ", "))
+	case chat.ChatEvent_LEFT:
+		fmt.Printf("%s * %s left (%s)\n", at, event.User, strings.Join(event.Members, ", "))
+	default:
+		prefix := ""
+		if event.History {
+			prefix = "[history] "
+		}
+		fmt.Printf("%s %s<%s> %s\n", at, prefix, event.User, event.Body)
 	}
-	log.Printf("Response from server: %s", response.Body)
 }
@@ -0,0 +1 @@
+protoc --go_out=plugins=grpc:chat chat.proto
@@ -4,7 +4,14 @@ go 1.13
 
 require (
 	github.com/golang/protobuf v1.4.3
-	golang.org/x/net v0.0.0-20190311183353-d8887717615a
-	google.golang.org/grpc v1.27.0
+	github.com/prometheus/client_golang v1.9.0
+	golang.org/x/net v0.0.0-20200625001655-4c5254603344
+	google.golang.org/grpc v1.35.0
 	google.golang.org/protobuf v1.25.0
+	grpc-observability v0.0.0-00010101000000-000000000000
+	grpc-tls v0.0.0-00010101000000-000000000000
 )
+
+replace grpc-observability => ../grpc-observability
+
+replace grpc-tls => ../grpc-tls
@@ -4,6 +4,8 @@ import (
 	"log"
 	"net"
 
+	"grpc-tls/tlsconfig"
+
 	"google.golang.org/grpc"
 )
 
@@ -13,7 +15,13 @@ func main() {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	tlsOptions, stopTLS, err := tlsconfig.ServerOptions(tlsconfig.LoadConfig())
+	if err != nil {
+		log.Fatalf("failed to load TLS certificates: %s", err)
+	}
+	defer stopTLS()
+
+	grpcServer := grpc.NewServer(tlsOptions...)
 
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %s", err)
@@ -5,8 +5,11 @@ import (
 	"log"
 	"net"
 
+	"grpc-observability/interceptor"
+	"grpc-tls/tlsconfig"
 	"learn-grpc/chat"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
 )
 
@@ -19,9 +22,21 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	s := chat.Server{}
+	s := chat.InitServer()
 
-	grpcServer := grpc.NewServer()
+	observer, err := interceptor.New(interceptor.Config{Registerer: prometheus.DefaultRegisterer})
+	if err != nil {
+		log.Fatalf("failed to set up interceptors: %s", err)
+	}
+	interceptor.ServeMetrics(":9102")
+
+	tlsOptions, stopTLS, err := tlsconfig.ServerOptions(tlsconfig.LoadConfig())
+	if err != nil {
+		log.Fatalf("failed to load TLS certificates: %s", err)
+	}
+	defer stopTLS()
+
+	grpcServer := grpc.NewServer(append(observer.ServerOptions(), tlsOptions...)...)
 
 	chat.RegisterChatServiceServer(grpcServer, &s)
 
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/suite"
+)
+
+// BackendSuite checks the behaviour every Backend must share, against the real clock
+type BackendSuite struct {
+	suite.Suite
+	backend Backend
+	prefix  string
+}
+
+func (suite *BackendSuite) SetupTest() {
+	// Keys are unique per test so a shared Redis doesn't leak state between runs
+	suite.prefix = "ratelimit-test:" + time.Now().Format(time.RFC3339Nano) + ":"
+}
+
+func (suite *BackendSuite) TestTakeToken() {
+	ctx := context.Background()
+	limit := Limit{Burst: 2, Every: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		wait, err := suite.backend.TakeToken(ctx, suite.prefix+"bucket", limit)
+		suite.NoError(err)
+		suite.Zero(wait)
+	}
+
+	wait, err := suite.backend.TakeToken(ctx, suite.prefix+"bucket", limit)
+	suite.NoError(err)
+	suite.InDelta(time.Minute, wait, float64(time.Second))
+}
+
+func (suite *BackendSuite) TestFailures() {
+	ctx := context.Background()
+	key := suite.prefix + "failures"
+
+	for want := int64(1); want <= 3; want++ {
+		count, err := suite.backend.AddFailure(ctx, key, time.Minute)
+		suite.NoError(err)
+		suite.Equal(want, count)
+	}
+
+	suite.NoError(suite.backend.ResetFailures(ctx, key))
+	count, err := suite.backend.AddFailure(ctx, key, time.Minute)
+	suite.NoError(err)
+	suite.Equal(int64(1), count)
+}
+
+func (suite *BackendSuite) TestLock() {
+	ctx := context.Background()
+	key := suite.prefix + "lock"
+
+	lockedFor, err := suite.backend.LockedFor(ctx, key)
+	suite.NoError(err)
+	suite.Zero(lockedFor)
+
+	suite.NoError(suite.backend.Lock(ctx, key, time.Minute))
+	lockedFor, err = suite.backend.LockedFor(ctx, key)
+	suite.NoError(err)
+	suite.InDelta(time.Minute, lockedFor, float64(time.Second))
+}
+
+func TestMemoryBackend(t *testing.T) {
+	suite.Run(t, &BackendSuite{backend: NewMemoryBackend()})
+}
+
+// TestRedisBackend runs against the Redis in REDIS_ADDR and is skipped when it isn't set
+func TestRedisBackend(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if len(addr) == 0 {
+		t.Skip("REDIS_ADDR is not set")
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	defer rdb.Close()
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	suite.Run(t, &BackendSuite{backend: NewRedisBackend(rdb)})
+}
+
+func TestMemoryBackendSweep(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend := NewMemoryBackendWithClock(func() time.Time { return now }).(*memoryBackend)
+	ctx := context.Background()
+
+	backend.TakeToken(ctx, "idle", Limit{Burst: 1, Every: time.Second})
+	backend.AddFailure(ctx, "failures", time.Second)
+	backend.Lock(ctx, "lock", time.Second)
+
+	now = now.Add(time.Minute)
+	for i := 0; i < 1024; i++ {
+		backend.TakeToken(ctx, "active", Limit{Burst: 1, Every: time.Hour})
+	}
+
+	if _, ok := backend.buckets["idle"]; ok {
+		t.Fatal("expected the refilled bucket to be swept")
+	}
+	if len(backend.failures) != 0 || len(backend.locks) != 0 {
+		t.Fatalf("expected expired entries to be swept, got %v %v", backend.failures, backend.locks)
+	}
+}
@@ -0,0 +1,8 @@
+module ratelimit
+
+go 1.15
+
+require (
+	github.com/go-redis/redis/v8 v8.8.2
+	github.com/stretchr/testify v1.7.0
+)
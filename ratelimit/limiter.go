@@ -0,0 +1,166 @@
+// Package ratelimit throttles login attempts per IP and per account and locks accounts
+// out progressively after repeated failures. Counters live in memory or in Redis.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"time"
+)
+
+// Limit describes a token bucket: Burst tokens, one token refilled Every interval
+type Limit struct {
+	Burst int
+	Every time.Duration
+}
+
+// Backend stores token buckets, failure counters and account locks
+type Backend interface {
+	// TakeToken consumes one token from the bucket and returns how long to wait when it is empty
+	TakeToken(ctx context.Context, key string, limit Limit) (time.Duration, error)
+	// AddFailure increments the failure counter, which expires after window, and returns the new count
+	AddFailure(ctx context.Context, key string, window time.Duration) (int64, error)
+	ResetFailures(ctx context.Context, key string) error
+	Lock(ctx context.Context, key string, duration time.Duration) error
+	// LockedFor returns the remaining lock duration, zero when the key isn't locked
+	LockedFor(ctx context.Context, key string) (time.Duration, error)
+}
+
+// Config is the rate limit and lockout policy
+type Config struct {
+	PerIP      Limit
+	PerAccount Limit
+	// Number of consecutive failures before the account gets locked
+	LockoutThreshold int64
+	// The lock doubles with every failure past the threshold, up to LockoutMax
+	LockoutBase   time.Duration
+	LockoutMax    time.Duration
+	FailureWindow time.Duration
+}
+
+// DefaultConfig is the policy used for login endpoints
+func DefaultConfig() Config {
+	return Config{
+		PerIP:            Limit{Burst: 20, Every: 3 * time.Second},
+		PerAccount:       Limit{Burst: 5, Every: 12 * time.Second},
+		LockoutThreshold: 5,
+		LockoutBase:      time.Minute,
+		LockoutMax:       time.Hour,
+		FailureWindow:    24 * time.Hour,
+	}
+}
+
+// LimitedError is returned when a login attempt is rejected
+type LimitedError struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+func (e *LimitedError) Error() string {
+	return fmt.Sprintf("%s, retry after %d seconds", e.Reason, RetryAfterSeconds(e.RetryAfter))
+}
+
+// RetryAfterSeconds rounds up a duration into the value of a Retry-After header
+func RetryAfterSeconds(duration time.Duration) int64 {
+	return int64(math.Ceil(duration.Seconds()))
+}
+
+// Limiter is a login rate limiter with progressive account lockout
+type Limiter struct {
+	backend Backend
+	config  Config
+	audit   *log.Logger
+}
+
+// NewLimiter creates a limiter, audit events go to stdout when audit is nil
+func NewLimiter(backend Backend, config Config, audit *log.Logger) *Limiter {
+	if audit == nil {
+		audit = log.New(os.Stdout, "[audit] ", log.LstdFlags|log.LUTC)
+	}
+
+	return &Limiter{
+		backend,
+		config,
+		audit,
+	}
+}
+
+// Allow checks the account lock and both token buckets before a login attempt is processed
+func (limiter *Limiter) Allow(ctx context.Context, ip, account string) error {
+	lockedFor, err := limiter.backend.LockedFor(ctx, lockKey(account))
+	if err != nil {
+		return err
+	}
+	if lockedFor > 0 {
+		limiter.audit.Printf("event=login_rejected reason=locked account=%q ip=%q retry_after=%s", account, ip, lockedFor)
+		return &LimitedError{Reason: "Account is temporarily locked", RetryAfter: lockedFor}
+	}
+
+	wait, err := limiter.backend.TakeToken(ctx, "ratelimit:ip:"+ip, limiter.config.PerIP)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		limiter.audit.Printf("event=login_rejected reason=ip_rate_limited account=%q ip=%q retry_after=%s", account, ip, wait)
+		return &LimitedError{Reason: "Too many login attempts", RetryAfter: wait}
+	}
+
+	wait, err = limiter.backend.TakeToken(ctx, "ratelimit:account:"+account, limiter.config.PerAccount)
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		limiter.audit.Printf("event=login_rejected reason=account_rate_limited account=%q ip=%q retry_after=%s", account, ip, wait)
+		return &LimitedError{Reason: "Too many login attempts", RetryAfter: wait}
+	}
+
+	return nil
+}
+
+// Failure records a failed login and locks the account once the threshold is reached
+func (limiter *Limiter) Failure(ctx context.Context, ip, account string) error {
+	failures, err := limiter.backend.AddFailure(ctx, failureKey(account), limiter.config.FailureWindow)
+	if err != nil {
+		return err
+	}
+	limiter.audit.Printf("event=login_failure account=%q ip=%q failures=%d", account, ip, failures)
+
+	if failures < limiter.config.LockoutThreshold {
+		return nil
+	}
+
+	duration := limiter.lockDuration(failures)
+	if err := limiter.backend.Lock(ctx, lockKey(account), duration); err != nil {
+		return err
+	}
+	limiter.audit.Printf("event=account_locked account=%q ip=%q failures=%d duration=%s", account, ip, failures, duration)
+	return &LimitedError{Reason: "Account is temporarily locked", RetryAfter: duration}
+}
+
+// Success clears the failure counter of the account
+func (limiter *Limiter) Success(ctx context.Context, ip, account string) error {
+	limiter.audit.Printf("event=login_success account=%q ip=%q", account, ip)
+	return limiter.backend.ResetFailures(ctx, failureKey(account))
+}
+
+func (limiter *Limiter) lockDuration(failures int64) time.Duration {
+	duration := limiter.config.LockoutBase
+	for i := limiter.config.LockoutThreshold; i < failures && duration < limiter.config.LockoutMax; i++ {
+		duration *= 2
+	}
+	if duration > limiter.config.LockoutMax {
+		duration = limiter.config.LockoutMax
+	}
+	return duration
+}
+
+func failureKey(account string) string {
+	return "ratelimit:failures:" + account
+}
+
+func lockKey(account string) string {
+	return "ratelimit:lock:" + account
+}
@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LimiterSuite struct {
+	suite.Suite
+	now     time.Time
+	limiter *Limiter
+}
+
+func (suite *LimiterSuite) SetupTest() {
+	suite.now = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	backend := NewMemoryBackendWithClock(func() time.Time {
+		return suite.now
+	})
+	config := Config{
+		PerIP:            Limit{Burst: 3, Every: time.Second},
+		PerAccount:       Limit{Burst: 10, Every: time.Second},
+		LockoutThreshold: 3,
+		LockoutBase:      time.Minute,
+		LockoutMax:       4 * time.Minute,
+		FailureWindow:    time.Hour,
+	}
+	suite.limiter = NewLimiter(backend, config, log.New(ioutil.Discard, "", 0))
+}
+
+func (suite *LimiterSuite) retryAfter(err error) time.Duration {
+	var limitedErr *LimitedError
+	suite.True(errors.As(err, &limitedErr), "Expected a LimitedError, got %v", err)
+	if limitedErr == nil {
+		return 0
+	}
+	return limitedErr.RetryAfter
+}
+
+func (suite *LimiterSuite) TestIPBucketRefills() {
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		suite.NoError(suite.limiter.Allow(ctx, "10.0.0.1", "email"))
+	}
+
+	err := suite.limiter.Allow(ctx, "10.0.0.1", "email")
+	suite.Equal(time.Second, suite.retryAfter(err))
+
+	// Another IP has its own bucket
+	suite.NoError(suite.limiter.Allow(ctx, "10.0.0.2", "email"))
+
+	suite.now = suite.now.Add(time.Second)
+	suite.NoError(suite.limiter.Allow(ctx, "10.0.0.1", "email"))
+}
+
+func (suite *LimiterSuite) TestProgressiveLockout() {
+	ctx := context.Background()
+	suite.NoError(suite.limiter.Failure(ctx, "10.0.0.1", "email"))
+	suite.NoError(suite.limiter.Failure(ctx, "10.0.0.1", "email"))
+
+	err := suite.limiter.Failure(ctx, "10.0.0.1", "email")
+	suite.Equal(time.Minute, suite.retryAfter(err))
+	suite.Equal(time.Minute, suite.retryAfter(suite.limiter.Allow(ctx, "10.0.0.2", "email")))
+
+	// Other accounts are not affected
+	suite.NoError(suite.limiter.Allow(ctx, "10.0.0.2", "other"))
+
+	suite.now = suite.now.Add(time.Minute)
+	err = suite.limiter.Failure(ctx, "10.0.0.1", "email")
+	suite.Equal(2*time.Minute, suite.retryAfter(err))
+
+	suite.now = suite.now.Add(2 * time.Minute)
+	err = suite.limiter.Failure(ctx, "10.0.0.1", "email")
+	suite.Equal(4*time.Minute, suite.retryAfter(err))
+
+	// Capped at LockoutMax
+	suite.now = suite.now.Add(4 * time.Minute)
+	err = suite.limiter.Failure(ctx, "10.0.0.1", "email")
+	suite.Equal(4*time.Minute, suite.retryAfter(err))
+}
+
+func (suite *LimiterSuite) TestSuccessResetsFailures() {
+	ctx := context.Background()
+	suite.NoError(suite.limiter.Failure(ctx, "10.0.0.1", "email"))
+	suite.NoError(suite.limiter.Failure(ctx, "10.0.0.1", "email"))
+	suite.NoError(suite.limiter.Success(ctx, "10.0.0.1", "email"))
+
+	suite.NoError(suite.limiter.Failure(ctx, "10.0.0.1", "email"))
+	suite.NoError(suite.limiter.Allow(ctx, "10.0.0.1", "email"))
+}
+
+func (suite *LimiterSuite) TestRetryAfterSeconds() {
+	suite.Equal(int64(1), RetryAfterSeconds(100*time.Millisecond))
+	suite.Equal(int64(60), RetryAfterSeconds(time.Minute))
+}
+
+func TestLimiterSuite(t *testing.T) {
+	suite.Run(t, new(LimiterSuite))
+}
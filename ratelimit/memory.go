@@ -0,0 +1,137 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+type counter struct {
+	count     int64
+	expiresAt time.Time
+}
+
+type memoryBackend struct {
+	mu         sync.Mutex
+	now        func() time.Time
+	buckets    map[string]*bucket
+	failures   map[string]*counter
+	locks      map[string]time.Time
+	operations int
+}
+
+// NewMemoryBackend keeps every counter in the process, suited for a single instance
+func NewMemoryBackend() Backend {
+	return NewMemoryBackendWithClock(time.Now)
+}
+
+// NewMemoryBackendWithClock is a memory backend reading the time from now, for tests
+func NewMemoryBackendWithClock(now func() time.Time) Backend {
+	return &memoryBackend{
+		now:      now,
+		buckets:  make(map[string]*bucket),
+		failures: make(map[string]*counter),
+		locks:    make(map[string]time.Time),
+	}
+}
+
+func (backend *memoryBackend) TakeToken(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	now := backend.now()
+	backend.sweep(now)
+
+	b, ok := backend.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		backend.buckets[key] = b
+	}
+	b.limit = limit
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+float64(now.Sub(b.last))/float64(limit.Every))
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, nil
+	}
+	return time.Duration((1 - b.tokens) * float64(limit.Every)), nil
+}
+
+func (backend *memoryBackend) AddFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	now := backend.now()
+	c, ok := backend.failures[key]
+	if !ok || !now.Before(c.expiresAt) {
+		c = &counter{}
+		backend.failures[key] = c
+	}
+	c.count++
+	c.expiresAt = now.Add(window)
+	return c.count, nil
+}
+
+func (backend *memoryBackend) ResetFailures(ctx context.Context, key string) error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	delete(backend.failures, key)
+	return nil
+}
+
+func (backend *memoryBackend) Lock(ctx context.Context, key string, duration time.Duration) error {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	backend.locks[key] = backend.now().Add(duration)
+	return nil
+}
+
+func (backend *memoryBackend) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	backend.mu.Lock()
+	defer backend.mu.Unlock()
+
+	until, ok := backend.locks[key]
+	if !ok {
+		return 0, nil
+	}
+	remaining := until.Sub(backend.now())
+	if remaining <= 0 {
+		delete(backend.locks, key)
+		return 0, nil
+	}
+	return remaining, nil
+}
+
+// sweep drops full buckets and expired entries every so often so the maps don't grow forever
+func (backend *memoryBackend) sweep(now time.Time) {
+	backend.operations++
+	if backend.operations%1024 != 0 {
+		return
+	}
+
+	for key, b := range backend.buckets {
+		if now.Sub(b.last) >= time.Duration(b.limit.Burst)*b.limit.Every {
+			delete(backend.buckets, key)
+		}
+	}
+	for key, c := range backend.failures {
+		if !now.Before(c.expiresAt) {
+			delete(backend.failures, key)
+		}
+	}
+	for key, until := range backend.locks {
+		if !now.Before(until) {
+			delete(backend.locks, key)
+		}
+	}
+}
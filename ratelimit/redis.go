@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// takeTokenScript refills and takes from the bucket atomically, returning the wait in milliseconds
+var takeTokenScript = redis.NewScript(`
+local burst = tonumber(ARGV[1])
+local every = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) / every)
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+else
+	wait = math.ceil((1 - tokens) * every)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * every))
+return wait
+`)
+
+type redisBackend struct {
+	rdb *redis.Client
+}
+
+// NewRedisBackend shares counters between every instance connected to the same Redis
+func NewRedisBackend(rdb *redis.Client) Backend {
+	return &redisBackend{
+		rdb,
+	}
+}
+
+func (backend *redisBackend) TakeToken(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	wait, err := takeTokenScript.Run(ctx, backend.rdb, []string{key},
+		limit.Burst,
+		limit.Every.Milliseconds(),
+		time.Now().UnixNano()/int64(time.Millisecond),
+	).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(wait) * time.Millisecond, nil
+}
+
+func (backend *redisBackend) AddFailure(ctx context.Context, key string, window time.Duration) (int64, error) {
+	pipe := backend.rdb.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.PExpire(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (backend *redisBackend) ResetFailures(ctx context.Context, key string) error {
+	return backend.rdb.Del(ctx, key).Err()
+}
+
+func (backend *redisBackend) Lock(ctx context.Context, key string, duration time.Duration) error {
+	return backend.rdb.Set(ctx, key, 1, duration).Err()
+}
+
+func (backend *redisBackend) LockedFor(ctx context.Context, key string) (time.Duration, error) {
+	ttl, err := backend.rdb.PTTL(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
@@ -0,0 +1,38 @@
+package blobstore
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"time"
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob is not found")
+	ErrInvalidKey = errors.New("blob key is invalid")
+)
+
+// keyPattern keeps keys usable as file names and url path segments
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9._-]{0,127}$`)
+
+// Blob is an opened blob, seekable so it can be served with range requests
+type Blob interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+type BlobInfo struct {
+	Size int64
+	ModTime time.Time
+}
+
+// BlobStore keeps binary objects addressed by a key
+type BlobStore interface {
+	Put(key string, data io.Reader) error
+	Open(key string) (Blob, *BlobInfo, error)
+	Delete(key string) error
+}
+
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
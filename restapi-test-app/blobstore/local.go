@@ -0,0 +1,80 @@
+package blobstore
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type localBlobStore struct {
+	dir string
+}
+
+// InitializeLocalBlobStore returns a BlobStore keeping every blob as a file in dir
+func InitializeLocalBlobStore(dir string) (BlobStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &localBlobStore{dir}, nil
+}
+
+// Put writes to a temporary file first, so a blob is never read half written
+func (store *localBlobStore) Put(key string, data io.Reader) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	file, err := ioutil.TempFile(store.dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), filepath.Join(store.dir, key))
+}
+
+func (store *localBlobStore) Open(key string) (Blob, *BlobInfo, error) {
+	if !ValidKey(key) {
+		return nil, nil, ErrBlobNotFound
+	}
+
+	file, err := os.Open(filepath.Join(store.dir, key))
+	if os.IsNotExist(err) {
+		return nil, nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, &BlobInfo{Size: stat.Size(), ModTime: stat.ModTime()}, nil
+}
+
+func (store *localBlobStore) Delete(key string) error {
+	if !ValidKey(key) {
+		return ErrInvalidKey
+	}
+
+	err := os.Remove(filepath.Join(store.dir, key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,64 @@
+package blobstore
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+type localBlobStoreSuite struct {
+	suite.Suite
+	dir string
+	store BlobStore
+}
+
+func (suite *localBlobStoreSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "blobstore")
+	suite.NoError(err)
+
+	store, err := InitializeLocalBlobStore(dir)
+	suite.NoError(err)
+
+	suite.dir = dir
+	suite.store = store
+}
+
+func (suite *localBlobStoreSuite) TearDownTest() {
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *localBlobStoreSuite) TestPutOpenDelete_Positive() {
+	err := suite.store.Put("image.png", bytes.NewBufferString("content"))
+	suite.NoError(err, "no error when put a blob with a valid key")
+
+	blob, info, err := suite.store.Open("image.png")
+	suite.NoError(err, "no error when open an existing blob")
+	content, err := ioutil.ReadAll(blob)
+	blob.Close()
+	suite.NoError(err)
+	suite.Equal("content", string(content))
+	suite.Equal(int64(7), info.Size)
+
+	err = suite.store.Delete("image.png")
+	suite.NoError(err, "no error when delete an existing blob")
+
+	_, _, err = suite.store.Open("image.png")
+	suite.Equal(ErrBlobNotFound, err, "deleted blob is not found")
+}
+
+func (suite *localBlobStoreSuite) TestInvalidKey_Negative() {
+	err := suite.store.Put("../escape.png", bytes.NewBufferString("content"))
+	suite.Equal(ErrInvalidKey, err, "keys can not leave the directory")
+
+	_, _, err = suite.store.Open("../escape.png")
+	suite.Equal(ErrBlobNotFound, err)
+
+	err = suite.store.Put(".hidden", bytes.NewBufferString("content"))
+	suite.Equal(ErrInvalidKey, err)
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	suite.Run(t, new(localBlobStoreSuite))
+}
@@ -0,0 +1,119 @@
+package broker
+
+import (
+	"restapi-tested-app/entities"
+	"sync"
+)
+
+// subscriberBufferSize is how many events a subscriber may fall behind before it is dropped.
+// A dropped client reconnects with Last-Event-ID and catches up from the replay buffer.
+const subscriberBufferSize = 64
+
+type subscriber struct {
+	events chan entities.TweetEvent
+}
+
+type tweetBroker struct {
+	mutex sync.Mutex
+	lastID int64
+	bufferSize int
+	buffer []entities.TweetEvent
+	subscribers map[*subscriber]struct{}
+}
+
+// TweetBroker fans tweet events out to the subscribers of this process
+type TweetBroker interface {
+	// Publish numbers the event if it has no id yet, keeps it for replay and sends it to every subscriber.
+	// An error means the event could not be handed over and no subscriber receives it.
+	Publish(event entities.TweetEvent) error
+	// Subscribe returns the buffered events after lastEventID and a channel of the following ones.
+	// The channel is closed when the subscriber falls too far behind; cancel must always be called.
+	Subscribe(lastEventID int64) (replay []entities.TweetEvent, events <-chan entities.TweetEvent, cancel func())
+}
+
+func InitializeTweetBroker(bufferSize int) TweetBroker {
+	return newTweetBroker(bufferSize)
+}
+
+func newTweetBroker(bufferSize int) *tweetBroker {
+	return &tweetBroker{
+		bufferSize: bufferSize,
+		buffer: make([]entities.TweetEvent, 0, bufferSize),
+		subscribers: map[*subscriber]struct{}{},
+	}
+}
+
+func (broker *tweetBroker) Publish(event entities.TweetEvent) error {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	if event.ID == 0 {
+		event.ID = broker.lastID + 1
+	}
+	if event.ID > broker.lastID {
+		broker.lastID = event.ID
+	}
+
+	if broker.bufferSize > 0 {
+		if len(broker.buffer) == broker.bufferSize {
+			copy(broker.buffer, broker.buffer[1:])
+			broker.buffer = broker.buffer[:len(broker.buffer)-1]
+		}
+		broker.buffer = append(broker.buffer, event)
+	}
+
+	for sub := range broker.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			delete(broker.subscribers, sub)
+			close(sub.events)
+		}
+	}
+
+	return nil
+}
+
+func (broker *tweetBroker) Subscribe(lastEventID int64) ([]entities.TweetEvent, <-chan entities.TweetEvent, func()) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	sub := &subscriber{events: make(chan entities.TweetEvent, subscriberBufferSize)}
+	broker.subscribers[sub] = struct{}{}
+
+	cancel := func() {
+		broker.mutex.Lock()
+		defer broker.mutex.Unlock()
+
+		if _, ok := broker.subscribers[sub]; ok {
+			delete(broker.subscribers, sub)
+			close(sub.events)
+		}
+	}
+
+	return broker.replay(lastEventID), sub.events, cancel
+}
+
+// replay returns the buffered events published after the one with lastEventID.
+// Ids coming from several instances are not strictly ordered, so the position of that event
+// in the buffer is used when it is still there, otherwise every event with a higher id is returned.
+func (broker *tweetBroker) replay(lastEventID int64) []entities.TweetEvent {
+	if lastEventID <= 0 {
+		return []entities.TweetEvent{}
+	}
+
+	for i, event := range broker.buffer {
+		if event.ID == lastEventID {
+			return append([]entities.TweetEvent{}, broker.buffer[i+1:]...)
+		}
+	}
+
+	result := []entities.TweetEvent{}
+	for _, event := range broker.buffer {
+		if event.ID > lastEventID {
+			result = append(result, event)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package broker
+
+import (
+	"github.com/stretchr/testify/suite"
+	"restapi-tested-app/entities"
+	"testing"
+)
+
+type tweetBrokerSuite struct {
+	suite.Suite
+	broker TweetBroker
+}
+
+func (suite *tweetBrokerSuite) SetupTest() {
+	suite.broker = InitializeTweetBroker(3)
+}
+
+func (suite *tweetBrokerSuite) publish(texts ...string) {
+	for _, text := range texts {
+		suite.broker.Publish(entities.TweetEvent{
+			Type: entities.TweetCreated,
+			Tweet: entities.Tweet{Text: text},
+		})
+	}
+}
+
+func (suite *tweetBrokerSuite) TestSubscribe_ReceivesNewEvents_Positive() {
+	replay, events, cancel := suite.broker.Subscribe(0)
+	defer cancel()
+	suite.Empty(replay, "nothing to replay for a new subscriber")
+
+	suite.publish("first", "second")
+
+	event := <-events
+	suite.Equal(int64(1), event.ID, "events are numbered")
+	suite.Equal("first", event.Tweet.Text)
+	event = <-events
+	suite.Equal(int64(2), event.ID)
+}
+
+func (suite *tweetBrokerSuite) TestSubscribe_ResumeFromLastEventID_Positive() {
+	suite.publish("1", "2", "3", "4")
+
+	// only the last three events are buffered
+	replay, _, cancel := suite.broker.Subscribe(2)
+	defer cancel()
+	suite.Len(replay, 2)
+	suite.Equal(int64(3), replay[0].ID)
+	suite.Equal(int64(4), replay[1].ID)
+
+	replay, _, cancel = suite.broker.Subscribe(1)
+	defer cancel()
+	suite.Len(replay, 3, "events older than the buffer are lost, the rest is replayed")
+}
+
+func (suite *tweetBrokerSuite) TestPublish_KeepsIdsFromOtherInstances_Positive() {
+	suite.broker.Publish(entities.TweetEvent{ID: 10, Type: entities.TweetCreated})
+	suite.broker.Publish(entities.TweetEvent{ID: 9, Type: entities.TweetCreated})
+	suite.broker.Publish(entities.TweetEvent{ID: 11, Type: entities.TweetCreated})
+
+	// replay goes by position, not by id, when the last event is still buffered
+	replay, _, cancel := suite.broker.Subscribe(10)
+	defer cancel()
+	suite.Len(replay, 2)
+	suite.Equal(int64(9), replay[0].ID)
+}
+
+func (suite *tweetBrokerSuite) TestPublish_DropsSlowSubscriber_Negative() {
+	_, events, cancel := suite.broker.Subscribe(0)
+	defer cancel()
+
+	for i := 0; i <= subscriberBufferSize; i++ {
+		suite.publish("text")
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	suite.Equal(subscriberBufferSize, received, "the channel is closed once the subscriber falls behind")
+}
+
+func TestTweetBroker(t *testing.T) {
+	suite.Run(t, new(tweetBrokerSuite))
+}
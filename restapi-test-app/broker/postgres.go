@@ -0,0 +1,110 @@
+package broker
+
+import (
+	"encoding/json"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"log"
+	"restapi-tested-app/entities"
+	"time"
+)
+
+const (
+	notifyChannel = "tweet_events"
+	// postgres rejects NOTIFY payloads of 8000 bytes or more
+	maxNotifyPayload = 7900
+)
+
+type notification struct {
+	Event entities.TweetEvent `json:"event"`
+	// Partial is set when the tweet was too large for the payload and has to be loaded by the listener
+	Partial bool `json:"partial,omitempty"`
+}
+
+type postgresTweetBroker struct {
+	*tweetBroker
+	db *sqlx.DB
+	loadTweet func(id int) (*entities.Tweet, error)
+}
+
+// InitializePostgresTweetBroker returns a broker that publishes through Postgres NOTIFY and delivers what it
+// LISTENs to, so every instance connected to the same database sends the same events with the same ids.
+// loadTweet is used to fetch tweets too large to fit in a notification.
+func InitializePostgresTweetBroker(db *sqlx.DB, connectionString string, bufferSize int, loadTweet func(id int) (*entities.Tweet, error)) (TweetBroker, error) {
+	listener := pq.NewListener(connectionString, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Println("Tweet stream listener error", err)
+		}
+	})
+
+	err := listener.Listen(notifyChannel)
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	broker := &postgresTweetBroker{
+		tweetBroker: newTweetBroker(bufferSize),
+		db: db,
+		loadTweet: loadTweet,
+	}
+	go broker.listen(listener)
+
+	return broker, nil
+}
+
+// Publish sends the event through NOTIFY, it reaches the subscribers of this instance only once Postgres
+// delivers it back, so the ids stay the same on every instance
+func (broker *postgresTweetBroker) Publish(event entities.TweetEvent) error {
+	return broker.notify(event)
+}
+
+func (broker *postgresTweetBroker) notify(event entities.TweetEvent) error {
+	err := broker.db.Get(&event.ID, `SELECT nextval('tweet_events_id_seq');`)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(notification{Event: event})
+	if err != nil {
+		return err
+	}
+
+	if len(payload) > maxNotifyPayload {
+		event.Tweet = entities.Tweet{ID: event.Tweet.ID}
+		payload, err = json.Marshal(notification{Event: event, Partial: true})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = broker.db.Exec(`SELECT pg_notify($1, $2);`, notifyChannel, string(payload))
+	return err
+}
+
+func (broker *postgresTweetBroker) listen(listener *pq.Listener) {
+	for n := range listener.Notify {
+		// a nil notification means the connection was re-established, whatever was sent meanwhile is lost
+		if n == nil {
+			continue
+		}
+
+		var payload notification
+		err := json.Unmarshal([]byte(n.Extra), &payload)
+		if err != nil {
+			log.Println("Error while reading tweet event", err)
+			continue
+		}
+
+		if payload.Partial {
+			tweet, err := broker.loadTweet(payload.Event.Tweet.ID)
+			if err != nil {
+				log.Println("Error while loading tweet of event", err)
+			} else {
+				payload.Event.Tweet = *tweet
+			}
+		}
+
+		broker.tweetBroker.Publish(payload.Event)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"restapi-tested-app/config"
+	"restapi-tested-app/repositories"
+)
+
+// batchSize is the number of tweets indexed per transaction
+const batchSize = 500
+
+// reindex-tweets fills tweet_hashtags and tweet_mentions for tweets written before they existed.
+// It is safe to run more than once, every tweet's rows are rebuilt from its text.
+func main() {
+	db := config.ConnectDB(config.GetConfig())
+	defer db.Close()
+
+	tweetRepository := repositories.InitializeTweetRepository(db)
+
+	lastID, total := 0, 0
+	for {
+		nextID, err := tweetRepository.ReindexTweets(lastID, batchSize)
+		if err != nil {
+			log.Fatalf("reindexing tweets after id %d: %v", lastID, err)
+		}
+		if nextID == 0 {
+			break
+		}
+
+		total++
+		lastID = nextID
+		log.Printf("indexed batch %d, up to tweet %d", total, lastID)
+	}
+
+	log.Printf("done, indexed %d batches", total)
+}
@@ -1,11 +1,13 @@
 package config
 
 import (
+	"fmt"
 	"github.com/joho/godotenv"
 	"log"
 	"os"
 	"restapi-tested-app/entities"
 	"strconv"
+	"time"
 )
 
 func GetConfig() *entities.Config {
@@ -35,7 +37,61 @@ func GetConfig() *entities.Config {
 		},
 		TimeZone:  "Asia/Jakarta",
 		SecretKey: os.Getenv("SECRET_KEY"),
+		Trending: entities.TrendingConfig{
+			Window:          getDuration("TRENDING_WINDOW", 24*time.Hour),
+			HalfLife:        getDuration("TRENDING_HALF_LIFE", 6*time.Hour),
+			RefreshInterval: getDuration("TRENDING_REFRESH_INTERVAL", time.Minute),
+		},
+		Stream: entities.StreamConfig{
+			Backend:    getString("STREAM_BACKEND", "memory"),
+			BufferSize: getInt("STREAM_BUFFER_SIZE", 256),
+		},
+		Media: entities.MediaConfig{
+			Dir:         getString("MEDIA_DIR", "media"),
+			MaxSize:     int64(getInt("MEDIA_MAX_SIZE", 5<<20)),
+			MaxPerTweet: getInt("MEDIA_MAX_PER_TWEET", 4),
+		},
 	}
 
 	return config
 }
+
+// getString reads a value from the environment, falling back to the default when it is unset
+func getString(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// getInt reads a number from the environment, falling back to the default when it is unset
+func getInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return number
+}
+
+// getDuration reads a positive duration such as "90m" from the environment, falling back to the default when it is unset
+func getDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		panic(fmt.Sprintf("%s must be a positive duration such as \"1m\", got %q", key, value))
+	}
+
+	return duration
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDuration(t *testing.T) {
+	defer os.Unsetenv("TEST_DURATION")
+
+	assert.Equal(t, time.Minute, getDuration("TEST_DURATION", time.Minute), "default when unset")
+
+	os.Setenv("TEST_DURATION", "90s")
+	assert.Equal(t, 90*time.Second, getDuration("TEST_DURATION", time.Minute))
+
+	for _, value := range []string{"0", "-1m", "soon"} {
+		os.Setenv("TEST_DURATION", value)
+		assert.PanicsWithValue(t, `TEST_DURATION must be a positive duration such as "1m", got "`+value+`"`, func() {
+			getDuration("TEST_DURATION", time.Minute)
+		}, value)
+	}
+}
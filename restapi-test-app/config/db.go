@@ -16,10 +16,9 @@ func ConnectDB(config *entities.Config) *sqlx.DB {
 	return DB
 }
 
-func getDBConnection(config *entities.Config) *sqlx.DB {
-	var dbConnectionStr string
-
-	dbConnectionStr = fmt.Sprintf(
+// ConnectionString returns the postgres connection string of the configured database
+func ConnectionString(config *entities.Config) string {
+	return fmt.Sprintf(
 		"host=%s port=%d dbname=%s user=%s password=%s sslmode=disable",
 		config.Database.Host,
 		config.Database.Port,
@@ -27,8 +26,10 @@ func getDBConnection(config *entities.Config) *sqlx.DB {
 		config.Database.Username,
 		config.Database.Password,
 	)
+}
 
-	db, err := sqlx.Open("postgres", dbConnectionStr)
+func getDBConnection(config *entities.Config) *sqlx.DB {
+	db, err := sqlx.Open("postgres", ConnectionString(config))
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+)
+
+// AttachmentsPath is where the blobs of attachments are downloaded from
+const AttachmentsPath = "/attachments/"
+
+type Attachment struct {
+	ID int `json:"id" db:"id"`
+	TweetID int `json:"tweetId" db:"tweet_id"`
+	ContentType string `json:"contentType" db:"content_type"`
+	Size int64 `json:"size" db:"size"`
+	Width int `json:"width" db:"width"`
+	Height int `json:"height" db:"height"`
+	BlobKey string `json:"-" db:"blob_key"`
+	ThumbnailKey string `json:"-" db:"thumbnail_key"`
+	URL string `json:"url" db:"-"`
+	ThumbnailURL string `json:"thumbnailUrl" db:"-"`
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+}
+
+// SetURLs fills the download urls from the blob keys
+func (attachment *Attachment) SetURLs() {
+	attachment.URL = AttachmentsPath + attachment.BlobKey
+	attachment.ThumbnailURL = AttachmentsPath + attachment.ThumbnailKey
+}
+
+type MediaConfig struct {
+	Dir string
+	MaxSize int64
+	MaxPerTweet int
+}
@@ -4,6 +4,9 @@ type Config struct {
 	Database  DatabaseConfig
 	TimeZone  string
 	SecretKey string
+	Trending  TrendingConfig
+	Stream    StreamConfig
+	Media     MediaConfig
 }
 
 type DatabaseConfig struct {
@@ -12,4 +15,9 @@ type DatabaseConfig struct {
 	DbName   string
 	Username string
 	Password string
-}
\ No newline at end of file
+}
+type StreamConfig struct {
+	// Backend is either "memory" for a single instance or "postgres" to fan out through LISTEN/NOTIFY
+	Backend    string
+	BufferSize int
+}
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"time"
+)
+
+const (
+	// SearchFullText matches the words of the query using the full-text index
+	SearchFullText = "fulltext"
+	// SearchTrigram matches similar text, used when the full-text search finds nothing (e.g. typos or partial words)
+	SearchTrigram = "trigram"
+)
+
+// SearchOptions are the parameters of a tweet search as sent by the client
+type SearchOptions struct {
+	Text string
+	Username string
+	From *time.Time
+	To *time.Time
+	Cursor string
+	Limit int
+}
+
+// SearchQuery is a page of a search as run by the repository, with the cursor decoded
+type SearchQuery struct {
+	Mode string
+	Text string
+	Username string
+	From *time.Time
+	To *time.Time
+	// AfterRank and AfterID are the rank and id of the last tweet of the previous page
+	AfterRank *float64
+	AfterID int
+	Limit int
+}
+
+type SearchHit struct {
+	Tweet
+	Rank float64 `json:"rank" db:"rank"`
+	// Highlight is an HTML escaped snippet of the text with the matches wrapped in <mark> tags
+	Highlight string `json:"highlight" db:"highlight"`
+}
+
+type SearchResult struct {
+	Tweets []SearchHit `json:"tweets"`
+	NextCursor string `json:"nextCursor"`
+}
@@ -0,0 +1,25 @@
+package entities
+
+import (
+	"time"
+)
+
+// HashtagCount is the number of times a hashtag was used within one hour
+type HashtagCount struct {
+	Tag string `db:"tag"`
+	Bucket time.Time `db:"bucket"`
+	Count int `db:"count"`
+}
+
+// TrendingHashtag is a hashtag ranked by its decayed number of uses
+type TrendingHashtag struct {
+	Tag string `json:"tag"`
+	Count int `json:"count"`
+	Score float64 `json:"score"`
+}
+
+type TrendingConfig struct {
+	Window time.Duration
+	HalfLife time.Duration
+	RefreshInterval time.Duration
+}
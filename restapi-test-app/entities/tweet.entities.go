@@ -8,8 +8,24 @@ type Tweet struct {
 	ID int `json:"id" db:"id"`
 	Username string `json:"username" db:"username"`
 	Text string `json:"text" db:"text"`
+	ParentID *int `json:"parentId" db:"parent_id"`
+	LikeCount int `json:"likeCount" db:"like_count"`
+	ReplyCount int `json:"replyCount" db:"reply_count"`
+	RetweetCount int `json:"retweetCount" db:"retweet_count"`
 	CreatedAt time.Time `json:"createdAt" db:"created_at"`
 	ModifiedAt time.Time `json:"modifiedAt" db:"modified_at"`
+	Attachments []Attachment `json:"attachments,omitempty" db:"-"`
+}
+
+// TweetThread is a tweet together with its nested replies
+type TweetThread struct {
+	Tweet
+	Replies []*TweetThread `json:"replies"`
+}
+
+// TweetReaction is the request body used to like or retweet a tweet
+type TweetReaction struct {
+	Username string `json:"username" binding:"required"`
 }
 
 func (tweet *Tweet) IsValid() bool {
@@ -17,4 +33,18 @@ func (tweet *Tweet) IsValid() bool {
 		return false
 	}
 	return true
-}
\ No newline at end of file
+}
+
+const (
+	TweetCreated = "created"
+	TweetUpdated = "updated"
+	TweetDeleted = "deleted"
+)
+
+// TweetEvent is a change of a tweet pushed to the clients of the tweet stream.
+// Deleted events only carry the id of the tweet.
+type TweetEvent struct {
+	ID int64 `json:"id"`
+	Type string `json:"type"`
+	Tweet Tweet `json:"tweet"`
+}
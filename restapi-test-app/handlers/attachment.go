@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/usecases"
+	"strconv"
+)
+
+// multipartOverhead leaves room for the boundaries and headers of the form around the file
+const multipartOverhead = 1 << 20
+
+type attachmentHandler struct {
+	attachmentUsecase usecases.AttachmentUsecase
+	maxUploadSize int64
+}
+
+type AttachmentHandler interface {
+	AddAttachment(ctx *gin.Context) *entities.AppResult
+	DownloadAttachment(ctx *gin.Context)
+}
+
+func InitializeAttachmentHandler(usecase usecases.AttachmentUsecase, maxFileSize int64) AttachmentHandler {
+	return &attachmentHandler{usecase, maxFileSize + multipartOverhead}
+}
+
+// AddAttachment reads the image from the "file" field of a multipart form
+func (handler *attachmentHandler) AddAttachment(ctx *gin.Context) *entities.AppResult {
+	var result entities.AppResult
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		result.Err = errors.New("id must be a number")
+		result.StatusCode = http.StatusBadRequest
+		return &result
+	}
+
+	if ctx.Request.ContentLength > handler.maxUploadSize {
+		result.Err = errors.New("file is too large")
+		result.StatusCode = http.StatusRequestEntityTooLarge
+		return &result
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, handler.maxUploadSize)
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		result.Err = errors.New("file is required")
+		result.StatusCode = http.StatusBadRequest
+		return &result
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		result.Err = err
+		result.StatusCode = http.StatusBadRequest
+		return &result
+	}
+	defer file.Close()
+
+	attachment, err := handler.attachmentUsecase.AddAttachment(id, file)
+	if err == nil {
+		result.Message = fmt.Sprintf("Success to attach file to tweet with id %d", id)
+		result.StatusCode = http.StatusCreated
+		result.Data = attachment
+	} else {
+		result.Err = err.(*entities.AppError).Err
+		result.StatusCode = err.(*entities.AppError).StatusCode
+	}
+
+	return &result
+}
+
+// DownloadAttachment serves the blob with http.ServeContent, which handles Range and conditional requests
+func (handler *attachmentHandler) DownloadAttachment(ctx *gin.Context) {
+	key := ctx.Param("key")
+
+	blob, info, err := handler.attachmentUsecase.OpenAttachment(key)
+	if err != nil {
+		ctx.JSON(err.(*entities.AppError).StatusCode, entities.Response{
+			Success: false,
+			Message: err.(*entities.AppError).Error(),
+			Data: struct{}{},
+		})
+		return
+	}
+	defer blob.Close()
+
+	// blobs are never modified, a new upload gets a new key
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(ctx.Writer, ctx.Request, key, info.ModTime, blob)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"restapi-tested-app/blobstore"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/mocks"
+	"restapi-tested-app/utils"
+	"strings"
+	"testing"
+	"time"
+)
+
+type attachmentHandlerSuite struct {
+	suite.Suite
+	usecase *mocks.AttachmentUsecase
+	testingServer *httptest.Server
+}
+
+// blob wraps a reader so it can be returned as an opened blob
+type blob struct {
+	*strings.Reader
+}
+
+func (blob) Close() error {
+	return nil
+}
+
+func (suite *attachmentHandlerSuite) SetupSuite() {
+	usecase := new(mocks.AttachmentUsecase)
+	handler := InitializeAttachmentHandler(usecase, 1<<20)
+
+	router := gin.Default()
+	router.POST("/tweet/:id/attachments", utils.ServeHTTP(handler.AddAttachment))
+	router.GET("/attachments/:key", handler.DownloadAttachment)
+
+	suite.testingServer = httptest.NewServer(router)
+	suite.usecase = usecase
+}
+
+func (suite *attachmentHandlerSuite) TearDownSuite() {
+	defer suite.testingServer.Close()
+}
+
+func (suite *attachmentHandlerSuite) TestAddAttachment_Positive() {
+	attachment := entities.Attachment{
+		ID: 1,
+		TweetID: 1,
+		ContentType: "image/png",
+		URL: "/attachments/key.png",
+		ThumbnailURL: "/attachments/key_thumb.png",
+	}
+	suite.usecase.On("AddAttachment", 1, mock.Anything).Return(&attachment, nil)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", "image.png")
+	suite.NoError(err)
+	part.Write([]byte("image content"))
+	writer.Close()
+
+	response, err := http.Post(fmt.Sprintf("%s/tweet/1/attachments", suite.testingServer.URL), writer.FormDataContentType(), &body)
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	responseBody := struct {
+		Message string `json:"message"`
+		Data entities.Attachment `json:"data"`
+	}{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusCreated, response.StatusCode)
+	suite.Equal("Success to attach file to tweet with id 1", responseBody.Message)
+	suite.Equal("/attachments/key_thumb.png", responseBody.Data.ThumbnailURL)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *attachmentHandlerSuite) TestAddAttachment_MissingFile_Negative() {
+	response, err := http.Post(fmt.Sprintf("%s/tweet/1/attachments", suite.testingServer.URL), "application/json", bytes.NewBufferString("{}"))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func (suite *attachmentHandlerSuite) TestDownloadAttachment_Range_Positive() {
+	info := blobstore.BlobInfo{Size: 10, ModTime: time.Now()}
+	suite.usecase.On("OpenAttachment", "key.png").Return(blob{strings.NewReader("0123456789")}, &info, nil)
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/attachments/key.png", suite.testingServer.URL), nil)
+	suite.NoError(err)
+	request.Header.Set("Range", "bytes=2-5")
+
+	response, err := http.DefaultClient.Do(request)
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	content, err := ioutil.ReadAll(response.Body)
+	suite.NoError(err)
+	suite.Equal(http.StatusPartialContent, response.StatusCode)
+	suite.Equal("bytes 2-5/10", response.Header.Get("Content-Range"))
+	suite.Equal("image/png", response.Header.Get("Content-Type"))
+	suite.Equal("2345", string(content))
+}
+
+func (suite *attachmentHandlerSuite) TestDownloadAttachment_NotFound_Negative() {
+	suite.usecase.On("OpenAttachment", "missing.png").Return(nil, nil, &entities.AppError{
+		Err: fmt.Errorf("attachment is not found"),
+		StatusCode: http.StatusNotFound,
+	})
+
+	response, err := http.Get(fmt.Sprintf("%s/attachments/missing.png", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusNotFound, response.StatusCode)
+}
+
+func TestAttachmentHandler(t *testing.T) {
+	suite.Run(t, new(attachmentHandlerSuite))
+}
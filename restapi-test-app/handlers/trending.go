@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/usecases"
+	"strconv"
+)
+
+const (
+	defaultTrendingLimit = 10
+	maxTrendingLimit = 50
+)
+
+type trendingHandler struct {
+	trendingUsecase usecases.TrendingUsecase
+}
+
+type TrendingHandler interface {
+	GetTrendingHashtags(ctx *gin.Context) *entities.AppResult
+}
+
+func InitializeTrendingHandler(usecase usecases.TrendingUsecase) TrendingHandler {
+	return &trendingHandler{usecase}
+}
+
+func (handler *trendingHandler) GetTrendingHashtags(ctx *gin.Context) *entities.AppResult {
+	var result entities.AppResult
+
+	limit := defaultTrendingLimit
+	if value := ctx.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > maxTrendingLimit {
+			result.Err = errors.New("limit must be a number between 1 and 50")
+			result.StatusCode = http.StatusBadRequest
+			result.Data = []struct{}{}
+			return &result
+		}
+		limit = parsed
+	}
+
+	hashtags, err := handler.trendingUsecase.GetTrendingHashtags(limit)
+	if err == nil {
+		result.StatusCode = http.StatusOK
+		result.Message = "Success to get trending hashtags"
+		result.Data = hashtags
+	} else {
+		result.StatusCode = err.(*entities.AppError).StatusCode
+		result.Err = err.(*entities.AppError).Err
+		result.Data = []struct{}{}
+	}
+
+	return &result
+}
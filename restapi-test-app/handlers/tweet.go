@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
 	"restapi-tested-app/entities"
 	"restapi-tested-app/usecases"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type tweetHandler struct {
@@ -20,6 +23,15 @@ type TweetHandler interface {
 	CreateTweet(ctx *gin.Context) *entities.AppResult
 	UpdateTweet(ctx *gin.Context) *entities.AppResult
 	DeleteTweet(ctx *gin.Context) *entities.AppResult
+	GetTweetThread(ctx *gin.Context) *entities.AppResult
+	ReplyTweet(ctx *gin.Context) *entities.AppResult
+	LikeTweet(ctx *gin.Context) *entities.AppResult
+	UnlikeTweet(ctx *gin.Context) *entities.AppResult
+	Retweet(ctx *gin.Context) *entities.AppResult
+	Unretweet(ctx *gin.Context) *entities.AppResult
+	GetTweetsByHashtag(ctx *gin.Context) *entities.AppResult
+	GetTweetsByMention(ctx *gin.Context) *entities.AppResult
+	StreamTweets(ctx *gin.Context)
 }
 
 func InitializeTweetHandler(usecase usecases.TweetUsecase) TweetHandler {
@@ -70,29 +82,71 @@ func (handler *tweetHandler) GetTweetByID(ctx *gin.Context) *entities.AppResult
 	return &result
 }
 
+// SearchTweetByText reads the keyword from "search" and the optional "username", "from" and "to" (RFC 3339),
+// "cursor" and "limit" query parameters
 func (handler *tweetHandler) SearchTweetByText(ctx *gin.Context) *entities.AppResult {
 	searchParam := ctx.Query("search")
 	var result entities.AppResult
 
-	tweets, err := handler.tweetUsecase.SearchTweetByText(searchParam)
+	options, err := searchOptions(ctx)
+	if err != nil {
+		result.StatusCode = http.StatusBadRequest
+		result.Err = err
+		result.Data = struct{}{}
+		return &result
+	}
+
+	found, err := handler.tweetUsecase.SearchTweetByText(options)
 	if err == nil {
 		result.StatusCode = http.StatusOK
-		if len(*tweets) == 0 {
+		if len(found.Tweets) == 0 {
 			result.Message = fmt.Sprintf("No tweets found with a searching keyword %s", searchParam)
-			result.Data = []struct{}{}
 		} else {
 			result.Message = fmt.Sprintf("Success to get all tweets with a searching keyword %s", searchParam)
-			result.Data = tweets
 		}
+		result.Data = found
 	} else {
-		result.StatusCode = http.StatusInternalServerError
-		result.Err = err
-		result.Data = []struct{}{}
+		result.StatusCode = err.(*entities.AppError).StatusCode
+		result.Err = err.(*entities.AppError).Err
+		result.Data = struct{}{}
 	}
 
 	return &result
 }
 
+func searchOptions(ctx *gin.Context) (entities.SearchOptions, error) {
+	options := entities.SearchOptions{
+		Text: ctx.Query("search"),
+		Username: ctx.Query("username"),
+		Cursor: ctx.Query("cursor"),
+	}
+
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			return options, errors.New("limit must be a number")
+		}
+		options.Limit = limit
+	}
+
+	for _, param := range []struct {
+		name string
+		target **time.Time
+	}{{"from", &options.From}, {"to", &options.To}} {
+		value := ctx.Query(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return options, fmt.Errorf("%s must be a RFC 3339 timestamp", param.name)
+		}
+		*param.target = &parsed
+	}
+
+	return options, nil
+}
+
 func (handler *tweetHandler) CreateTweet(ctx *gin.Context) *entities.AppResult {
 	var tweet entities.Tweet
 	var result entities.AppResult
@@ -156,5 +210,141 @@ func (handler *tweetHandler) DeleteTweet(ctx *gin.Context) *entities.AppResult {
 		result.StatusCode = err.(*entities.AppError).StatusCode
 	}
 
+	return &result
+}
+
+func (handler *tweetHandler) GetTweetThread(ctx *gin.Context) *entities.AppResult {
+	var result entities.AppResult
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		result.Err = errors.New("id must be a number")
+		result.StatusCode = http.StatusBadRequest
+		result.Data = struct{}{}
+		return &result
+	}
+
+	thread, err := handler.tweetUsecase.GetTweetThread(id)
+	if err == nil {
+		result.StatusCode = http.StatusOK
+		result.Message = fmt.Sprintf("Success to get thread of tweet with id %d", id)
+		result.Data = thread
+	} else {
+		result.StatusCode = err.(*entities.AppError).StatusCode
+		result.Err = err.(*entities.AppError).Err
+		result.Data = struct{}{}
+	}
+
+	return &result
+}
+
+func (handler *tweetHandler) ReplyTweet(ctx *gin.Context) *entities.AppResult {
+	var tweet entities.Tweet
+	var result entities.AppResult
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		result.Err = errors.New("id must be a number")
+		result.StatusCode = http.StatusBadRequest
+		return &result
+	}
+
+	if err := ctx.ShouldBindJSON(&tweet); err != nil {
+		result.Err = err
+		result.Message = "username and text can not be empty"
+		result.StatusCode = http.StatusBadRequest
+		return &result
+	}
+
+	err = handler.tweetUsecase.ReplyTweet(id, &tweet)
+	if err == nil {
+		result.Message = fmt.Sprintf("Success to reply tweet with id %d", id)
+		result.StatusCode = http.StatusCreated
+	} else {
+		result.Err = err.(*entities.AppError).Err
+		result.Message = err.(*entities.AppError).Error()
+		result.StatusCode = err.(*entities.AppError).StatusCode
+	}
+
+	return &result
+}
+
+func (handler *tweetHandler) LikeTweet(ctx *gin.Context) *entities.AppResult {
+	return handler.react(ctx, handler.tweetUsecase.LikeTweet, "Success to like tweet with id %d")
+}
+
+func (handler *tweetHandler) UnlikeTweet(ctx *gin.Context) *entities.AppResult {
+	return handler.react(ctx, handler.tweetUsecase.UnlikeTweet, "Success to unlike tweet with id %d")
+}
+
+func (handler *tweetHandler) Retweet(ctx *gin.Context) *entities.AppResult {
+	return handler.react(ctx, handler.tweetUsecase.Retweet, "Success to retweet tweet with id %d")
+}
+
+func (handler *tweetHandler) Unretweet(ctx *gin.Context) *entities.AppResult {
+	return handler.react(ctx, handler.tweetUsecase.Unretweet, "Success to undo retweet of tweet with id %d")
+}
+
+// react binds the username of a like or retweet request and passes it to the given usecase method
+func (handler *tweetHandler) react(ctx *gin.Context, react func(id int, username string) error, message string) *entities.AppResult {
+	var reaction entities.TweetReaction
+	var result entities.AppResult
+
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		result.Err = errors.New("id must be a number")
+		result.StatusCode = http.StatusBadRequest
+		return &result
+	}
+
+	if err := ctx.ShouldBindJSON(&reaction); err != nil {
+		result.Err = errors.New("username can not be empty")
+		result.StatusCode = http.StatusBadRequest
+		return &result
+	}
+
+	err = react(id, reaction.Username)
+	if err == nil {
+		result.Message = fmt.Sprintf(message, id)
+		result.StatusCode = http.StatusOK
+	} else {
+		result.Err = err.(*entities.AppError).Err
+		result.Message = err.(*entities.AppError).Error()
+		result.StatusCode = err.(*entities.AppError).StatusCode
+	}
+
+	return &result
+}
+
+func (handler *tweetHandler) GetTweetsByHashtag(ctx *gin.Context) *entities.AppResult {
+	tag := ctx.Param("tag")
+	tweets, err := handler.tweetUsecase.GetTweetsByHashtag(tag)
+	return tweetListResult(tweets, err, fmt.Sprintf("tagged with #%s", strings.TrimPrefix(tag, "#")))
+}
+
+func (handler *tweetHandler) GetTweetsByMention(ctx *gin.Context) *entities.AppResult {
+	username := ctx.Param("username")
+	tweets, err := handler.tweetUsecase.GetTweetsByMention(username)
+	return tweetListResult(tweets, err, fmt.Sprintf("mentioning @%s", strings.TrimPrefix(username, "@")))
+}
+
+func tweetListResult(tweets *[]entities.Tweet, err error, description string) *entities.AppResult {
+	var result entities.AppResult
+
+	if err == nil {
+		result.StatusCode = http.StatusOK
+		if tweets == nil || len(*tweets) == 0 {
+			result.Message = fmt.Sprintf("No tweets found %s", description)
+			result.Data = []struct{}{}
+		} else {
+			result.Message = fmt.Sprintf("Success to get all tweets %s", description)
+			result.Data = tweets
+		}
+	} else {
+		result.StatusCode = err.(*entities.AppError).StatusCode
+		result.Err = err.(*entities.AppError).Err
+		result.Data = []struct{}{}
+	}
+
 	return &result
 }
\ No newline at end of file
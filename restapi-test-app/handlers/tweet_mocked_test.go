@@ -6,12 +6,14 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/suite"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"restapi-tested-app/entities"
 	"restapi-tested-app/mocks"
 	"restapi-tested-app/utils"
 	"testing"
+	"time"
 )
 
 type tweetHandlerSuite struct {
@@ -35,7 +37,12 @@ func (suite *tweetHandlerSuite) SetupSuite() {
 	router := gin.Default()
 	router.POST("/tweet", utils.ServeHTTP(handler.CreateTweet))
 	router.GET("/tweet", utils.ServeHTTP(handler.GetAllTweets))
+	router.GET("/tweet/search", utils.ServeHTTP(handler.SearchTweetByText))
 	router.GET("/tweet/:id", utils.ServeHTTP(handler.GetTweetByID))
+	router.GET("/tweet/:id/thread", utils.ServeHTTP(handler.GetTweetThread))
+	router.POST("/tweet/:id/like", utils.ServeHTTP(handler.LikeTweet))
+	router.GET("/tweets/hashtag/:tag", utils.ServeHTTP(handler.GetTweetsByHashtag))
+	router.GET("/tweets/stream", handler.StreamTweets)
 
 	// create and run the testing server
 	testingServer := httptest.NewServer(router)
@@ -131,6 +138,188 @@ func (suite *tweetHandlerSuite) TestGetTweetByID_Positive() {
 	suite.usecase.AssertExpectations(suite.T())
 }
 
+func (suite *tweetHandlerSuite) TestGetTweetThread_Positive() {
+	id := 1
+	thread := entities.TweetThread{
+		Tweet: entities.Tweet{
+			ID: id,
+			Username: "username",
+			Text: "text",
+			ReplyCount: 1,
+		},
+		Replies: []*entities.TweetThread{
+			{
+				Tweet: entities.Tweet{
+					ID: 2,
+					Username: "username",
+					Text: "reply",
+					ParentID: &id,
+				},
+				Replies: []*entities.TweetThread{},
+			},
+		},
+	}
+
+	suite.usecase.On("GetTweetThread", id).Return(&thread, nil)
+	response, err := http.Get(fmt.Sprintf("%s/tweet/%d/thread", suite.testingServer.URL, id))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	responseBody := struct {
+		Data entities.TweetThread `json:"data"`
+	}{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal(1, responseBody.Data.ReplyCount)
+	suite.Len(responseBody.Data.Replies, 1)
+	suite.Equal("reply", responseBody.Data.Replies[0].Text)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *tweetHandlerSuite) TestLikeTweet_Positive() {
+	id := 1
+	suite.usecase.On("LikeTweet", id, "username").Return(nil)
+
+	requestBody, err := json.Marshal(entities.TweetReaction{Username: "username"})
+	suite.NoError(err, "can not marshal struct to json")
+
+	response, err := http.Post(fmt.Sprintf("%s/tweet/%d/like", suite.testingServer.URL, id), "application/json", bytes.NewBuffer(requestBody))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	responseBody := entities.Response{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal(responseBody.Message, fmt.Sprintf("Success to like tweet with id %d", id))
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *tweetHandlerSuite) TestLikeTweet_MissingUsername_Negative() {
+	response, err := http.Post(fmt.Sprintf("%s/tweet/1/like", suite.testingServer.URL), "application/json", bytes.NewBufferString("{}"))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func (suite *tweetHandlerSuite) TestGetTweetsByHashtag_Positive() {
+	tweets := []entities.Tweet{
+		{
+			Username: "username",
+			Text: "learning #golang",
+		},
+	}
+
+	suite.usecase.On("GetTweetsByHashtag", "golang").Return(&tweets, nil)
+	response, err := http.Get(fmt.Sprintf("%s/tweets/hashtag/golang", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	responseBody := entities.Response{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal(responseBody.Message, "Success to get all tweets tagged with #golang")
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *tweetHandlerSuite) TestStreamTweets_ResumeFromLastEventID_Positive() {
+	replay := []entities.TweetEvent{
+		{
+			ID: 3,
+			Type: entities.TweetCreated,
+			Tweet: entities.Tweet{ID: 1, Username: "username", Text: "text"},
+		},
+	}
+	events := make(chan entities.TweetEvent, 1)
+	events <- entities.TweetEvent{
+		ID: 4,
+		Type: entities.TweetDeleted,
+		Tweet: entities.Tweet{ID: 1},
+	}
+	// a closed channel ends the stream, like a subscriber dropped by the broker
+	close(events)
+	cancelled := false
+
+	suite.usecase.On("SubscribeTweets", int64(2)).Return(replay, (<-chan entities.TweetEvent)(events), func() { cancelled = true })
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/tweets/stream", suite.testingServer.URL), nil)
+	suite.NoError(err)
+	request.Header.Set("Last-Event-ID", "2")
+
+	response, err := http.DefaultClient.Do(request)
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	suite.NoError(err)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("text/event-stream", response.Header.Get("Content-Type"))
+	suite.Contains(string(body), "id: 3\nevent: created\ndata: {\"id\":3,\"type\":\"created\"")
+	suite.Contains(string(body), "id: 4\nevent: deleted\n")
+	suite.True(cancelled, "the subscription is cancelled when the stream ends")
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *tweetHandlerSuite) TestStreamTweets_InvalidLastEventID_Negative() {
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/tweets/stream", suite.testingServer.URL), nil)
+	suite.NoError(err)
+	request.Header.Set("Last-Event-ID", "abc")
+
+	response, err := http.DefaultClient.Do(request)
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func (suite *tweetHandlerSuite) TestSearchTweetByText_Positive() {
+	from := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	result := entities.SearchResult{
+		Tweets: []entities.SearchHit{
+			{
+				Tweet: entities.Tweet{ID: 1, Username: "username", Text: "learning golang"},
+				Highlight: "learning <mark>golang</mark>",
+			},
+		},
+		NextCursor: "cursor",
+	}
+
+	suite.usecase.On("SearchTweetByText", entities.SearchOptions{
+		Text: "golang",
+		Username: "username",
+		From: &from,
+		Limit: 5,
+	}).Return(&result, nil)
+
+	response, err := http.Get(fmt.Sprintf("%s/tweet/search?search=golang&username=username&from=2021-06-01T00:00:00Z&limit=5", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	responseBody := struct {
+		Message string `json:"message"`
+		Data entities.SearchResult `json:"data"`
+	}{}
+	json.NewDecoder(response.Body).Decode(&responseBody)
+
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("Success to get all tweets with a searching keyword golang", responseBody.Message)
+	suite.Equal("cursor", responseBody.Data.NextCursor)
+	suite.Equal("learning <mark>golang</mark>", responseBody.Data.Tweets[0].Highlight)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *tweetHandlerSuite) TestSearchTweetByText_InvalidDate_Negative() {
+	response, err := http.Get(fmt.Sprintf("%s/tweet/search?search=golang&from=yesterday", suite.testingServer.URL))
+	suite.NoError(err, "no error when calling this endpoint")
+	defer response.Body.Close()
+
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
 func TestTweetHandler(t *testing.T) {
 	suite.Run(t, new(tweetHandlerSuite))
 }
\ No newline at end of file
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"restapi-tested-app/entities"
+	"strconv"
+	"time"
+)
+
+// heartbeatInterval keeps proxies from closing an idle stream
+const heartbeatInterval = 15 * time.Second
+
+// StreamTweets pushes created, updated and deleted tweets as Server-Sent Events.
+// A reconnecting client sends the Last-Event-ID header (or the lastEventId query parameter)
+// and first receives the events it missed, as far as they are still buffered.
+// It does not go through ServeHTTP since the response is written incrementally.
+func (handler *tweetHandler) StreamTweets(ctx *gin.Context) {
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("lastEventId")
+	}
+
+	var lastID int64
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, entities.Response{
+				Success: false,
+				Message: "Last-Event-ID must be a number",
+				Data: struct{}{},
+			})
+			return
+		}
+		lastID = id
+	}
+
+	replay, events, cancel := handler.tweetUsecase.SubscribeTweets(lastID)
+	defer cancel()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	for _, event := range replay {
+		if err := writeTweetEvent(ctx.Writer, event); err != nil {
+			return
+		}
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				// the client fell behind, it reconnects and resumes from its last event
+				return
+			}
+			if err := writeTweetEvent(ctx.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(ctx.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case <-ctx.Request.Context().Done():
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+func writeTweetEvent(writer io.Writer, event entities.TweetEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,51 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	entities "restapi-tested-app/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AttachmentRepository is an autogenerated mock type for the AttachmentRepository type
+type AttachmentRepository struct {
+	mock.Mock
+}
+
+// CreateAttachment provides a mock function with given fields: attachment, maxPerTweet
+func (_m *AttachmentRepository) CreateAttachment(attachment *entities.Attachment, maxPerTweet int) error {
+	ret := _m.Called(attachment, maxPerTweet)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entities.Attachment, int) error); ok {
+		r0 = rf(attachment, maxPerTweet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAttachmentByKey provides a mock function with given fields: key
+func (_m *AttachmentRepository) GetAttachmentByKey(key string) (*entities.Attachment, error) {
+	ret := _m.Called(key)
+
+	var r0 *entities.Attachment
+	if rf, ok := ret.Get(0).(func(string) *entities.Attachment); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
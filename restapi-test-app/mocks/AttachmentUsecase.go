@@ -0,0 +1,72 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	blobstore "restapi-tested-app/blobstore"
+	entities "restapi-tested-app/entities"
+
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AttachmentUsecase is an autogenerated mock type for the AttachmentUsecase type
+type AttachmentUsecase struct {
+	mock.Mock
+}
+
+// AddAttachment provides a mock function with given fields: tweetID, file
+func (_m *AttachmentUsecase) AddAttachment(tweetID int, file io.Reader) (*entities.Attachment, error) {
+	ret := _m.Called(tweetID, file)
+
+	var r0 *entities.Attachment
+	if rf, ok := ret.Get(0).(func(int, io.Reader) *entities.Attachment); ok {
+		r0 = rf(tweetID, file)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.Attachment)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, io.Reader) error); ok {
+		r1 = rf(tweetID, file)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OpenAttachment provides a mock function with given fields: key
+func (_m *AttachmentUsecase) OpenAttachment(key string) (blobstore.Blob, *blobstore.BlobInfo, error) {
+	ret := _m.Called(key)
+
+	var r0 blobstore.Blob
+	if rf, ok := ret.Get(0).(func(string) blobstore.Blob); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(blobstore.Blob)
+		}
+	}
+
+	var r1 *blobstore.BlobInfo
+	if rf, ok := ret.Get(1).(func(string) *blobstore.BlobInfo); ok {
+		r1 = rf(key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*blobstore.BlobInfo)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(key)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
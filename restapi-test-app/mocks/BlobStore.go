@@ -0,0 +1,75 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	io "io"
+	blobstore "restapi-tested-app/blobstore"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BlobStore is an autogenerated mock type for the BlobStore type
+type BlobStore struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: key
+func (_m *BlobStore) Delete(key string) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Open provides a mock function with given fields: key
+func (_m *BlobStore) Open(key string) (blobstore.Blob, *blobstore.BlobInfo, error) {
+	ret := _m.Called(key)
+
+	var r0 blobstore.Blob
+	if rf, ok := ret.Get(0).(func(string) blobstore.Blob); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(blobstore.Blob)
+		}
+	}
+
+	var r1 *blobstore.BlobInfo
+	if rf, ok := ret.Get(1).(func(string) *blobstore.BlobInfo); ok {
+		r1 = rf(key)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*blobstore.BlobInfo)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string) error); ok {
+		r2 = rf(key)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Put provides a mock function with given fields: key, data
+func (_m *BlobStore) Put(key string, data io.Reader) error {
+	ret := _m.Called(key, data)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, io.Reader) error); ok {
+		r0 = rf(key, data)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
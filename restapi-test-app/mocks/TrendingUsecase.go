@@ -0,0 +1,56 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	entities "restapi-tested-app/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TrendingUsecase is an autogenerated mock type for the TrendingUsecase type
+type TrendingUsecase struct {
+	mock.Mock
+}
+
+// GetTrendingHashtags provides a mock function with given fields: limit
+func (_m *TrendingUsecase) GetTrendingHashtags(limit int) ([]entities.TrendingHashtag, error) {
+	ret := _m.Called(limit)
+
+	var r0 []entities.TrendingHashtag
+	if rf, ok := ret.Get(0).(func(int) []entities.TrendingHashtag); ok {
+		r0 = rf(limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.TrendingHashtag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields:
+func (_m *TrendingUsecase) Refresh() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RunRefresher provides a mock function with given fields: stop
+func (_m *TrendingUsecase) RunRefresher(stop <-chan struct{}) {
+	_m.Called(stop)
+}
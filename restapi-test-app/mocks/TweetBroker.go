@@ -0,0 +1,62 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	entities "restapi-tested-app/entities"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TweetBroker is an autogenerated mock type for the TweetBroker type
+type TweetBroker struct {
+	mock.Mock
+}
+
+// Publish provides a mock function with given fields: event
+func (_m *TweetBroker) Publish(event entities.TweetEvent) error {
+	ret := _m.Called(event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(entities.TweetEvent) error); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Subscribe provides a mock function with given fields: lastEventID
+func (_m *TweetBroker) Subscribe(lastEventID int64) ([]entities.TweetEvent, <-chan entities.TweetEvent, func()) {
+	ret := _m.Called(lastEventID)
+
+	var r0 []entities.TweetEvent
+	if rf, ok := ret.Get(0).(func(int64) []entities.TweetEvent); ok {
+		r0 = rf(lastEventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.TweetEvent)
+		}
+	}
+
+	var r1 <-chan entities.TweetEvent
+	if rf, ok := ret.Get(1).(func(int64) <-chan entities.TweetEvent); ok {
+		r1 = rf(lastEventID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan entities.TweetEvent)
+		}
+	}
+
+	var r2 func()
+	if rf, ok := ret.Get(2).(func(int64) func()); ok {
+		r2 = rf(lastEventID)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(func())
+		}
+	}
+
+	return r0, r1, r2
+}
@@ -6,6 +6,8 @@ import (
 	entities "restapi-tested-app/entities"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TweetRepository is an autogenerated mock type for the TweetRepository type
@@ -28,17 +30,26 @@ func (_m *TweetRepository) CreateTweet(tweet *entities.Tweet) error {
 }
 
 // DeleteTweet provides a mock function with given fields: id
-func (_m *TweetRepository) DeleteTweet(id int) error {
+func (_m *TweetRepository) DeleteTweet(id int) ([]entities.Attachment, error) {
 	ret := _m.Called(id)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(int) error); ok {
+	var r0 []entities.Attachment
+	if rf, ok := ret.Get(0).(func(int) []entities.Attachment); ok {
 		r0 = rf(id)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.Attachment)
+		}
 	}
 
-	return r0
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // GetAllTweets provides a mock function with given fields:
@@ -64,6 +75,29 @@ func (_m *TweetRepository) GetAllTweets() (*[]entities.Tweet, error) {
 	return r0, r1
 }
 
+// GetHashtagCounts provides a mock function with given fields: since
+func (_m *TweetRepository) GetHashtagCounts(since time.Time) (*[]entities.HashtagCount, error) {
+	ret := _m.Called(since)
+
+	var r0 *[]entities.HashtagCount
+	if rf, ok := ret.Get(0).(func(time.Time) *[]entities.HashtagCount); ok {
+		r0 = rf(since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]entities.HashtagCount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTweetByID provides a mock function with given fields: id
 func (_m *TweetRepository) GetTweetByID(id int) (*entities.Tweet, error) {
 	ret := _m.Called(id)
@@ -87,13 +121,59 @@ func (_m *TweetRepository) GetTweetByID(id int) (*entities.Tweet, error) {
 	return r0, r1
 }
 
-// SearchTweetByText provides a mock function with given fields: text
-func (_m *TweetRepository) SearchTweetByText(text string) (*[]entities.Tweet, error) {
-	ret := _m.Called(text)
+// GetTweetThread provides a mock function with given fields: id
+func (_m *TweetRepository) GetTweetThread(id int) (*[]entities.Tweet, error) {
+	ret := _m.Called(id)
+
+	var r0 *[]entities.Tweet
+	if rf, ok := ret.Get(0).(func(int) *[]entities.Tweet); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]entities.Tweet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTweetsByHashtag provides a mock function with given fields: tag
+func (_m *TweetRepository) GetTweetsByHashtag(tag string) (*[]entities.Tweet, error) {
+	ret := _m.Called(tag)
+
+	var r0 *[]entities.Tweet
+	if rf, ok := ret.Get(0).(func(string) *[]entities.Tweet); ok {
+		r0 = rf(tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]entities.Tweet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTweetsByMention provides a mock function with given fields: username
+func (_m *TweetRepository) GetTweetsByMention(username string) (*[]entities.Tweet, error) {
+	ret := _m.Called(username)
 
 	var r0 *[]entities.Tweet
 	if rf, ok := ret.Get(0).(func(string) *[]entities.Tweet); ok {
-		r0 = rf(text)
+		r0 = rf(username)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]entities.Tweet)
@@ -102,7 +182,79 @@ func (_m *TweetRepository) SearchTweetByText(text string) (*[]entities.Tweet, er
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(text)
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LikeTweet provides a mock function with given fields: id, username
+func (_m *TweetRepository) LikeTweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReindexTweets provides a mock function with given fields: afterID, limit
+func (_m *TweetRepository) ReindexTweets(afterID int, limit int) (int, error) {
+	ret := _m.Called(afterID, limit)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(int, int) int); ok {
+		r0 = rf(afterID, limit)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Retweet provides a mock function with given fields: id, username
+func (_m *TweetRepository) Retweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchTweetByText provides a mock function with given fields: query
+func (_m *TweetRepository) SearchTweetByText(query entities.SearchQuery) (*[]entities.SearchHit, error) {
+	ret := _m.Called(query)
+
+	var r0 *[]entities.SearchHit
+	if rf, ok := ret.Get(0).(func(entities.SearchQuery) *[]entities.SearchHit); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]entities.SearchHit)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entities.SearchQuery) error); ok {
+		r1 = rf(query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,6 +262,34 @@ func (_m *TweetRepository) SearchTweetByText(text string) (*[]entities.Tweet, er
 	return r0, r1
 }
 
+// UnlikeTweet provides a mock function with given fields: id, username
+func (_m *TweetRepository) UnlikeTweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unretweet provides a mock function with given fields: id, username
+func (_m *TweetRepository) Unretweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateTweet provides a mock function with given fields: tweet
 func (_m *TweetRepository) UpdateTweet(tweet *entities.Tweet) error {
 	ret := _m.Called(tweet)
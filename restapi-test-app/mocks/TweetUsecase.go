@@ -87,13 +87,59 @@ func (_m *TweetUsecase) GetTweetByID(id int) (*entities.Tweet, error) {
 	return r0, r1
 }
 
-// SearchTweetByText provides a mock function with given fields: text
-func (_m *TweetUsecase) SearchTweetByText(text string) (*[]entities.Tweet, error) {
-	ret := _m.Called(text)
+// GetTweetThread provides a mock function with given fields: id
+func (_m *TweetUsecase) GetTweetThread(id int) (*entities.TweetThread, error) {
+	ret := _m.Called(id)
+
+	var r0 *entities.TweetThread
+	if rf, ok := ret.Get(0).(func(int) *entities.TweetThread); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.TweetThread)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTweetsByHashtag provides a mock function with given fields: tag
+func (_m *TweetUsecase) GetTweetsByHashtag(tag string) (*[]entities.Tweet, error) {
+	ret := _m.Called(tag)
+
+	var r0 *[]entities.Tweet
+	if rf, ok := ret.Get(0).(func(string) *[]entities.Tweet); ok {
+		r0 = rf(tag)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*[]entities.Tweet)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTweetsByMention provides a mock function with given fields: username
+func (_m *TweetUsecase) GetTweetsByMention(username string) (*[]entities.Tweet, error) {
+	ret := _m.Called(username)
 
 	var r0 *[]entities.Tweet
 	if rf, ok := ret.Get(0).(func(string) *[]entities.Tweet); ok {
-		r0 = rf(text)
+		r0 = rf(username)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*[]entities.Tweet)
@@ -102,7 +148,7 @@ func (_m *TweetUsecase) SearchTweetByText(text string) (*[]entities.Tweet, error
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(text)
+		r1 = rf(username)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -110,6 +156,133 @@ func (_m *TweetUsecase) SearchTweetByText(text string) (*[]entities.Tweet, error
 	return r0, r1
 }
 
+// LikeTweet provides a mock function with given fields: id, username
+func (_m *TweetUsecase) LikeTweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplyTweet provides a mock function with given fields: parentID, tweet
+func (_m *TweetUsecase) ReplyTweet(parentID int, tweet *entities.Tweet) error {
+	ret := _m.Called(parentID, tweet)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, *entities.Tweet) error); ok {
+		r0 = rf(parentID, tweet)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Retweet provides a mock function with given fields: id, username
+func (_m *TweetUsecase) Retweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchTweetByText provides a mock function with given fields: options
+func (_m *TweetUsecase) SearchTweetByText(options entities.SearchOptions) (*entities.SearchResult, error) {
+	ret := _m.Called(options)
+
+	var r0 *entities.SearchResult
+	if rf, ok := ret.Get(0).(func(entities.SearchOptions) *entities.SearchResult); ok {
+		r0 = rf(options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entities.SearchResult)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(entities.SearchOptions) error); ok {
+		r1 = rf(options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SubscribeTweets provides a mock function with given fields: lastEventID
+func (_m *TweetUsecase) SubscribeTweets(lastEventID int64) ([]entities.TweetEvent, <-chan entities.TweetEvent, func()) {
+	ret := _m.Called(lastEventID)
+
+	var r0 []entities.TweetEvent
+	if rf, ok := ret.Get(0).(func(int64) []entities.TweetEvent); ok {
+		r0 = rf(lastEventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entities.TweetEvent)
+		}
+	}
+
+	var r1 <-chan entities.TweetEvent
+	if rf, ok := ret.Get(1).(func(int64) <-chan entities.TweetEvent); ok {
+		r1 = rf(lastEventID)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(<-chan entities.TweetEvent)
+		}
+	}
+
+	var r2 func()
+	if rf, ok := ret.Get(2).(func(int64) func()); ok {
+		r2 = rf(lastEventID)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(func())
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// UnlikeTweet provides a mock function with given fields: id, username
+func (_m *TweetUsecase) UnlikeTweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unretweet provides a mock function with given fields: id, username
+func (_m *TweetUsecase) Unretweet(id int, username string) error {
+	ret := _m.Called(id, username)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int, string) error); ok {
+		r0 = rf(id, username)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateTweet provides a mock function with given fields: tweet
 func (_m *TweetUsecase) UpdateTweet(tweet *entities.Tweet) error {
 	ret := _m.Called(tweet)
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"restapi-tested-app/entities"
+)
+
+// ErrTooManyAttachments is returned when a tweet already has the maximum number of attachments
+var ErrTooManyAttachments = errors.New("tweet has too many attachments")
+
+const attachmentColumns = `id, tweet_id, content_type, size, width, height, blob_key, thumbnail_key, created_at`
+
+type attachmentRepository struct {
+	db *sqlx.DB
+}
+
+type AttachmentRepository interface {
+	CreateAttachment(attachment *entities.Attachment, maxPerTweet int) error
+	GetAttachmentByKey(key string) (*entities.Attachment, error)
+}
+
+func InitializeAttachmentRepository(db *sqlx.DB) AttachmentRepository {
+	return &attachmentRepository{db}
+}
+
+// CreateAttachment stores the attachment unless the tweet is missing or already has maxPerTweet attachments
+func (repository *attachmentRepository) CreateAttachment(attachment *entities.Attachment, maxPerTweet int) error {
+	if attachment == nil {
+		return errors.New("attachment can not be nil")
+	}
+
+	tx, err := repository.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	err = insertAttachment(tx, attachment, maxPerTweet)
+	if err == nil {
+		err = tx.Commit()
+	} else {
+		tx.Rollback()
+	}
+
+	return err
+}
+
+func insertAttachment(tx *sqlx.Tx, attachment *entities.Attachment, maxPerTweet int) error {
+	// lock the tweet so concurrent uploads can not exceed the limit
+	var id int
+	err := tx.Get(&id, `SELECT id FROM tweets WHERE id=$1 FOR UPDATE;`, attachment.TweetID)
+	if err == sql.ErrNoRows {
+		return ErrTweetNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = tx.Get(&count, `SELECT COUNT(*) FROM attachments WHERE tweet_id=$1;`, attachment.TweetID)
+	if err != nil {
+		return err
+	}
+	if count >= maxPerTweet {
+		return ErrTooManyAttachments
+	}
+
+	return tx.QueryRowx(`
+		INSERT INTO attachments(tweet_id, content_type, size, width, height, blob_key, thumbnail_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at;
+	`, attachment.TweetID, attachment.ContentType, attachment.Size, attachment.Width, attachment.Height,
+		attachment.BlobKey, attachment.ThumbnailKey).Scan(&attachment.ID, &attachment.CreatedAt)
+}
+
+// GetAttachmentByKey finds the attachment owning a blob, either as the original or as the thumbnail
+func (repository *attachmentRepository) GetAttachmentByKey(key string) (*entities.Attachment, error) {
+	var attachment entities.Attachment
+
+	err := repository.db.Get(&attachment, `SELECT `+attachmentColumns+` FROM attachments WHERE blob_key=$1 OR thumbnail_key=$1;`, key)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.SetURLs()
+	return &attachment, nil
+}
+
+// loadAttachments fills the attachments of the given tweets with a single query
+func loadAttachments(db *sqlx.DB, tweets []*entities.Tweet) error {
+	if len(tweets) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(tweets))
+	byID := make(map[int][]*entities.Tweet, len(tweets))
+	for _, tweet := range tweets {
+		tweet.Attachments = []entities.Attachment{}
+		ids = append(ids, int64(tweet.ID))
+		byID[tweet.ID] = append(byID[tweet.ID], tweet)
+	}
+
+	var attachments []entities.Attachment
+	err := db.Select(&attachments, `SELECT `+attachmentColumns+` FROM attachments WHERE tweet_id = ANY($1) ORDER BY id;`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		attachment.SetURLs()
+		for _, tweet := range byID[attachment.TweetID] {
+			tweet.Attachments = append(tweet.Attachments, attachment)
+		}
+	}
+
+	return nil
+}
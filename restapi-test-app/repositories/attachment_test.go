@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/suite"
+	"restapi-tested-app/config"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/utils"
+	"testing"
+)
+
+type attachmentRepositorySuite struct {
+	suite.Suite
+	repository AttachmentRepository
+	tweetRepository TweetRepository
+	cleanupExecutor utils.TruncateTableExecutor
+}
+
+func (suite *attachmentRepositorySuite) SetupSuite() {
+	configs := config.GetConfig()
+	db := config.ConnectDB(configs)
+
+	suite.repository = InitializeAttachmentRepository(db)
+	suite.tweetRepository = InitializeTweetRepository(db)
+	suite.cleanupExecutor = utils.InitTruncateTableExecutor(db)
+}
+
+func (suite *attachmentRepositorySuite) TearDownTest() {
+	defer suite.cleanupExecutor.TruncateTable([]string{"tweets", "attachments"})
+}
+
+func (suite *attachmentRepositorySuite) attachment(tweetID int, key string) *entities.Attachment {
+	return &entities.Attachment{
+		TweetID: tweetID,
+		ContentType: "image/png",
+		Size: 10,
+		Width: 1,
+		Height: 1,
+		BlobKey: key + ".png",
+		ThumbnailKey: key + "_thumb.png",
+	}
+}
+
+func (suite *attachmentRepositorySuite) TestCreateAttachment_Limit_Positive() {
+	tweet := entities.Tweet{
+		Username: "username",
+		Text: "text",
+	}
+	err := suite.tweetRepository.CreateTweet(&tweet)
+	suite.NoError(err, "no error when create tweet with valid input")
+
+	for i := 0; i < 2; i++ {
+		err = suite.repository.CreateAttachment(suite.attachment(tweet.ID, fmt.Sprintf("key%d", i)), 2)
+		suite.NoError(err, "no error when the tweet has room for attachments")
+	}
+
+	err = suite.repository.CreateAttachment(suite.attachment(tweet.ID, "key2"), 2)
+	suite.Equal(ErrTooManyAttachments, err)
+
+	result, err := suite.tweetRepository.GetTweetByID(tweet.ID)
+	suite.NoError(err)
+	suite.Equal(2, len(result.Attachments), "the tweet is returned with its attachments")
+	suite.Equal("/attachments/key0.png", result.Attachments[0].URL)
+
+	attachment, err := suite.repository.GetAttachmentByKey("key1_thumb.png")
+	suite.NoError(err, "attachment is found by its thumbnail")
+	suite.Equal("/attachments/key1.png", attachment.URL)
+}
+
+func (suite *attachmentRepositorySuite) TestCreateAttachment_TweetNotFound_Negative() {
+	err := suite.repository.CreateAttachment(suite.attachment(1, "key"), 4)
+	suite.Equal(ErrTweetNotFound, err)
+}
+
+func (suite *attachmentRepositorySuite) TestDeleteTweet_ReturnsAttachmentsOfThread_Positive() {
+	tweet := entities.Tweet{Username: "username", Text: "text"}
+	err := suite.tweetRepository.CreateTweet(&tweet)
+	suite.NoError(err)
+	reply := entities.Tweet{Username: "username", Text: "reply", ParentID: &tweet.ID}
+	err = suite.tweetRepository.CreateTweet(&reply)
+	suite.NoError(err)
+
+	suite.NoError(suite.repository.CreateAttachment(suite.attachment(tweet.ID, "tweet"), 4))
+	suite.NoError(suite.repository.CreateAttachment(suite.attachment(reply.ID, "reply"), 4))
+
+	attachments, err := suite.tweetRepository.DeleteTweet(tweet.ID)
+	suite.NoError(err)
+	suite.Equal(2, len(attachments), "attachments of the tweet and of its reply are returned")
+	suite.Equal("tweet.png", attachments[0].BlobKey)
+	suite.Equal("reply_thumb.png", attachments[1].ThumbnailKey)
+
+	_, err = suite.repository.GetAttachmentByKey("reply.png")
+	suite.Error(err, "attachment rows are removed with the tweet")
+}
+
+func TestAttachmentRepository(t *testing.T) {
+	suite.Run(t, new(attachmentRepositorySuite))
+}
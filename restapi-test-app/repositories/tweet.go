@@ -1,11 +1,23 @@
 package repositories
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"restapi-tested-app/entities"
+	"restapi-tested-app/utils"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// ErrTweetNotFound is returned when the tweet being replied to, liked or retweeted does not exist
+var ErrTweetNotFound = errors.New("tweet is not found")
+
+const tweetColumns = `id, username, text, parent_id, like_count, reply_count, retweet_count, created_at, modified_at`
+
 type tweetRepository struct {
 	db *sqlx.DB
 }
@@ -13,10 +25,19 @@ type tweetRepository struct {
 type TweetRepository interface {
 	GetAllTweets() (*[]entities.Tweet, error)
 	GetTweetByID(id int) (*entities.Tweet, error)
-	SearchTweetByText(text string) (*[]entities.Tweet, error)
+	SearchTweetByText(query entities.SearchQuery) (*[]entities.SearchHit, error)
 	CreateTweet(tweet *entities.Tweet) error
 	UpdateTweet(tweet *entities.Tweet) error
-	DeleteTweet(id int) error
+	DeleteTweet(id int) ([]entities.Attachment, error)
+	GetTweetThread(id int) (*[]entities.Tweet, error)
+	LikeTweet(id int, username string) error
+	UnlikeTweet(id int, username string) error
+	Retweet(id int, username string) error
+	Unretweet(id int, username string) error
+	GetTweetsByHashtag(tag string) (*[]entities.Tweet, error)
+	GetTweetsByMention(username string) (*[]entities.Tweet, error)
+	GetHashtagCounts(since time.Time) (*[]entities.HashtagCount, error)
+	ReindexTweets(afterID int, limit int) (int, error)
 }
 
 func InitializeTweetRepository(db *sqlx.DB) TweetRepository {
@@ -25,7 +46,7 @@ func InitializeTweetRepository(db *sqlx.DB) TweetRepository {
 
 func (repository *tweetRepository) GetAllTweets() (*[]entities.Tweet, error) {
 	var result []entities.Tweet
-	rows, err := repository.db.Queryx(`SELECT id, username, text, created_at, modified_at FROM tweets`)
+	rows, err := repository.db.Queryx(`SELECT `+tweetColumns+` FROM tweets`)
 	if err != nil {
 		return nil, err
 	}
@@ -39,13 +60,23 @@ func (repository *tweetRepository) GetAllTweets() (*[]entities.Tweet, error) {
 		result = append(result, tweet)
 	}
 
-	return &result, err
+	err = loadAttachments(repository.db, tweetPointers(result))
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
 }
 
 func (repository *tweetRepository) GetTweetByID(id int) (*entities.Tweet, error) {
 	var tweet entities.Tweet
 
-	err := repository.db.Get(&tweet, `SELECT id, username, text, created_at, modified_at FROM tweets WHERE id=$1;`, id)
+	err := repository.db.Get(&tweet, `SELECT `+tweetColumns+` FROM tweets WHERE id=$1;`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadAttachments(repository.db, []*entities.Tweet{&tweet})
 	if err != nil {
 		return nil, err
 	}
@@ -53,17 +84,71 @@ func (repository *tweetRepository) GetTweetByID(id int) (*entities.Tweet, error)
 	return &tweet, nil
 }
 
-func (repository *tweetRepository) SearchTweetByText(text string) (*[]entities.Tweet, error) {
-	var result []entities.Tweet
+// SearchTweetByText returns one page of tweets matching the query, best matches first.
+// Full-text matches are highlighted here, trigram matches are left for the caller to highlight.
+func (repository *tweetRepository) SearchTweetByText(query entities.SearchQuery) (*[]entities.SearchHit, error) {
+	var rank, match, highlight string
+	args := []interface{}{query.Text}
 
-	rows, err := repository.db.Queryx(`SELECT id, username, text, created_at, modified_at FROM tweets WHERE text ILIKE $1;`, text)
-	for rows.Next() {
-		var tweet entities.Tweet
-		err = rows.StructScan(&tweet)
-		result = append(result, tweet)
+	if query.Mode == entities.SearchTrigram {
+		rank = `word_similarity($1, text)::float8`
+		match = `($1 <% text OR strpos(lower(text), lower($1)) > 0)`
+		highlight = `''`
+	} else {
+		rank = `ts_rank(search_vector, websearch_to_tsquery('english', $1))::float8`
+		match = `search_vector @@ websearch_to_tsquery('english', $1)`
+		// the text is escaped first so the snippet can be rendered as HTML
+		highlight = `ts_headline('english',
+			replace(replace(replace(text, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+			websearch_to_tsquery('english', $1),
+			'StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5')`
 	}
 
-	return &result, err
+	conditions := []string{match}
+	if query.Username != "" {
+		args = append(args, query.Username)
+		conditions = append(conditions, fmt.Sprintf("username = $%d", len(args)))
+	}
+	if query.From != nil {
+		args = append(args, *query.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if query.To != nil {
+		args = append(args, *query.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+	if query.AfterRank != nil {
+		args = append(args, *query.AfterRank, query.AfterID)
+		conditions = append(conditions, fmt.Sprintf("(%s < $%d OR (%s = $%d AND id < $%d))", rank, len(args)-1, rank, len(args)-1, len(args)))
+	}
+	args = append(args, query.Limit)
+
+	var result []entities.SearchHit
+	err := repository.db.Select(&result, `
+		SELECT `+tweetColumns+`, rank, `+highlight+` AS highlight
+		FROM (
+			SELECT `+tweetColumns+`, `+rank+` AS rank
+			FROM tweets
+			WHERE `+strings.Join(conditions, " AND ")+`
+			ORDER BY rank DESC, id DESC
+			LIMIT $`+strconv.Itoa(len(args))+`
+		) page
+		ORDER BY rank DESC, id DESC;
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	tweets := make([]*entities.Tweet, 0, len(result))
+	for i := range result {
+		tweets = append(tweets, &result[i].Tweet)
+	}
+	err = loadAttachments(repository.db, tweets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
 }
 
 func (repository *tweetRepository) CreateTweet(tweet *entities.Tweet) error {
@@ -78,9 +163,6 @@ func (repository *tweetRepository) CreateTweet(tweet *entities.Tweet) error {
 		return err
 	} else {
 		err = insertTweet(tx, tweet)
-		if err != nil {
-			return err
-		}
 	}
 
 	if err == nil {
@@ -93,59 +175,244 @@ func (repository *tweetRepository) CreateTweet(tweet *entities.Tweet) error {
 }
 
 func insertTweet(tx *sqlx.Tx, tweet *entities.Tweet) error {
-	_, err := tx.NamedExec(`
-		INSERT INTO tweets(username, text)
-		VALUES (:username, :text);
-	`, tweet)
+	if tweet.ParentID != nil {
+		err := incrementCounter(tx, "reply_count", *tweet.ParentID, 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := tx.QueryRowx(`
+		INSERT INTO tweets(username, text, parent_id)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, modified_at;
+	`, tweet.Username, tweet.Text, tweet.ParentID).Scan(&tweet.ID, &tweet.CreatedAt, &tweet.ModifiedAt)
+	if err != nil {
+		return err
+	}
+
+	return indexTweet(tx, tweet.ID, tweet.Text)
+}
+
+// indexTweet replaces the hashtags and mentions stored for a tweet with the ones found in its text.
+// Hashtags keep the creation time of the tweet, so editing a tweet does not make its tags trend again.
+func indexTweet(tx *sqlx.Tx, id int, text string) error {
+	_, err := tx.Exec(`DELETE FROM tweet_hashtags WHERE tweet_id=$1;`, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM tweet_mentions WHERE tweet_id=$1;`, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO tweet_hashtags(tweet_id, tag, created_at)
+		SELECT id, unnest($2::varchar[]), created_at FROM tweets WHERE id=$1;
+	`, id, pq.Array(utils.ExtractHashtags(text)))
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO tweet_mentions(tweet_id, username)
+		SELECT id, unnest($2::varchar[]) FROM tweets WHERE id=$1;
+	`, id, pq.Array(utils.ExtractMentions(text)))
 
 	return err
 }
 
-func (repository *tweetRepository) UpdateTweet(tweet *entities.Tweet) error {
-	var err error
+// ReindexTweets rebuilds the hashtags and mentions of at most limit tweets with an id greater than afterID,
+// in id order, and returns the last id it indexed or 0 once no tweets are left.
+// It backfills tweets written before hashtags and mentions were indexed.
+func (repository *tweetRepository) ReindexTweets(afterID int, limit int) (int, error) {
+	var tweets []entities.Tweet
 
-	tx, errTx := repository.db.Beginx()
-	if errTx != nil {
-	} else {
-		err = updateTweet(tx, tweet)
+	err := repository.db.Select(&tweets, `SELECT id, text FROM tweets WHERE id>$1 ORDER BY id LIMIT $2;`, afterID, limit)
+	if err != nil || len(tweets) == 0 {
+		return 0, err
+	}
+
+	tx, err := repository.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, tweet := range tweets {
+		err = indexTweet(tx, tweet.ID, tweet.Text)
 		if err != nil {
+			tx.Rollback()
+			return 0, err
 		}
 	}
 
-	if err == nil {
-		tx.Commit()
-	} else {
+	return tweets[len(tweets)-1].ID, tx.Commit()
+}
+
+// incrementCounter adds delta to one of the denormalized counters of a tweet.
+// The update also locks the tweet row, so concurrent reactions are serialized.
+func incrementCounter(tx *sqlx.Tx, column string, id int, delta int) error {
+	result, err := tx.Exec(`UPDATE tweets SET `+column+` = `+column+` + $1 WHERE id=$2;`, delta, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTweetNotFound
+	}
+
+	return nil
+}
+
+// UpdateTweet stores the new text of the tweet, ErrTweetNotFound is returned when there is no such tweet
+func (repository *tweetRepository) UpdateTweet(tweet *entities.Tweet) error {
+	tx, err := repository.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	err = updateTweet(tx, tweet)
+	if err != nil {
 		tx.Rollback()
+		return err
 	}
 
-	return err
+	return tx.Commit()
 }
 
 func updateTweet(tx *sqlx.Tx, tweet *entities.Tweet) error {
-	_, err := tx.NamedExec(`
+	result, err := tx.NamedExec(`
 		UPDATE tweets
 		SET username=:username,
 		    text=:text,
 		    modified_at=:modified_at
 		WHERE id=:id;
 	`, tweet)
+	if err != nil {
+		return err
+	}
 
-	return err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTweetNotFound
+	}
+
+	return indexTweet(tx, tweet.ID, tweet.Text)
 }
 
-func (repository *tweetRepository) DeleteTweet(id int) error {
-	var err error
+// DeleteTweet removes the tweet with the given id, ErrTweetNotFound is returned when there is no such tweet.
+// It returns the attachments removed with the tweet and its replies, their blobs are left to the caller.
+func (repository *tweetRepository) DeleteTweet(id int) ([]entities.Attachment, error) {
+	tx, err := repository.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
 
-	tx, errTx := repository.db.Beginx()
-	if errTx != nil {
-	} else {
+	var attachments []entities.Attachment
+	err = tx.Select(&attachments, `
+		WITH RECURSIVE thread AS (
+			SELECT id FROM tweets WHERE id=$1
+			UNION ALL
+			SELECT t.id FROM tweets t JOIN thread ON t.parent_id = thread.id
+		)
+		SELECT `+attachmentColumns+` FROM attachments WHERE tweet_id IN (SELECT id FROM thread) ORDER BY id;
+	`, id)
+	if err == nil {
 		err = deleteTweet(tx, id)
-		if err != nil {
+	}
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return attachments, tx.Commit()
+}
+
+func deleteTweet(tx *sqlx.Tx, id int) error {
+	var parentID *int
+
+	// replies, likes and retweets of the tweet are removed by the cascading foreign keys
+	err := tx.Get(&parentID, `
+		DELETE FROM tweets WHERE id=$1 RETURNING parent_id;
+	`, id)
+	if err == sql.ErrNoRows {
+		return ErrTweetNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if parentID != nil {
+		err = incrementCounter(tx, "reply_count", *parentID, -1)
+		if err == ErrTweetNotFound {
+			return nil
 		}
 	}
 
+	return err
+}
+
+// GetTweetThread returns the tweet with the given id followed by all of its direct and nested replies
+func (repository *tweetRepository) GetTweetThread(id int) (*[]entities.Tweet, error) {
+	var result []entities.Tweet
+
+	err := repository.db.Select(&result, `
+		WITH RECURSIVE thread AS (
+			SELECT `+tweetColumns+` FROM tweets WHERE id=$1
+			UNION ALL
+			SELECT t.id, t.username, t.text, t.parent_id, t.like_count, t.reply_count, t.retweet_count, t.created_at, t.modified_at
+			FROM tweets t
+			JOIN thread ON t.parent_id = thread.id
+		)
+		SELECT `+tweetColumns+` FROM thread ORDER BY created_at, id;
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadAttachments(repository.db, tweetPointers(result))
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (repository *tweetRepository) LikeTweet(id int, username string) error {
+	return repository.react("likes", "like_count", id, username, true)
+}
+
+func (repository *tweetRepository) UnlikeTweet(id int, username string) error {
+	return repository.react("likes", "like_count", id, username, false)
+}
+
+func (repository *tweetRepository) Retweet(id int, username string) error {
+	return repository.react("retweets", "retweet_count", id, username, true)
+}
+
+func (repository *tweetRepository) Unretweet(id int, username string) error {
+	return repository.react("retweets", "retweet_count", id, username, false)
+}
+
+// react adds or removes a like or a retweet and keeps the counter on the tweet in the same transaction.
+// Adding a reaction twice or removing a missing one leaves the counter untouched.
+func (repository *tweetRepository) react(table string, counter string, id int, username string, add bool) error {
+	tx, err := repository.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	err = react(tx, table, counter, id, username, add)
 	if err == nil {
-		tx.Commit()
+		err = tx.Commit()
 	} else {
 		tx.Rollback()
 	}
@@ -153,10 +420,95 @@ func (repository *tweetRepository) DeleteTweet(id int) error {
 	return err
 }
 
-func deleteTweet(tx *sqlx.Tx, id int) error {
-	_, err := tx.Exec(`
-		DELETE FROM tweets WHERE id=$1;
-	`, id)
+func react(tx *sqlx.Tx, table string, counter string, id int, username string, add bool) error {
+	// lock the tweet first, this also tells us whether it exists
+	err := incrementCounter(tx, counter, id, 0)
+	if err != nil {
+		return err
+	}
 
-	return err
+	var result sql.Result
+	delta := 1
+	if add {
+		result, err = tx.Exec(`INSERT INTO `+table+`(tweet_id, username) VALUES ($1, $2) ON CONFLICT DO NOTHING;`, id, username)
+	} else {
+		delta = -1
+		result, err = tx.Exec(`DELETE FROM `+table+` WHERE tweet_id=$1 AND username=$2;`, id, username)
+	}
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return err
+	}
+
+	return incrementCounter(tx, counter, id, delta)
+}
+
+func (repository *tweetRepository) GetTweetsByHashtag(tag string) (*[]entities.Tweet, error) {
+	var result []entities.Tweet
+
+	err := repository.db.Select(&result, `
+		SELECT `+tweetColumns+` FROM tweets
+		WHERE id IN (SELECT tweet_id FROM tweet_hashtags WHERE tag=$1)
+		ORDER BY created_at DESC, id DESC;
+	`, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadAttachments(repository.db, tweetPointers(result))
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (repository *tweetRepository) GetTweetsByMention(username string) (*[]entities.Tweet, error) {
+	var result []entities.Tweet
+
+	err := repository.db.Select(&result, `
+		SELECT `+tweetColumns+` FROM tweets
+		WHERE id IN (SELECT tweet_id FROM tweet_mentions WHERE username=$1)
+		ORDER BY created_at DESC, id DESC;
+	`, username)
+	if err != nil {
+		return nil, err
+	}
+
+	err = loadAttachments(repository.db, tweetPointers(result))
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// GetHashtagCounts returns how often each hashtag was used per hour since the given time
+func (repository *tweetRepository) GetHashtagCounts(since time.Time) (*[]entities.HashtagCount, error) {
+	var result []entities.HashtagCount
+
+	err := repository.db.Select(&result, `
+		SELECT tag, date_trunc('hour', created_at) AS bucket, COUNT(*) AS count
+		FROM tweet_hashtags
+		WHERE created_at >= $1
+		GROUP BY tag, bucket;
+	`, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func tweetPointers(tweets []entities.Tweet) []*entities.Tweet {
+	result := make([]*entities.Tweet, 0, len(tweets))
+	for i := range tweets {
+		result = append(result, &tweets[i])
+	}
+
+	return result
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	"restapi-tested-app/entities"
 	"restapi-tested-app/utils"
 	"testing"
+	"time"
 )
 
 type tweetRepositorySuite struct {
@@ -33,7 +34,7 @@ func (suite *tweetRepositorySuite) SetupSuite() {
 
 func (suite *tweetRepositorySuite) TearDownTest() {
 	// clean-up the used table to be used for another session
-	defer suite.cleanupExecutor.TruncateTable([]string{"tweets"})
+	defer suite.cleanupExecutor.TruncateTable([]string{"tweets", "likes", "retweets", "tweet_hashtags", "tweet_mentions"})
 }
 
 func (suite *tweetRepositorySuite) TestCreateTweet_Positive() {
@@ -111,6 +112,176 @@ func (suite *tweetRepositorySuite) TestGetTweetByID_Exists_Positive() {
 	suite.Equal(tweet.Text, (*result).Text, "should be equal between result and tweet")
 }
 
+func (suite *tweetRepositorySuite) TestReplyTweet_Counters_Positive() {
+	parentID := 1
+	tweet := entities.Tweet{
+		Username: "username",
+		Text: "text",
+	}
+	reply := entities.Tweet{
+		Username: "username",
+		Text: "reply",
+		ParentID: &parentID,
+	}
+
+	err := suite.repository.CreateTweet(&tweet)
+	suite.NoError(err, "no error when create tweet with valid input")
+	err = suite.repository.CreateTweet(&reply)
+	suite.NoError(err, "no error when reply to an existing tweet")
+
+	result, err := suite.repository.GetTweetByID(parentID)
+	suite.NoError(err, "no error because tweet is found")
+	suite.Equal(1, result.ReplyCount, "reply count is incremented")
+
+	thread, err := suite.repository.GetTweetThread(parentID)
+	suite.NoError(err, "no error because tweet is found")
+	suite.Equal(2, len(*thread), "thread contains the tweet and its reply")
+
+	_, err = suite.repository.DeleteTweet(2)
+	suite.NoError(err, "no error when delete the reply")
+
+	result, err = suite.repository.GetTweetByID(parentID)
+	suite.NoError(err, "no error because tweet is found")
+	suite.Equal(0, result.ReplyCount, "reply count is decremented")
+}
+
+func (suite *tweetRepositorySuite) TestUpdateTweet_NotFound_Negative() {
+	err := suite.repository.UpdateTweet(&entities.Tweet{ID: 1000, Username: "username", Text: "edited"})
+	suite.Equal(ErrTweetNotFound, err, "updating a missing tweet is not found")
+}
+
+func (suite *tweetRepositorySuite) TestDeleteTweet_NotFound_Negative() {
+	_, err := suite.repository.DeleteTweet(1000)
+	suite.Equal(ErrTweetNotFound, err, "deleting a missing tweet is not found")
+}
+
+func (suite *tweetRepositorySuite) TestReplyTweet_ParentNotFound_Negative() {
+	parentID := 1
+	reply := entities.Tweet{
+		Username: "username",
+		Text: "reply",
+		ParentID: &parentID,
+	}
+
+	err := suite.repository.CreateTweet(&reply)
+	suite.Equal(ErrTweetNotFound, err)
+}
+
+func (suite *tweetRepositorySuite) TestLikeAndRetweet_Counters_Positive() {
+	id := 1
+	tweet := entities.Tweet{
+		Username: "username",
+		Text: "text",
+	}
+
+	err := suite.repository.CreateTweet(&tweet)
+	suite.NoError(err, "no error when create tweet with valid input")
+
+	// liking twice with the same username only counts once
+	suite.NoError(suite.repository.LikeTweet(id, "alice"))
+	suite.NoError(suite.repository.LikeTweet(id, "alice"))
+	suite.NoError(suite.repository.LikeTweet(id, "bob"))
+	suite.NoError(suite.repository.Retweet(id, "alice"))
+
+	result, err := suite.repository.GetTweetByID(id)
+	suite.NoError(err, "no error because tweet is found")
+	suite.Equal(2, result.LikeCount)
+	suite.Equal(1, result.RetweetCount)
+
+	suite.NoError(suite.repository.UnlikeTweet(id, "alice"))
+	suite.NoError(suite.repository.UnlikeTweet(id, "alice"))
+	suite.NoError(suite.repository.Unretweet(id, "alice"))
+
+	result, err = suite.repository.GetTweetByID(id)
+	suite.NoError(err, "no error because tweet is found")
+	suite.Equal(1, result.LikeCount)
+	suite.Equal(0, result.RetweetCount)
+}
+
+func (suite *tweetRepositorySuite) TestLikeTweet_NotFound_Negative() {
+	err := suite.repository.LikeTweet(1, "alice")
+	suite.Equal(ErrTweetNotFound, err)
+}
+
+func (suite *tweetRepositorySuite) TestHashtagsAndMentions_Indexed_Positive() {
+	tweet := entities.Tweet{
+		Username: "username",
+		Text: "hello @Alice, learning #GoLang",
+	}
+
+	err := suite.repository.CreateTweet(&tweet)
+	suite.NoError(err, "no error when create tweet with valid input")
+	suite.Equal(1, tweet.ID, "id of the new tweet is set")
+
+	tweets, err := suite.repository.GetTweetsByHashtag("golang")
+	suite.NoError(err)
+	suite.Equal(1, len(*tweets), "tweet is found by its hashtag")
+
+	tweets, err = suite.repository.GetTweetsByMention("alice")
+	suite.NoError(err)
+	suite.Equal(1, len(*tweets), "tweet is found by its mention")
+
+	counts, err := suite.repository.GetHashtagCounts(time.Now().Add(-time.Hour))
+	suite.NoError(err)
+	suite.Equal(1, len(*counts))
+	suite.Equal("golang", (*counts)[0].Tag)
+
+	// editing the text replaces the indexed hashtags
+	tweet.Text = "now about #rust"
+	err = suite.repository.UpdateTweet(&tweet)
+	suite.NoError(err, "no error when update tweet")
+
+	tweets, err = suite.repository.GetTweetsByHashtag("golang")
+	suite.NoError(err)
+	suite.Equal(0, len(*tweets), "old hashtag is removed")
+
+	tweets, err = suite.repository.GetTweetsByHashtag("rust")
+	suite.NoError(err)
+	suite.Equal(1, len(*tweets), "new hashtag is indexed")
+}
+
+func (suite *tweetRepositorySuite) TestSearchTweetByText_RankedAndPaginated_Positive() {
+	for _, text := range []string{"golang", "golang and more golang <b>", "rust"} {
+		err := suite.repository.CreateTweet(&entities.Tweet{Username: "username", Text: text})
+		suite.NoError(err, "no error when create tweet with valid input")
+	}
+
+	hits, err := suite.repository.SearchTweetByText(entities.SearchQuery{
+		Mode: entities.SearchFullText,
+		Text: "golang",
+		Limit: 1,
+	})
+	suite.NoError(err)
+	suite.Equal(1, len(*hits), "page is limited")
+	first := (*hits)[0]
+	suite.Contains(first.Highlight, "<mark>golang</mark>")
+	suite.Contains(first.Highlight, "&lt;b&gt;", "the snippet is escaped")
+
+	hits, err = suite.repository.SearchTweetByText(entities.SearchQuery{
+		Mode: entities.SearchFullText,
+		Text: "golang",
+		AfterRank: &first.Rank,
+		AfterID: first.ID,
+		Limit: 10,
+	})
+	suite.NoError(err)
+	suite.Equal(1, len(*hits), "the second page has the remaining match")
+	suite.NotEqual(first.ID, (*hits)[0].ID)
+}
+
+func (suite *tweetRepositorySuite) TestSearchTweetByText_Trigram_Positive() {
+	err := suite.repository.CreateTweet(&entities.Tweet{Username: "username", Text: "learning golang"})
+	suite.NoError(err, "no error when create tweet with valid input")
+
+	hits, err := suite.repository.SearchTweetByText(entities.SearchQuery{
+		Mode: entities.SearchTrigram,
+		Text: "golan",
+		Limit: 10,
+	})
+	suite.NoError(err)
+	suite.Equal(1, len(*hits), "partial words match by similarity")
+}
+
 func TestTweetRepository(t *testing.T) {
 	suite.Run(t, new(tweetRepositorySuite))
 }
\ No newline at end of file
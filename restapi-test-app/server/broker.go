@@ -0,0 +1,20 @@
+package server
+
+import (
+	"github.com/jmoiron/sqlx"
+	"restapi-tested-app/broker"
+	"restapi-tested-app/config"
+	"restapi-tested-app/entities"
+)
+
+func SetupBroker(configs *entities.Config, db *sqlx.DB, repos *Repositories) broker.TweetBroker {
+	if configs.Stream.Backend == "postgres" {
+		tweetBroker, err := broker.InitializePostgresTweetBroker(db, config.ConnectionString(configs), configs.Stream.BufferSize, repos.TweetRepository.GetTweetByID)
+		if err != nil {
+			panic(err)
+		}
+		return tweetBroker
+	}
+
+	return broker.InitializeTweetBroker(configs.Stream.BufferSize)
+}
@@ -1,17 +1,24 @@
 package server
 
 import (
+	"restapi-tested-app/entities"
 	"restapi-tested-app/handlers"
 )
 
 type Handlers struct {
-	TweetHandler handlers.TweetHandler
+	TweetHandler      handlers.TweetHandler
+	TrendingHandler   handlers.TrendingHandler
+	AttachmentHandler handlers.AttachmentHandler
 }
 
-func SetupHandlers(uscs *Usecases) *Handlers {
+func SetupHandlers(uscs *Usecases, configs *entities.Config) *Handlers {
 	tweetHandlers := handlers.InitializeTweetHandler(uscs.TweetUsecase)
+	trendingHandlers := handlers.InitializeTrendingHandler(uscs.TrendingUsecase)
+	attachmentHandlers := handlers.InitializeAttachmentHandler(uscs.AttachmentUsecase, configs.Media.MaxSize)
 
 	return &Handlers{
-		TweetHandler: tweetHandlers,
+		TweetHandler:      tweetHandlers,
+		TrendingHandler:   trendingHandlers,
+		AttachmentHandler: attachmentHandlers,
 	}
 }
\ No newline at end of file
@@ -6,13 +6,16 @@ import (
 )
 
 type Repositories struct {
-	TweetRepository repositories.TweetRepository
+	TweetRepository      repositories.TweetRepository
+	AttachmentRepository repositories.AttachmentRepository
 }
 
 func SetupRepositories(db *sqlx.DB) *Repositories {
 	tweetRepository := repositories.InitializeTweetRepository(db)
+	attachmentRepository := repositories.InitializeAttachmentRepository(db)
 
 	return &Repositories{
-		TweetRepository: tweetRepository,
+		TweetRepository:      tweetRepository,
+		AttachmentRepository: attachmentRepository,
 	}
 }
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"restapi-tested-app/blobstore"
 	"restapi-tested-app/config"
 	"restapi-tested-app/entities"
 	"restapi-tested-app/utils"
@@ -30,6 +31,18 @@ func registerRoutes(router *gin.Engine, hndlrs *Handlers) {
 	router.POST("/tweet", serveHttp(hndlrs.TweetHandler.CreateTweet))
 	router.PUT("/tweet", serveHttp(hndlrs.TweetHandler.UpdateTweet))
 	router.DELETE("/tweet/:id", serveHttp(hndlrs.TweetHandler.DeleteTweet))
+	router.GET("/tweet/:id/thread", serveHttp(hndlrs.TweetHandler.GetTweetThread))
+	router.POST("/tweet/:id/reply", serveHttp(hndlrs.TweetHandler.ReplyTweet))
+	router.POST("/tweet/:id/like", serveHttp(hndlrs.TweetHandler.LikeTweet))
+	router.DELETE("/tweet/:id/like", serveHttp(hndlrs.TweetHandler.UnlikeTweet))
+	router.POST("/tweet/:id/retweet", serveHttp(hndlrs.TweetHandler.Retweet))
+	router.DELETE("/tweet/:id/retweet", serveHttp(hndlrs.TweetHandler.Unretweet))
+	router.GET("/tweets/hashtag/:tag", serveHttp(hndlrs.TweetHandler.GetTweetsByHashtag))
+	router.GET("/tweets/mention/:username", serveHttp(hndlrs.TweetHandler.GetTweetsByMention))
+	router.GET("/tweets/trending", serveHttp(hndlrs.TrendingHandler.GetTrendingHashtags))
+	router.GET("/tweets/stream", hndlrs.TweetHandler.StreamTweets)
+	router.POST("/tweet/:id/attachments", serveHttp(hndlrs.AttachmentHandler.AddAttachment))
+	router.GET("/attachments/:key", hndlrs.AttachmentHandler.DownloadAttachment)
 }
 
 func SetupServer() {
@@ -39,8 +52,16 @@ func SetupServer() {
 	db := config.ConnectDB(configs)
 
 	repos := SetupRepositories(db)
-	uscs := SetupUsecases(repos)
-	hdnlrs := SetupHandlers(uscs)
+	tweetBroker := SetupBroker(configs, db, repos)
+	blobStore, err := blobstore.InitializeLocalBlobStore(configs.Media.Dir)
+	if err != nil {
+		panic(err)
+	}
+	uscs := SetupUsecases(repos, tweetBroker, blobStore, configs)
+	hdnlrs := SetupHandlers(uscs, configs)
+
+	// keep the trending hashtags fresh for as long as the server runs
+	go uscs.TrendingUsecase.RunRefresher(nil)
 
 	router := gin.Default()
 
@@ -1,15 +1,26 @@
 package server
 
-import "restapi-tested-app/usecases"
+import (
+	"restapi-tested-app/blobstore"
+	"restapi-tested-app/broker"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/usecases"
+)
 
 type Usecases struct {
-	TweetUsecase usecases.TweetUsecase
+	TweetUsecase      usecases.TweetUsecase
+	TrendingUsecase   usecases.TrendingUsecase
+	AttachmentUsecase usecases.AttachmentUsecase
 }
 
-func SetupUsecases(repos *Repositories) *Usecases {
-	tweetUsecase := usecases.InitializeTweetUsecase(repos.TweetRepository)
+func SetupUsecases(repos *Repositories, tweetBroker broker.TweetBroker, blobStore blobstore.BlobStore, configs *entities.Config) *Usecases {
+	tweetUsecase := usecases.InitializeTweetUsecase(repos.TweetRepository, tweetBroker, blobStore)
+	trendingUsecase := usecases.InitializeTrendingUsecase(repos.TweetRepository, configs.Trending)
+	attachmentUsecase := usecases.InitializeAttachmentUsecase(repos.AttachmentRepository, blobStore, configs.Media)
 
 	return &Usecases{
-		TweetUsecase: tweetUsecase,
+		TweetUsecase:      tweetUsecase,
+		TrendingUsecase:   trendingUsecase,
+		AttachmentUsecase: attachmentUsecase,
 	}
 }
\ No newline at end of file
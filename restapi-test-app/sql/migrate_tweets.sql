@@ -0,0 +1,68 @@
+-- Upgrade a database created before replies, reactions, search and hashtags existed.
+-- tweet.sql only runs when the database volume is empty, so run this file on existing
+-- databases first and tweet.sql after it. Every statement can safely run again.
+BEGIN;
+
+-- Replies and reactions
+ALTER TABLE tweets
+    ADD COLUMN IF NOT EXISTS parent_id     INT REFERENCES tweets (id) ON DELETE CASCADE,
+    ADD COLUMN IF NOT EXISTS like_count    INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS reply_count   INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS retweet_count INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS tweets_parent_id_idx ON tweets (parent_id);
+
+CREATE TABLE IF NOT EXISTS likes
+(
+    tweet_id   INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    username   VARCHAR(128) NOT NULL,
+    created_at timestamptz  NOT NULL DEFAULT Now(),
+    PRIMARY KEY (tweet_id, username)
+);
+
+CREATE TABLE IF NOT EXISTS retweets
+(
+    tweet_id   INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    username   VARCHAR(128) NOT NULL,
+    created_at timestamptz  NOT NULL DEFAULT Now(),
+    PRIMARY KEY (tweet_id, username)
+);
+
+-- Recount from the source rows, so the counters are right however far an earlier run got
+UPDATE tweets t
+SET like_count    = (SELECT count(*) FROM likes l WHERE l.tweet_id = t.id),
+    retweet_count = (SELECT count(*) FROM retweets r WHERE r.tweet_id = t.id),
+    reply_count   = (SELECT count(*) FROM tweets c WHERE c.parent_id = t.id);
+
+-- Search, adding the generated column computes it for every existing tweet
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE tweets
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (to_tsvector('english', "text")) STORED;
+
+CREATE INDEX IF NOT EXISTS tweets_search_vector_idx ON tweets USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS tweets_text_trgm_idx ON tweets USING GIN ("text" gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS tweets_username_idx ON tweets (username, created_at);
+
+-- Hashtags and mentions, the rows of existing tweets are filled by running `go run ./cmd/reindex-tweets` after this script
+CREATE TABLE IF NOT EXISTS tweet_hashtags
+(
+    tweet_id   INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    tag        VARCHAR(140) NOT NULL,
+    created_at timestamptz  NOT NULL DEFAULT Now(),
+    PRIMARY KEY (tweet_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS tweet_hashtags_tag_idx ON tweet_hashtags (tag, created_at);
+CREATE INDEX IF NOT EXISTS tweet_hashtags_created_at_idx ON tweet_hashtags (created_at);
+
+CREATE TABLE IF NOT EXISTS tweet_mentions
+(
+    tweet_id INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    username VARCHAR(128) NOT NULL,
+    PRIMARY KEY (tweet_id, username)
+);
+
+CREATE INDEX IF NOT EXISTS tweet_mentions_username_idx ON tweet_mentions (username);
+
+COMMIT;
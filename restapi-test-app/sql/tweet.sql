@@ -1,8 +1,75 @@
 CREATE TABLE IF NOT EXISTS tweets
 (
-    id          serial PRIMARY KEY,
-    username    VARCHAR(128) NOT NULL,
-    "text"      TEXT         NOT NULL,
-    created_at  timestamptz  NOT NULL DEFAULT Now(),
-    modified_at timestamptz  NOT NULL DEFAULT Now()
-)
\ No newline at end of file
+    id            serial PRIMARY KEY,
+    username      VARCHAR(128) NOT NULL,
+    "text"        TEXT         NOT NULL,
+    parent_id     INT          REFERENCES tweets (id) ON DELETE CASCADE,
+    like_count    INT          NOT NULL DEFAULT 0,
+    reply_count   INT          NOT NULL DEFAULT 0,
+    retweet_count INT          NOT NULL DEFAULT 0,
+    created_at    timestamptz  NOT NULL DEFAULT Now(),
+    modified_at   timestamptz  NOT NULL DEFAULT Now(),
+    search_vector tsvector     GENERATED ALWAYS AS (to_tsvector('english', "text")) STORED
+);
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS tweets_search_vector_idx ON tweets USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS tweets_text_trgm_idx ON tweets USING GIN ("text" gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS tweets_username_idx ON tweets (username, created_at);
+
+CREATE INDEX IF NOT EXISTS tweets_parent_id_idx ON tweets (parent_id);
+
+CREATE TABLE IF NOT EXISTS likes
+(
+    tweet_id   INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    username   VARCHAR(128) NOT NULL,
+    created_at timestamptz  NOT NULL DEFAULT Now(),
+    PRIMARY KEY (tweet_id, username)
+);
+
+CREATE TABLE IF NOT EXISTS retweets
+(
+    tweet_id   INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    username   VARCHAR(128) NOT NULL,
+    created_at timestamptz  NOT NULL DEFAULT Now(),
+    PRIMARY KEY (tweet_id, username)
+);
+
+CREATE TABLE IF NOT EXISTS tweet_hashtags
+(
+    tweet_id   INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    tag        VARCHAR(140) NOT NULL,
+    created_at timestamptz  NOT NULL DEFAULT Now(),
+    PRIMARY KEY (tweet_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS tweet_hashtags_tag_idx ON tweet_hashtags (tag, created_at);
+CREATE INDEX IF NOT EXISTS tweet_hashtags_created_at_idx ON tweet_hashtags (created_at);
+
+CREATE TABLE IF NOT EXISTS tweet_mentions
+(
+    tweet_id INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    username VARCHAR(128) NOT NULL,
+    PRIMARY KEY (tweet_id, username)
+);
+
+CREATE INDEX IF NOT EXISTS tweet_mentions_username_idx ON tweet_mentions (username);
+
+-- ids of the events pushed to /tweets/stream, shared by every instance listening on the same database
+CREATE SEQUENCE IF NOT EXISTS tweet_events_id_seq;
+
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id            serial PRIMARY KEY,
+    tweet_id      INT          NOT NULL REFERENCES tweets (id) ON DELETE CASCADE,
+    content_type  VARCHAR(64)  NOT NULL,
+    size          BIGINT       NOT NULL,
+    width         INT          NOT NULL,
+    height        INT          NOT NULL,
+    blob_key      VARCHAR(128) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(128) NOT NULL UNIQUE,
+    created_at    timestamptz  NOT NULL DEFAULT Now()
+);
+
+CREATE INDEX IF NOT EXISTS attachments_tweet_id_idx ON attachments (tweet_id);
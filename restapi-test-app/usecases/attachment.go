@@ -0,0 +1,203 @@
+package usecases
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	// registers the gif decoder used by image.Decode
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"restapi-tested-app/blobstore"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/repositories"
+	"restapi-tested-app/utils"
+)
+
+const (
+	thumbnailSize = 320
+	// maxImageSide and maxImagePixels reject images that are small files but huge once decoded
+	maxImageSide = 8192
+	maxImagePixels = 40000000
+)
+
+// imageExtensions are the accepted content types, as sniffed from the uploaded bytes
+var imageExtensions = map[string]string{
+	"image/png": ".png",
+	"image/jpeg": ".jpg",
+	"image/gif": ".gif",
+}
+
+type attachmentUsecase struct {
+	attachmentRepository repositories.AttachmentRepository
+	blobStore blobstore.BlobStore
+	config entities.MediaConfig
+}
+
+type AttachmentUsecase interface {
+	AddAttachment(tweetID int, file io.Reader) (*entities.Attachment, error)
+	OpenAttachment(key string) (blobstore.Blob, *blobstore.BlobInfo, error)
+}
+
+func InitializeAttachmentUsecase(repository repositories.AttachmentRepository, blobStore blobstore.BlobStore, config entities.MediaConfig) AttachmentUsecase {
+	return &attachmentUsecase{repository, blobStore, config}
+}
+
+// AddAttachment validates an uploaded image, stores it with a thumbnail and attaches it to the tweet
+func (usecase *attachmentUsecase) AddAttachment(tweetID int, file io.Reader) (*entities.Attachment, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(file, usecase.config.MaxSize+1))
+	if err != nil {
+		return nil, &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	if int64(len(data)) > usecase.config.MaxSize {
+		return nil, &entities.AppError{
+			Err: fmt.Errorf("file can not be larger than %d bytes", usecase.config.MaxSize),
+			StatusCode: http.StatusRequestEntityTooLarge,
+		}
+	}
+
+	contentType := http.DetectContentType(data)
+	extension, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, &entities.AppError{
+			Err: errors.New("file must be a png, jpeg or gif image"),
+			StatusCode: http.StatusUnsupportedMediaType,
+		}
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width > maxImageSide || config.Height > maxImageSide || config.Width*config.Height > maxImagePixels {
+		return nil, &entities.AppError{
+			Err: errors.New("image is corrupted or too large"),
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, &entities.AppError{
+			Err: errors.New("image is corrupted or too large"),
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	thumbnail, err := encodeThumbnail(img, contentType)
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	key, err := blobKey()
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	attachment := entities.Attachment{
+		TweetID: tweetID,
+		ContentType: contentType,
+		Size: int64(len(data)),
+		Width: config.Width,
+		Height: config.Height,
+		BlobKey: key + extension,
+		ThumbnailKey: key + "_thumb" + thumbnailExtension(contentType),
+	}
+
+	err = usecase.blobStore.Put(attachment.BlobKey, bytes.NewReader(data))
+	if err == nil {
+		err = usecase.blobStore.Put(attachment.ThumbnailKey, thumbnail)
+	}
+	if err == nil {
+		err = usecase.attachmentRepository.CreateAttachment(&attachment, usecase.config.MaxPerTweet)
+	}
+	if err != nil {
+		usecase.blobStore.Delete(attachment.BlobKey)
+		usecase.blobStore.Delete(attachment.ThumbnailKey)
+	}
+
+	switch err {
+	case nil:
+		attachment.SetURLs()
+		return &attachment, nil
+	case repositories.ErrTweetNotFound:
+		return nil, &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusNotFound,
+		}
+	case repositories.ErrTooManyAttachments:
+		return nil, &entities.AppError{
+			Err: fmt.Errorf("a tweet can not have more than %d attachments", usecase.config.MaxPerTweet),
+			StatusCode: http.StatusConflict,
+		}
+	default:
+		return nil, internalError(err)
+	}
+}
+
+// OpenAttachment opens the blob of an attachment or its thumbnail, as long as the attachment still exists
+func (usecase *attachmentUsecase) OpenAttachment(key string) (blobstore.Blob, *blobstore.BlobInfo, error) {
+	notFound := &entities.AppError{
+		Err: errors.New("attachment is not found"),
+		StatusCode: http.StatusNotFound,
+	}
+
+	attachment, _ := usecase.attachmentRepository.GetAttachmentByKey(key)
+	if attachment == nil {
+		return nil, nil, notFound
+	}
+
+	blob, info, err := usecase.blobStore.Open(key)
+	if err == blobstore.ErrBlobNotFound {
+		return nil, nil, notFound
+	}
+	if err != nil {
+		return nil, nil, internalError(err)
+	}
+
+	return blob, info, nil
+}
+
+// encodeThumbnail keeps jpeg thumbnails for photos and png for the rest, which may be transparent
+func encodeThumbnail(img image.Image, contentType string) (*bytes.Buffer, error) {
+	var buffer bytes.Buffer
+	thumbnail := utils.Thumbnail(img, thumbnailSize)
+
+	var err error
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&buffer, thumbnail, &jpeg.Options{Quality: 80})
+	} else {
+		err = png.Encode(&buffer, thumbnail)
+	}
+
+	return &buffer, err
+}
+
+func thumbnailExtension(contentType string) string {
+	if contentType == "image/jpeg" {
+		return ".jpg"
+	}
+	return ".png"
+}
+
+func blobKey() (string, error) {
+	key := make([]byte, 16)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
+
+func internalError(err error) error {
+	return &entities.AppError{
+		Err: err,
+		StatusCode: http.StatusInternalServerError,
+	}
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"restapi-tested-app/blobstore"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/mocks"
+	"restapi-tested-app/repositories"
+	"strings"
+	"testing"
+)
+
+type attachmentUsecaseSuite struct {
+	suite.Suite
+	repository *mocks.AttachmentRepository
+	dir string
+	blobStore blobstore.BlobStore
+	usecase AttachmentUsecase
+}
+
+func (suite *attachmentUsecaseSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "attachments")
+	suite.NoError(err)
+	blobStore, err := blobstore.InitializeLocalBlobStore(dir)
+	suite.NoError(err)
+
+	suite.repository = new(mocks.AttachmentRepository)
+	suite.dir = dir
+	suite.blobStore = blobStore
+	suite.usecase = InitializeAttachmentUsecase(suite.repository, blobStore, entities.MediaConfig{
+		MaxSize: 1 << 20,
+		MaxPerTweet: 4,
+	})
+}
+
+func (suite *attachmentUsecaseSuite) TearDownTest() {
+	os.RemoveAll(suite.dir)
+}
+
+func (suite *attachmentUsecaseSuite) pngImage(width, height int) *bytes.Buffer {
+	var buffer bytes.Buffer
+	err := png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height)))
+	suite.NoError(err)
+	return &buffer
+}
+
+func (suite *attachmentUsecaseSuite) blobs() []string {
+	files, err := ioutil.ReadDir(suite.dir)
+	suite.NoError(err)
+
+	names := []string{}
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	return names
+}
+
+func (suite *attachmentUsecaseSuite) TestAddAttachment_Positive() {
+	suite.repository.On("CreateAttachment", mock.AnythingOfType("*entities.Attachment"), 4).Return(nil)
+
+	attachment, err := suite.usecase.AddAttachment(1, suite.pngImage(640, 480))
+	suite.NoError(err, "no error when attach a valid image")
+	suite.Equal("image/png", attachment.ContentType)
+	suite.Equal(640, attachment.Width)
+	suite.Equal(480, attachment.Height)
+	suite.True(strings.HasPrefix(attachment.URL, "/attachments/"))
+	suite.True(strings.HasSuffix(attachment.ThumbnailURL, "_thumb.png"))
+	suite.Len(suite.blobs(), 2, "the image and its thumbnail are stored")
+
+	thumbnail, _, err := suite.blobStore.Open(attachment.ThumbnailKey)
+	suite.NoError(err)
+	defer thumbnail.Close()
+	config, err := png.DecodeConfig(thumbnail)
+	suite.NoError(err)
+	suite.Equal(320, config.Width, "thumbnail is scaled down")
+	suite.Equal(240, config.Height, "thumbnail keeps the aspect ratio")
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *attachmentUsecaseSuite) TestAddAttachment_NotAnImage_Negative() {
+	_, err := suite.usecase.AddAttachment(1, strings.NewReader("<html>not an image</html>"))
+	suite.Equal(415, err.(*entities.AppError).StatusCode)
+	suite.Empty(suite.blobs())
+}
+
+func (suite *attachmentUsecaseSuite) TestAddAttachment_TooLarge_Negative() {
+	_, err := suite.usecase.AddAttachment(1, bytes.NewReader(make([]byte, 1<<20+1)))
+	suite.Equal(413, err.(*entities.AppError).StatusCode)
+}
+
+func (suite *attachmentUsecaseSuite) TestAddAttachment_TooManyAttachments_Negative() {
+	suite.repository.On("CreateAttachment", mock.Anything, 4).Return(repositories.ErrTooManyAttachments)
+
+	_, err := suite.usecase.AddAttachment(1, suite.pngImage(10, 10))
+	suite.Equal(409, err.(*entities.AppError).StatusCode)
+	suite.Empty(suite.blobs(), "stored blobs are removed again")
+}
+
+func (suite *attachmentUsecaseSuite) TestOpenAttachment_Deleted_Negative() {
+	suite.NoError(suite.blobStore.Put("orphan.png", suite.pngImage(10, 10)))
+	suite.repository.On("GetAttachmentByKey", "orphan.png").Return(nil, repositories.ErrTweetNotFound)
+
+	_, _, err := suite.usecase.OpenAttachment("orphan.png")
+	suite.Equal(404, err.(*entities.AppError).StatusCode, "blobs of deleted tweets are not served")
+}
+
+func TestAttachmentUsecase(t *testing.T) {
+	suite.Run(t, new(attachmentUsecaseSuite))
+}
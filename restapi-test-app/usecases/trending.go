@@ -0,0 +1,139 @@
+package usecases
+
+import (
+	"math"
+	"net/http"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/repositories"
+	"sort"
+	"sync"
+	"time"
+)
+
+type trendingUsecase struct {
+	tweetRepository repositories.TweetRepository
+	config entities.TrendingConfig
+	now func() time.Time
+
+	mutex sync.RWMutex
+	hashtags []entities.TrendingHashtag
+	refreshed bool
+}
+
+// TrendingUsecase keeps an in-memory ranking of hashtags used within a sliding window.
+// Each use is weighted by its age, halving every HalfLife, so recent activity ranks higher.
+type TrendingUsecase interface {
+	GetTrendingHashtags(limit int) ([]entities.TrendingHashtag, error)
+	Refresh() error
+	RunRefresher(stop <-chan struct{})
+}
+
+func InitializeTrendingUsecase(repository repositories.TweetRepository, config entities.TrendingConfig) TrendingUsecase {
+	return InitializeTrendingUsecaseWithClock(repository, config, time.Now)
+}
+
+// InitializeTrendingUsecaseWithClock is InitializeTrendingUsecase with a custom clock, mostly useful for tests
+func InitializeTrendingUsecaseWithClock(repository repositories.TweetRepository, config entities.TrendingConfig, now func() time.Time) TrendingUsecase {
+	return &trendingUsecase{
+		tweetRepository: repository,
+		config: config,
+		now: now,
+	}
+}
+
+// GetTrendingHashtags returns the top hashtags of the last refresh, refreshing first if it never ran
+func (usecase *trendingUsecase) GetTrendingHashtags(limit int) ([]entities.TrendingHashtag, error) {
+	usecase.mutex.RLock()
+	refreshed := usecase.refreshed
+	usecase.mutex.RUnlock()
+
+	if !refreshed {
+		if err := usecase.Refresh(); err != nil {
+			return nil, &entities.AppError{
+				Err: err,
+				StatusCode: http.StatusInternalServerError,
+			}
+		}
+	}
+
+	usecase.mutex.RLock()
+	defer usecase.mutex.RUnlock()
+
+	if limit <= 0 || limit > len(usecase.hashtags) {
+		limit = len(usecase.hashtags)
+	}
+	result := make([]entities.TrendingHashtag, limit)
+	copy(result, usecase.hashtags[:limit])
+
+	return result, nil
+}
+
+// Refresh recomputes the ranking from the hourly hashtag counts inside the window
+func (usecase *trendingUsecase) Refresh() error {
+	now := usecase.now()
+
+	counts, err := usecase.tweetRepository.GetHashtagCounts(now.Add(-usecase.config.Window))
+	if err != nil {
+		return err
+	}
+
+	hashtags := rankHashtags(*counts, now, usecase.config.HalfLife)
+
+	usecase.mutex.Lock()
+	usecase.hashtags = hashtags
+	usecase.refreshed = true
+	usecase.mutex.Unlock()
+
+	return nil
+}
+
+// RunRefresher refreshes the ranking every RefreshInterval until stop is closed
+func (usecase *trendingUsecase) RunRefresher(stop <-chan struct{}) {
+	ticker := time.NewTicker(usecase.config.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// a failed refresh keeps serving the previous ranking
+			usecase.Refresh()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func rankHashtags(counts []entities.HashtagCount, now time.Time, halfLife time.Duration) []entities.TrendingHashtag {
+	byTag := map[string]*entities.TrendingHashtag{}
+
+	for _, count := range counts {
+		hashtag, ok := byTag[count.Tag]
+		if !ok {
+			hashtag = &entities.TrendingHashtag{Tag: count.Tag}
+			byTag[count.Tag] = hashtag
+		}
+
+		// a bucket holds one hour of uses, take its middle as their age
+		age := now.Sub(count.Bucket.Add(30 * time.Minute))
+		if age < 0 {
+			age = 0
+		}
+
+		hashtag.Count += count.Count
+		hashtag.Score += float64(count.Count) * math.Pow(0.5, float64(age)/float64(halfLife))
+	}
+
+	result := make([]entities.TrendingHashtag, 0, len(byTag))
+	for _, hashtag := range byTag {
+		result = append(result, *hashtag)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		return result[i].Tag < result[j].Tag
+	})
+
+	return result
+}
@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"restapi-tested-app/entities"
+	"restapi-tested-app/mocks"
+	"testing"
+	"time"
+)
+
+type trendingUsecaseSuite struct {
+	suite.Suite
+	repository *mocks.TweetRepository
+	usecase TrendingUsecase
+	now time.Time
+}
+
+func (suite *trendingUsecaseSuite) SetupTest() {
+	suite.now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	suite.repository = new(mocks.TweetRepository)
+	suite.usecase = InitializeTrendingUsecaseWithClock(suite.repository, entities.TrendingConfig{
+		Window: 24 * time.Hour,
+		HalfLife: 6 * time.Hour,
+		RefreshInterval: time.Minute,
+	}, func() time.Time { return suite.now })
+}
+
+func (suite *trendingUsecaseSuite) TestGetTrendingHashtags_RecentUsesRankHigher_Positive() {
+	counts := []entities.HashtagCount{
+		// used a lot, but almost a day ago
+		{Tag: "old", Bucket: suite.now.Add(-23 * time.Hour), Count: 10},
+		// used less, but within the last hour
+		{Tag: "new", Bucket: suite.now.Add(-time.Hour), Count: 4},
+		{Tag: "new", Bucket: suite.now.Add(-2 * time.Hour), Count: 2},
+		{Tag: "tiny", Bucket: suite.now.Add(-20 * time.Hour), Count: 1},
+	}
+	suite.repository.On("GetHashtagCounts", suite.now.Add(-24*time.Hour)).Return(&counts, nil).Once()
+
+	hashtags, err := suite.usecase.GetTrendingHashtags(2)
+	suite.NoError(err, "no error when the counts are available")
+	suite.Len(hashtags, 2, "result is limited")
+	suite.Equal("new", hashtags[0].Tag, "recent hashtag is ranked first")
+	suite.Equal(6, hashtags[0].Count)
+	suite.Equal("old", hashtags[1].Tag)
+	suite.True(hashtags[0].Score > hashtags[1].Score)
+
+	// the ranking is cached until the next refresh
+	_, err = suite.usecase.GetTrendingHashtags(10)
+	suite.NoError(err)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *trendingUsecaseSuite) TestRefresh_KeepsPreviousRankingOnError_Negative() {
+	counts := []entities.HashtagCount{
+		{Tag: "go", Bucket: suite.now.Add(-time.Hour), Count: 1},
+	}
+	suite.repository.On("GetHashtagCounts", mock.Anything).Return(&counts, nil).Once()
+	suite.repository.On("GetHashtagCounts", mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+	suite.NoError(suite.usecase.Refresh())
+	suite.Error(suite.usecase.Refresh())
+
+	hashtags, err := suite.usecase.GetTrendingHashtags(10)
+	suite.NoError(err)
+	suite.Len(hashtags, 1)
+	suite.Equal("go", hashtags[0].Tag)
+}
+
+func (suite *trendingUsecaseSuite) TestGetTrendingHashtags_RepositoryError_Negative() {
+	suite.repository.On("GetHashtagCounts", mock.Anything).Return(nil, errors.New("connection refused"))
+
+	hashtags, err := suite.usecase.GetTrendingHashtags(10)
+	suite.Nil(hashtags)
+	suite.Equal(500, err.(*entities.AppError).StatusCode)
+}
+
+func TestTrendingUsecase(t *testing.T) {
+	suite.Run(t, new(trendingUsecaseSuite))
+}
@@ -2,27 +2,43 @@ package usecases
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"restapi-tested-app/blobstore"
+	"restapi-tested-app/broker"
 	"restapi-tested-app/entities"
 	"restapi-tested-app/repositories"
+	"restapi-tested-app/utils"
+	"strings"
 	"time"
 )
 
 type tweetUsecase struct {
 	tweetRepository repositories.TweetRepository
+	tweetBroker broker.TweetBroker
+	blobStore blobstore.BlobStore
 }
 
 type TweetUsecase interface {
 	GetAllTweets() (*[]entities.Tweet, error)
 	GetTweetByID(id int) (*entities.Tweet, error)
-	SearchTweetByText(text string) (*[]entities.Tweet, error)
+	SearchTweetByText(options entities.SearchOptions) (*entities.SearchResult, error)
 	CreateTweet(tweet *entities.Tweet) error
 	UpdateTweet(tweet *entities.Tweet) error
 	DeleteTweet(id int) error
+	GetTweetThread(id int) (*entities.TweetThread, error)
+	ReplyTweet(parentID int, tweet *entities.Tweet) error
+	LikeTweet(id int, username string) error
+	UnlikeTweet(id int, username string) error
+	Retweet(id int, username string) error
+	Unretweet(id int, username string) error
+	GetTweetsByHashtag(tag string) (*[]entities.Tweet, error)
+	GetTweetsByMention(username string) (*[]entities.Tweet, error)
+	SubscribeTweets(lastEventID int64) ([]entities.TweetEvent, <-chan entities.TweetEvent, func())
 }
 
-func InitializeTweetUsecase(repository repositories.TweetRepository) TweetUsecase {
-	return &tweetUsecase{repository}
+func InitializeTweetUsecase(repository repositories.TweetRepository, tweetBroker broker.TweetBroker, blobStore blobstore.BlobStore) TweetUsecase {
+	return &tweetUsecase{repository, tweetBroker, blobStore}
 }
 
 func (usecase *tweetUsecase) GetAllTweets() (*[]entities.Tweet, error) {
@@ -40,8 +56,108 @@ func (usecase *tweetUsecase) GetTweetByID(id int) (*entities.Tweet, error) {
 	return tweet, nil
 }
 
-func (usecase *tweetUsecase) SearchTweetByText(text string) (*[]entities.Tweet, error) {
-	return usecase.tweetRepository.SearchTweetByText("%"+text+"%")
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit = 100
+)
+
+// SearchTweetByText searches the full-text index and falls back to trigram similarity when nothing matches.
+// The cursor of the next page remembers which of the two was used.
+func (usecase *tweetUsecase) SearchTweetByText(options entities.SearchOptions) (*entities.SearchResult, error) {
+	query, err := searchQuery(options)
+	if err != nil {
+		return nil, err
+	}
+
+	hits, err := usecase.searchPage(query)
+	if err == nil && len(*hits) == 0 && query.AfterRank == nil && query.Mode == entities.SearchFullText {
+		query.Mode = entities.SearchTrigram
+		hits, err = usecase.searchPage(query)
+	}
+	if err != nil {
+		return nil, &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	result := entities.SearchResult{
+		Tweets: *hits,
+	}
+	if len(result.Tweets) > query.Limit {
+		result.Tweets = result.Tweets[:query.Limit]
+		last := result.Tweets[query.Limit-1]
+		result.NextCursor = utils.EncodeSearchCursor(query.Mode, last.Rank, last.ID)
+	}
+
+	if query.Mode == entities.SearchTrigram {
+		for i := range result.Tweets {
+			result.Tweets[i].Highlight = utils.HighlightMatch(result.Tweets[i].Text, query.Text)
+		}
+	}
+
+	return &result, nil
+}
+
+// searchPage fetches one tweet more than the page size, to know whether there is a next page
+func (usecase *tweetUsecase) searchPage(query entities.SearchQuery) (*[]entities.SearchHit, error) {
+	query.Limit++
+	hits, err := usecase.tweetRepository.SearchTweetByText(query)
+	if err != nil {
+		return nil, err
+	}
+	if hits == nil {
+		hits = &[]entities.SearchHit{}
+	}
+
+	return hits, nil
+}
+
+func searchQuery(options entities.SearchOptions) (entities.SearchQuery, error) {
+	query := entities.SearchQuery{
+		Mode: entities.SearchFullText,
+		Text: strings.TrimSpace(options.Text),
+		Username: options.Username,
+		From: options.From,
+		To: options.To,
+		Limit: options.Limit,
+	}
+
+	if query.Text == "" {
+		return query, &entities.AppError{
+			Err: errors.New("search keyword cannot be empty"),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return query, &entities.AppError{
+			Err: errors.New("from must be before to"),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+
+	if options.Cursor != "" {
+		mode, rank, id, err := utils.DecodeSearchCursor(options.Cursor)
+		if err != nil || (mode != entities.SearchFullText && mode != entities.SearchTrigram) {
+			return query, &entities.AppError{
+				Err: utils.ErrInvalidCursor,
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		query.Mode = mode
+		query.AfterRank = &rank
+		query.AfterID = id
+	}
+
+	return query, nil
 }
 
 func (usecase *tweetUsecase) CreateTweet(tweet *entities.Tweet) error {
@@ -59,21 +175,234 @@ func (usecase *tweetUsecase) CreateTweet(tweet *entities.Tweet) error {
 		}
 	}
 	err := usecase.tweetRepository.CreateTweet(tweet)
+	if err == repositories.ErrTweetNotFound {
+		return &entities.AppError{
+			Err: errors.New("tweet to reply is not found"),
+			StatusCode: http.StatusNotFound,
+		}
+	}
 	if err != nil {
 		return &entities.AppError{
 			Err: err,
 			StatusCode: http.StatusInternalServerError,
 		}
 	}
-	return nil
+
+	return usecase.publish(entities.TweetEvent{
+		Type: entities.TweetCreated,
+		Tweet: *tweet,
+	})
 }
 
 func (usecase *tweetUsecase) UpdateTweet(tweet *entities.Tweet) error {
 	tweet.ModifiedAt = time.Now()
 	err := usecase.tweetRepository.UpdateTweet(tweet)
-	return err
+	if err == repositories.ErrTweetNotFound {
+		return &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	if err != nil {
+		return &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	// the stream sends the stored tweet, the request does not carry its counters
+	updated, err := usecase.tweetRepository.GetTweetByID(tweet.ID)
+	if err != nil {
+		return &entities.AppError{
+			Err: fmt.Errorf("tweet is saved but could not be reloaded for the stream: %w", err),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+	return usecase.publish(entities.TweetEvent{
+		Type: entities.TweetUpdated,
+		Tweet: *updated,
+	})
 }
 
 func (usecase *tweetUsecase) DeleteTweet(id int) error {
-	return usecase.tweetRepository.DeleteTweet(id)
+	attachments, err := usecase.tweetRepository.DeleteTweet(id)
+	if err == repositories.ErrTweetNotFound {
+		return &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	if err != nil {
+		return &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	// the attachment rows are gone with the tweet, a blob that fails to delete is only wasted space
+	for _, attachment := range attachments {
+		usecase.blobStore.Delete(attachment.BlobKey)
+		usecase.blobStore.Delete(attachment.ThumbnailKey)
+	}
+
+	return usecase.publish(entities.TweetEvent{
+		Type: entities.TweetDeleted,
+		Tweet: entities.Tweet{ID: id},
+	})
+}
+
+// publish sends the event to the stream, the change is already stored when it fails
+func (usecase *tweetUsecase) publish(event entities.TweetEvent) error {
+	err := usecase.tweetBroker.Publish(event)
+	if err != nil {
+		return &entities.AppError{
+			Err: fmt.Errorf("tweet is saved but could not be sent to the stream: %w", err),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+func (usecase *tweetUsecase) GetTweetThread(id int) (*entities.TweetThread, error) {
+	tweets, err := usecase.tweetRepository.GetTweetThread(id)
+	if err != nil {
+		return nil, &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+	if tweets == nil || len(*tweets) == 0 {
+		return nil, &entities.AppError{
+			Err: errors.New("tweet is not found"),
+			StatusCode: http.StatusNotFound,
+		}
+	}
+
+	return buildThread(*tweets), nil
+}
+
+// buildThread nests the replies under their parents, the first tweet is the root of the thread
+func buildThread(tweets []entities.Tweet) *entities.TweetThread {
+	nodes := make(map[int]*entities.TweetThread, len(tweets))
+	for _, tweet := range tweets {
+		nodes[tweet.ID] = &entities.TweetThread{
+			Tweet: tweet,
+			Replies: []*entities.TweetThread{},
+		}
+	}
+
+	root := nodes[tweets[0].ID]
+	for _, tweet := range tweets[1:] {
+		if tweet.ParentID == nil {
+			continue
+		}
+		if parent, ok := nodes[*tweet.ParentID]; ok {
+			parent.Replies = append(parent.Replies, nodes[tweet.ID])
+		}
+	}
+
+	return root
+}
+
+func (usecase *tweetUsecase) ReplyTweet(parentID int, tweet *entities.Tweet) error {
+	if tweet == nil {
+		return &entities.AppError{
+			Err: errors.New("tweet is nil pointer"),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	tweet.ParentID = &parentID
+	return usecase.CreateTweet(tweet)
+}
+
+func (usecase *tweetUsecase) LikeTweet(id int, username string) error {
+	return reactionError(username, func() error {
+		return usecase.tweetRepository.LikeTweet(id, username)
+	})
+}
+
+func (usecase *tweetUsecase) UnlikeTweet(id int, username string) error {
+	return reactionError(username, func() error {
+		return usecase.tweetRepository.UnlikeTweet(id, username)
+	})
+}
+
+func (usecase *tweetUsecase) Retweet(id int, username string) error {
+	return reactionError(username, func() error {
+		return usecase.tweetRepository.Retweet(id, username)
+	})
+}
+
+func (usecase *tweetUsecase) Unretweet(id int, username string) error {
+	return reactionError(username, func() error {
+		return usecase.tweetRepository.Unretweet(id, username)
+	})
+}
+
+// reactionError validates the username, runs the reaction and maps repository errors to an AppError
+func reactionError(username string, react func() error) error {
+	if username == "" {
+		return &entities.AppError{
+			Err: errors.New("username cannot be empty"),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	err := react()
+	if err == repositories.ErrTweetNotFound {
+		return &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	if err != nil {
+		return &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+func (usecase *tweetUsecase) GetTweetsByHashtag(tag string) (*[]entities.Tweet, error) {
+	tag = utils.NormalizeHashtag(tag)
+	if tag == "" {
+		return nil, &entities.AppError{
+			Err: errors.New("hashtag cannot be empty"),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return tweetsOrError(usecase.tweetRepository.GetTweetsByHashtag(tag))
+}
+
+func (usecase *tweetUsecase) GetTweetsByMention(username string) (*[]entities.Tweet, error) {
+	username = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+	if username == "" {
+		return nil, &entities.AppError{
+			Err: errors.New("username cannot be empty"),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	return tweetsOrError(usecase.tweetRepository.GetTweetsByMention(username))
+}
+
+func tweetsOrError(tweets *[]entities.Tweet, err error) (*[]entities.Tweet, error) {
+	if err != nil {
+		return nil, &entities.AppError{
+			Err: err,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	return tweets, nil
+}
+
+// SubscribeTweets returns the events missed since lastEventID and a channel of the upcoming ones
+func (usecase *tweetUsecase) SubscribeTweets(lastEventID int64) ([]entities.TweetEvent, <-chan entities.TweetEvent, func()) {
+	return usecase.tweetBroker.Subscribe(lastEventID)
 }
\ No newline at end of file
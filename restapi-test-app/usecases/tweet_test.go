@@ -2,10 +2,15 @@ package usecases
 
 import (
 	"errors"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"net/http"
 	"restapi-tested-app/entities"
 	"restapi-tested-app/mocks"
+	"restapi-tested-app/repositories"
+	"restapi-tested-app/utils"
 	"testing"
+	"time"
 )
 
 type tweetUsecaseSuite struct {
@@ -13,6 +18,10 @@ type tweetUsecaseSuite struct {
 	suite.Suite
 	// the generated mocked version of our repository
 	repository *mocks.TweetRepository
+	// the mocked broker receiving the tweet events
+	broker *mocks.TweetBroker
+	// the mocked store holding the attachment blobs
+	blobStore *mocks.BlobStore
 	// the functionalities we want to test
 	usecase TweetUsecase
 }
@@ -20,11 +29,15 @@ type tweetUsecaseSuite struct {
 func (suite *tweetUsecaseSuite) SetupTest() {
 	// instantiate the mocked version of repository
 	repository := new(mocks.TweetRepository)
+	broker := new(mocks.TweetBroker)
+	blobStore := new(mocks.BlobStore)
 	// inject the repository to usecase, since usecase needs repository to work
-	usecase := InitializeTweetUsecase(repository)
+	usecase := InitializeTweetUsecase(repository, broker, blobStore)
 
 	// assign them as the suite properties
 	suite.repository = repository
+	suite.broker = broker
+	suite.blobStore = blobStore
 	suite.usecase = usecase
 }
 
@@ -38,6 +51,8 @@ func (suite *tweetUsecaseSuite) TestCreateTweet_Positive() {
 	// specify that inside usecase's CreateTweet method
 	// repository's CreateTweet method will be called
 	suite.repository.On("CreateTweet", &tweet).Return(nil)
+	// and the new tweet is published to the stream
+	suite.broker.On("Publish", entities.TweetEvent{Type: entities.TweetCreated, Tweet: tweet}).Return(nil)
 
 	// the real operation we need to test
 	err := suite.usecase.CreateTweet(&tweet)
@@ -45,6 +60,7 @@ func (suite *tweetUsecaseSuite) TestCreateTweet_Positive() {
 	// assertions to make sure our operation does the right thing
 	suite.Nil(err, "err is a nil pointer so no error in this process")
 	suite.repository.AssertExpectations(suite.T())
+	suite.broker.AssertExpectations(suite.T())
 }
 
 func (suite *tweetUsecaseSuite) TestCreateTweet_NilPointer_Negative() {
@@ -109,6 +125,286 @@ func (suite *tweetUsecaseSuite) TestGetTweetByID_Exists_Positive() {
 	suite.Equal(tweet, *result, "result and tweet should be equal")
 }
 
+func (suite *tweetUsecaseSuite) TestGetTweetThread_NestedReplies_Positive() {
+	id := 1
+	rootID, replyID := 1, 2
+	tweets := []entities.Tweet{
+		{ID: 1, Username: "username", Text: "root", ReplyCount: 2},
+		{ID: 2, Username: "username", Text: "reply", ParentID: &rootID, ReplyCount: 1},
+		{ID: 3, Username: "username", Text: "another reply", ParentID: &rootID},
+		{ID: 4, Username: "username", Text: "nested reply", ParentID: &replyID},
+	}
+
+	suite.repository.On("GetTweetThread", id).Return(&tweets, nil)
+
+	thread, err := suite.usecase.GetTweetThread(id)
+	suite.NoError(err, "no error when the tweet exists")
+	suite.Equal(1, thread.ID, "the requested tweet is the root of the thread")
+	suite.Len(thread.Replies, 2, "root has two direct replies")
+	suite.Equal(2, thread.Replies[0].ID)
+	suite.Len(thread.Replies[0].Replies, 1, "the first reply has a nested reply")
+	suite.Equal(4, thread.Replies[0].Replies[0].ID)
+	suite.Empty(thread.Replies[1].Replies, "the second reply has no replies")
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestGetTweetThread_NotFound_Negative() {
+	id := 1
+	emptyTweets := []entities.Tweet(nil)
+
+	suite.repository.On("GetTweetThread", id).Return(&emptyTweets, nil)
+
+	thread, err := suite.usecase.GetTweetThread(id)
+	suite.Nil(thread, "error is returned so thread has to be nil")
+	suite.Equal(err.(*entities.AppError).StatusCode, 404)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestReplyTweet_SetsParent_Positive() {
+	parentID := 1
+	tweet := entities.Tweet{
+		Username: "username",
+		Text: "text",
+	}
+
+	suite.repository.On("CreateTweet", &tweet).Return(nil)
+	suite.broker.On("Publish", mock.Anything).Return(nil)
+
+	err := suite.usecase.ReplyTweet(parentID, &tweet)
+	suite.Nil(err, "no error when reply to an existing tweet")
+	suite.Equal(parentID, *tweet.ParentID, "parent id is set on the reply")
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestReplyTweet_ParentNotFound_Negative() {
+	tweet := entities.Tweet{
+		Username: "username",
+		Text: "text",
+	}
+
+	suite.repository.On("CreateTweet", &tweet).Return(repositories.ErrTweetNotFound)
+
+	err := suite.usecase.ReplyTweet(1, &tweet)
+	suite.Equal(err.(*entities.AppError).StatusCode, 404)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestLikeTweet_Positive() {
+	suite.repository.On("LikeTweet", 1, "username").Return(nil)
+
+	err := suite.usecase.LikeTweet(1, "username")
+	suite.Nil(err, "no error when like an existing tweet")
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestLikeTweet_EmptyUsername_Negative() {
+	err := suite.usecase.LikeTweet(1, "")
+	suite.Equal(err.(*entities.AppError).StatusCode, 400)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestRetweet_NotFound_Negative() {
+	suite.repository.On("Retweet", 1, "username").Return(repositories.ErrTweetNotFound)
+
+	err := suite.usecase.Retweet(1, "username")
+	suite.Equal(err.(*entities.AppError).StatusCode, 404)
+	suite.Equal(err.Error(), "tweet is not found")
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestGetTweetsByHashtag_Normalized_Positive() {
+	tweets := []entities.Tweet{
+		{
+			Username: "username",
+			Text: "learning #golang",
+		},
+	}
+
+	suite.repository.On("GetTweetsByHashtag", "golang").Return(&tweets, nil)
+
+	result, err := suite.usecase.GetTweetsByHashtag("#GoLang")
+	suite.NoError(err, "no error when get tweets by hashtag")
+	suite.Equal(tweets, *result)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestGetTweetsByHashtag_Empty_Negative() {
+	_, err := suite.usecase.GetTweetsByHashtag("#")
+	suite.Equal(err.(*entities.AppError).StatusCode, 400)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestCreateTweet_Invalid_NotPublished_Negative() {
+	var tweet entities.Tweet
+
+	err := suite.usecase.CreateTweet(&tweet)
+	suite.Equal(err.(*entities.AppError).StatusCode, 400)
+	suite.broker.AssertNotCalled(suite.T(), "Publish", mock.Anything)
+}
+
+func (suite *tweetUsecaseSuite) TestUpdateTweet_PublishesStoredTweet_Positive() {
+	tweet := entities.Tweet{
+		ID: 1,
+		Username: "username",
+		Text: "edited",
+	}
+	stored := entities.Tweet{
+		ID: 1,
+		Username: "username",
+		Text: "edited",
+		LikeCount: 3,
+	}
+
+	suite.repository.On("UpdateTweet", &tweet).Return(nil)
+	suite.repository.On("GetTweetByID", 1).Return(&stored, nil)
+	suite.broker.On("Publish", entities.TweetEvent{Type: entities.TweetUpdated, Tweet: stored}).Return(nil)
+
+	err := suite.usecase.UpdateTweet(&tweet)
+	suite.Nil(err, "no error when update tweet")
+	suite.broker.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestUpdateTweet_NotFound_NotPublished_Negative() {
+	tweet := entities.Tweet{ID: 1, Username: "username", Text: "edited"}
+	suite.repository.On("UpdateTweet", &tweet).Return(repositories.ErrTweetNotFound)
+
+	err := suite.usecase.UpdateTweet(&tweet)
+	suite.Equal(http.StatusNotFound, err.(*entities.AppError).StatusCode, "updating a missing tweet is not found")
+	suite.broker.AssertNotCalled(suite.T(), "Publish", mock.Anything)
+}
+
+func (suite *tweetUsecaseSuite) TestUpdateTweet_ReloadFails_Negative() {
+	tweet := entities.Tweet{ID: 1, Username: "username", Text: "edited"}
+	suite.repository.On("UpdateTweet", &tweet).Return(nil)
+	suite.repository.On("GetTweetByID", 1).Return(nil, errors.New("connection reset"))
+
+	err := suite.usecase.UpdateTweet(&tweet)
+	suite.Equal(http.StatusInternalServerError, err.(*entities.AppError).StatusCode, "a failed reload is reported")
+	suite.broker.AssertNotCalled(suite.T(), "Publish", mock.Anything)
+}
+
+func (suite *tweetUsecaseSuite) TestDeleteTweet_PublishesId_Positive() {
+	suite.repository.On("DeleteTweet", 1).Return(nil, nil)
+	suite.broker.On("Publish", entities.TweetEvent{Type: entities.TweetDeleted, Tweet: entities.Tweet{ID: 1}}).Return(nil)
+
+	err := suite.usecase.DeleteTweet(1)
+	suite.Nil(err, "no error when delete tweet")
+	suite.broker.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestDeleteTweet_DeletesAttachmentBlobs_Positive() {
+	// the attachments of the tweet and of its replies
+	attachments := []entities.Attachment{
+		{ID: 1, TweetID: 1, BlobKey: "a.png", ThumbnailKey: "a_thumb.png"},
+		{ID: 2, TweetID: 2, BlobKey: "b.jpg", ThumbnailKey: "b_thumb.jpg"},
+	}
+	suite.repository.On("DeleteTweet", 1).Return(attachments, nil)
+	suite.blobStore.On("Delete", "a.png").Return(nil)
+	suite.blobStore.On("Delete", "a_thumb.png").Return(nil)
+	suite.blobStore.On("Delete", "b.jpg").Return(errors.New("disk error"))
+	suite.blobStore.On("Delete", "b_thumb.jpg").Return(nil)
+	suite.broker.On("Publish", mock.Anything).Return(nil)
+
+	err := suite.usecase.DeleteTweet(1)
+	suite.Nil(err, "a blob that can't be removed doesn't fail the delete")
+	suite.blobStore.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestDeleteTweet_NotFound_NotPublished_Negative() {
+	suite.repository.On("DeleteTweet", 1).Return(nil, repositories.ErrTweetNotFound)
+
+	err := suite.usecase.DeleteTweet(1)
+	suite.Equal(http.StatusNotFound, err.(*entities.AppError).StatusCode, "deleting a missing tweet is not found")
+	suite.broker.AssertNotCalled(suite.T(), "Publish", mock.Anything)
+	suite.blobStore.AssertNotCalled(suite.T(), "Delete", mock.Anything)
+}
+
+func (suite *tweetUsecaseSuite) TestDeleteTweet_PublishFails_Negative() {
+	suite.repository.On("DeleteTweet", 1).Return(nil, nil)
+	suite.broker.On("Publish", mock.Anything).Return(errors.New("notify failed"))
+
+	err := suite.usecase.DeleteTweet(1)
+	suite.Equal(http.StatusInternalServerError, err.(*entities.AppError).StatusCode, "a failed publish is reported")
+}
+
+func (suite *tweetUsecaseSuite) TestSearchTweetByText_NextPage_Positive() {
+	hits := []entities.SearchHit{
+		{Tweet: entities.Tweet{ID: 3, Text: "go"}, Rank: 0.9},
+		{Tweet: entities.Tweet{ID: 2, Text: "go"}, Rank: 0.5},
+		{Tweet: entities.Tweet{ID: 1, Text: "go"}, Rank: 0.1},
+	}
+
+	// one more than the page size is requested to know whether there is a next page
+	suite.repository.On("SearchTweetByText", entities.SearchQuery{
+		Mode: entities.SearchFullText,
+		Text: "go",
+		Username: "username",
+		Limit: 3,
+	}).Return(&hits, nil)
+
+	result, err := suite.usecase.SearchTweetByText(entities.SearchOptions{Text: " go ", Username: "username", Limit: 2})
+	suite.NoError(err)
+	suite.Len(result.Tweets, 2)
+	suite.NotEmpty(result.NextCursor)
+
+	mode, rank, id, err := utils.DecodeSearchCursor(result.NextCursor)
+	suite.NoError(err)
+	suite.Equal(entities.SearchFullText, mode)
+	suite.Equal(0.5, rank)
+	suite.Equal(2, id)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestSearchTweetByText_TrigramFallback_Positive() {
+	noHits := []entities.SearchHit(nil)
+	hits := []entities.SearchHit{
+		{Tweet: entities.Tweet{ID: 1, Text: "Learning golang"}, Rank: 0.6},
+	}
+
+	suite.repository.On("SearchTweetByText", mock.MatchedBy(func(query entities.SearchQuery) bool {
+		return query.Mode == entities.SearchFullText
+	})).Return(&noHits, nil)
+	suite.repository.On("SearchTweetByText", mock.MatchedBy(func(query entities.SearchQuery) bool {
+		return query.Mode == entities.SearchTrigram
+	})).Return(&hits, nil)
+
+	result, err := suite.usecase.SearchTweetByText(entities.SearchOptions{Text: "golan"})
+	suite.NoError(err)
+	suite.Len(result.Tweets, 1)
+	suite.Empty(result.NextCursor)
+	suite.Equal("Learning <mark>golan</mark>g", result.Tweets[0].Highlight)
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestSearchTweetByText_Cursor_Positive() {
+	cursor := utils.EncodeSearchCursor(entities.SearchTrigram, 0.5, 2)
+	noHits := []entities.SearchHit(nil)
+
+	suite.repository.On("SearchTweetByText", mock.MatchedBy(func(query entities.SearchQuery) bool {
+		return query.Mode == entities.SearchTrigram && *query.AfterRank == 0.5 && query.AfterID == 2
+	})).Return(&noHits, nil).Once()
+
+	result, err := suite.usecase.SearchTweetByText(entities.SearchOptions{Text: "golan", Cursor: cursor})
+	suite.NoError(err)
+	suite.Empty(result.Tweets, "no fallback when paginating")
+	suite.repository.AssertExpectations(suite.T())
+}
+
+func (suite *tweetUsecaseSuite) TestSearchTweetByText_InvalidOptions_Negative() {
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	_, err := suite.usecase.SearchTweetByText(entities.SearchOptions{Text: " "})
+	suite.Equal(err.(*entities.AppError).StatusCode, 400)
+
+	_, err = suite.usecase.SearchTweetByText(entities.SearchOptions{Text: "go", From: &from, To: &to})
+	suite.Equal(err.(*entities.AppError).StatusCode, 400)
+
+	_, err = suite.usecase.SearchTweetByText(entities.SearchOptions{Text: "go", Cursor: "garbage"})
+	suite.Equal(err.(*entities.AppError).StatusCode, 400)
+	suite.repository.AssertExpectations(suite.T())
+}
+
 func TestTweetUsecase(t *testing.T) {
 	suite.Run(t, new(tweetUsecaseSuite))
 }
\ No newline at end of file
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("cursor is invalid")
+
+// EncodeSearchCursor returns an opaque cursor pointing after the tweet with the given rank and id
+func EncodeSearchCursor(mode string, rank float64, id int) string {
+	value := mode + ":" + strconv.FormatFloat(rank, 'g', -1, 64) + ":" + strconv.Itoa(id)
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func DecodeSearchCursor(cursor string) (mode string, rank float64, id int, err error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, 0, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(value), ":")
+	if len(parts) != 3 {
+		return "", 0, 0, ErrInvalidCursor
+	}
+
+	rank, err = strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return "", 0, 0, ErrInvalidCursor
+	}
+
+	id, err = strconv.Atoi(parts[2])
+	if err != nil {
+		return "", 0, 0, ErrInvalidCursor
+	}
+
+	return parts[0], rank, id, nil
+}
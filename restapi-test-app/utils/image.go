@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"image"
+	"image/color"
+)
+
+// Thumbnail scales an image down so that neither side exceeds maxSize, keeping its aspect ratio.
+// Every pixel of the thumbnail is the average of the source pixels it covers.
+// Images already small enough are returned unchanged.
+func Thumbnail(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSize && height <= maxSize {
+		return src
+	}
+
+	thumbWidth, thumbHeight := maxSize, maxSize
+	if width > height {
+		thumbHeight = height * maxSize / width
+	} else {
+		thumbWidth = width * maxSize / height
+	}
+	if thumbWidth < 1 {
+		thumbWidth = 1
+	}
+	if thumbHeight < 1 {
+		thumbHeight = 1
+	}
+
+	dst := image.NewRGBA64(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		y0 := bounds.Min.Y + y*height/thumbHeight
+		y1 := bounds.Min.Y + (y+1)*height/thumbHeight
+
+		for x := 0; x < thumbWidth; x++ {
+			x0 := bounds.Min.X + x*width/thumbWidth
+			x1 := bounds.Min.X + (x+1)*width/thumbWidth
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / count),
+				G: uint16(g / count),
+				B: uint16(b / count),
+				A: uint16(a / count),
+			})
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/suite"
+	"image"
+	"image/color"
+	"testing"
+)
+
+type imageSuite struct {
+	suite.Suite
+}
+
+func (suite *imageSuite) TestThumbnail_KeepsAspectRatio_Positive() {
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			// left half black, right half white
+			if x >= 200 {
+				src.Set(x, y, color.White)
+			} else {
+				src.Set(x, y, color.Black)
+			}
+		}
+	}
+
+	thumbnail := Thumbnail(src, 100)
+	suite.Equal(image.Rect(0, 0, 100, 50), thumbnail.Bounds())
+
+	r, _, _, _ := thumbnail.At(10, 10).RGBA()
+	suite.Equal(uint32(0), r, "left side stays black")
+	r, _, _, _ = thumbnail.At(90, 10).RGBA()
+	suite.Equal(uint32(0xffff), r, "right side stays white")
+}
+
+func (suite *imageSuite) TestThumbnail_SmallImage_Positive() {
+	src := image.NewRGBA(image.Rect(0, 0, 50, 20))
+	suite.Equal(image.Image(src), Thumbnail(src, 100), "small images are not scaled")
+}
+
+func TestImage(t *testing.T) {
+	suite.Run(t, new(imageSuite))
+}
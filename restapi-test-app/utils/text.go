@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// a hashtag or mention has to start the text or follow a character that can not be part of a word,
+// so e-mail addresses and things like "a#b" are not picked up
+var (
+	hashtagPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#@])#([\p{L}\p{N}_]{1,139})`)
+	mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_#@])@([A-Za-z0-9_]{1,128})`)
+)
+
+// ExtractHashtags returns the distinct lowercased hashtags of a text without the leading '#'.
+// Tags made only of digits are ignored.
+func ExtractHashtags(text string) []string {
+	return extract(hashtagPattern, text, func(tag string) bool {
+		return strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsDigit(r) }) >= 0
+	})
+}
+
+// ExtractMentions returns the distinct lowercased usernames mentioned in a text without the leading '@'
+func ExtractMentions(text string) []string {
+	return extract(mentionPattern, text, func(string) bool { return true })
+}
+
+// NormalizeHashtag lowercases a tag and strips its leading '#', if any
+func NormalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+func extract(pattern *regexp.Regexp, text string, valid func(string) bool) []string {
+	result := []string{}
+	seen := map[string]bool{}
+
+	for _, match := range pattern.FindAllStringSubmatch(text, -1) {
+		value := strings.ToLower(match[1])
+		if seen[value] || !valid(value) {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+
+	return result
+}
+
+// snippetLength is the number of characters around a match kept in a highlight
+const snippetLength = 100
+
+// HighlightMatch returns an HTML escaped snippet of text around the first case-insensitive occurrence of term,
+// with the occurrence wrapped in <mark> tags. Without an occurrence the beginning of the text is returned.
+func HighlightMatch(text string, term string) string {
+	runes := []rune(text)
+	start, end := indexFold(runes, []rune(strings.TrimSpace(term)))
+
+	if start < 0 {
+		if len(runes) <= snippetLength {
+			return html.EscapeString(text)
+		}
+		return html.EscapeString(string(runes[:snippetLength])) + "…"
+	}
+
+	from := start - (snippetLength-(end-start))/2
+	if from < 0 {
+		from = 0
+	}
+	to := from + snippetLength
+	if to < end {
+		to = end
+	}
+	if to > len(runes) {
+		to = len(runes)
+	}
+
+	var builder strings.Builder
+	if from > 0 {
+		builder.WriteString("…")
+	}
+	builder.WriteString(html.EscapeString(string(runes[from:start])))
+	builder.WriteString("<mark>")
+	builder.WriteString(html.EscapeString(string(runes[start:end])))
+	builder.WriteString("</mark>")
+	builder.WriteString(html.EscapeString(string(runes[end:to])))
+	if to < len(runes) {
+		builder.WriteString("…")
+	}
+
+	return builder.String()
+}
+
+// indexFold returns the rune offsets of the first case-insensitive occurrence of term in text, or -1
+func indexFold(text []rune, term []rune) (int, int) {
+	if len(term) == 0 {
+		return -1, -1
+	}
+
+	for i := 0; i+len(term) <= len(text); i++ {
+		matched := true
+		for j, r := range term {
+			if unicode.ToLower(text[i+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i, i + len(term)
+		}
+	}
+
+	return -1, -1
+}
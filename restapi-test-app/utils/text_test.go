@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+type textSuite struct {
+	suite.Suite
+}
+
+func (suite *textSuite) TestExtractHashtags_Positive() {
+	tags := ExtractHashtags("#Go is fun, #golang #GO and #café. #2021 is not a tag but #2021_review is")
+	suite.Equal([]string{"go", "golang", "café", "2021_review"}, tags)
+}
+
+func (suite *textSuite) TestExtractHashtags_InsideWord_Negative() {
+	tags := ExtractHashtags("issue a#b, &#39; and ##double")
+	suite.Equal([]string{}, tags, "hashtags inside words or entities are ignored")
+}
+
+func (suite *textSuite) TestExtractMentions_Positive() {
+	mentions := ExtractMentions("@Alice and @bob_99: ping @alice again")
+	suite.Equal([]string{"alice", "bob_99"}, mentions)
+}
+
+func (suite *textSuite) TestExtractMentions_Email_Negative() {
+	mentions := ExtractMentions("mail me at someone@example.com")
+	suite.Equal([]string{}, mentions, "e-mail addresses are not mentions")
+}
+
+func (suite *textSuite) TestNormalizeHashtag_Positive() {
+	suite.Equal("golang", NormalizeHashtag(" #GoLang"))
+	suite.Equal("golang", NormalizeHashtag("golang"))
+}
+
+func (suite *textSuite) TestHighlightMatch_Positive() {
+	suite.Equal("I &lt;3 <mark>Gopher</mark>s", HighlightMatch("I <3 Gophers", "gopher"))
+}
+
+func (suite *textSuite) TestHighlightMatch_LongText_Positive() {
+	text := strings.Repeat("a", 200) + " needle " + strings.Repeat("b", 200)
+	highlight := HighlightMatch(text, "needle")
+	suite.Contains(highlight, "<mark>needle</mark>")
+	suite.True(strings.HasPrefix(highlight, "…"), "text before the snippet is cut")
+	suite.True(strings.HasSuffix(highlight, "…"), "text after the snippet is cut")
+}
+
+func (suite *textSuite) TestHighlightMatch_NoMatch_Negative() {
+	suite.Equal("gophers &amp; friends", HighlightMatch("gophers & friends", "gofer"))
+}
+
+func (suite *textSuite) TestSearchCursor_RoundTrip_Positive() {
+	cursor := EncodeSearchCursor("fulltext", 0.0607927, 42)
+	mode, rank, id, err := DecodeSearchCursor(cursor)
+	suite.NoError(err)
+	suite.Equal("fulltext", mode)
+	suite.Equal(0.0607927, rank)
+	suite.Equal(42, id)
+}
+
+func (suite *textSuite) TestSearchCursor_Invalid_Negative() {
+	_, _, _, err := DecodeSearchCursor("not a cursor")
+	suite.Equal(ErrInvalidCursor, err)
+}
+
+func TestText(t *testing.T) {
+	suite.Run(t, new(textSuite))
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+)
+
+// BootstrapAdmin -- Promote the user registered with ADMIN_EMAIL to admin while there is no admin yet,
+// so the first admin can assign roles to everyone else. Nothing happens when ADMIN_EMAIL is not set
+// or an admin exists, demoting that user later lasts across restarts.
+func BootstrapAdmin(db *sql.DB) {
+	email := os.Getenv("ADMIN_EMAIL")
+	if email == "" {
+		return
+	}
+
+	var hasAdmin bool
+	err := db.QueryRow(`
+		SELECT EXISTS (SELECT 1 FROM users u JOIN roles r ON r._id = u.role_id WHERE r.name = 'admin');
+	`).Scan(&hasAdmin)
+	if err != nil {
+		panic(err)
+	}
+	if hasAdmin {
+		return
+	}
+
+	result, err := db.Exec(`
+		UPDATE users SET role_id = (SELECT _id FROM roles WHERE name = 'admin')
+		WHERE email = $1;
+	`, email)
+	if err != nil {
+		panic(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		panic(err)
+	}
+	if affected == 0 {
+		fmt.Println("ADMIN_EMAIL is set but no such user is registered yet, restart after registering it")
+		return
+	}
+	fmt.Println("Promoted", email, "to admin")
+}
@@ -8,9 +8,6 @@ import (
 	_ "github.com/lib/pq"
 )
 
-// DB to use database
-var DB *sql.DB
-
 const (
 	host     = "localhost"
 	port     = "5432"
@@ -19,25 +16,24 @@ const (
 	dbname   = "postgres"
 )
 
-// InitDb Initializing database connection
-func InitDb() {
+// ConnectDB Initializing database connection
+func ConnectDB() *sql.DB {
 	config := dbConfig()
-	var err error
 	psqlInfo := fmt.Sprintf("host=%s port=%s user=%s "+
 		"password=%s dbname=%s sslmode=disable",
 		config[host], config[port],
 		config[user], config[password], config[dbname])
 
-	DB, err = sql.Open("postgres", psqlInfo)
+	db, err := sql.Open("postgres", psqlInfo)
 	if err != nil {
 		panic(err)
 	}
-	err = DB.Ping()
+	err = db.Ping()
 	if err != nil {
 		panic(err)
 	}
 	fmt.Println("Successfully connected!")
-
+	return db
 }
 
 func dbConfig() map[string]string {
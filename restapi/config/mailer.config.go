@@ -0,0 +1,31 @@
+package config
+
+import (
+	"golang-restapi/mailer"
+	"os"
+)
+
+// NewMailer Initializing the mailer delivering confirmation and password reset codes,
+// MAILER=smtp sends real emails, otherwise they are written to MAIL_OUTBOX_DIR
+func NewMailer() mailer.Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if os.Getenv("MAILER") == "smtp" {
+		return mailer.NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			from,
+		)
+	}
+
+	dir := os.Getenv("MAIL_OUTBOX_DIR")
+	if len(dir) == 0 {
+		dir = "outbox"
+	}
+	outbox, err := mailer.NewFileOutbox(dir, from)
+	if err != nil {
+		panic(err)
+	}
+	return outbox
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ConnectRedis Initializing the Redis connection at REDIS_ADDR
+func ConnectRedis() *redis.Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     os.Getenv("REDIS_ADDR"),
+		Password: "", // no password set
+		DB:       0,  // use default DB
+	})
+
+	err := rdb.Ping(context.Background()).Err()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println("Successfully connected to Redis!")
+	return rdb
+}
@@ -1,182 +1,181 @@
 package handler
 
 import (
-	"fmt"
+	"errors"
 	"golang-restapi/model"
 	"golang-restapi/repository"
+	"golang-restapi/usecase"
 	"golang-restapi/utils"
+	"ratelimit"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
+// AuthHandler -- Routes for registration, login and the verification code flows
+type AuthHandler interface {
+	Register(c *gin.Context)
+	Login(c *gin.Context)
+	ConfirmAccount(c *gin.Context)
+	ResendConfirmation(c *gin.Context)
+	RequestPassword(c *gin.Context)
+	ChangePassword(c *gin.Context)
+}
+
+type authHandler struct {
+	authUsecase usecase.AuthUsecase
+}
+
+// InitializeAuthHandler -- Create the auth handler
+func InitializeAuthHandler(authUsecase usecase.AuthUsecase) AuthHandler {
+	return &authHandler{
+		authUsecase: authUsecase,
+	}
+}
+
 // Register handler function
-func Register(c *gin.Context) {
-	var user model.User
-	var err error
-	c.BindJSON(&user)
-	fmt.Println("Register user", len(user.Email), len(user.Password))
-	if len(user.Email) == 0 || len(user.Password) == 0 {
+func (h *authHandler) Register(c *gin.Context) {
+	var data model.RegisterData
+	if !bindJSON(c, &data) {
+		return
+	}
+	if len(data.Email) == 0 || len(data.Password) == 0 {
 		utils.ResponseBadRequest(c, "Please provide email and password")
 		return
 	}
-	user.Password, err = utils.HashPassword(user.Password)
+	user, err := h.authUsecase.Register(data.Email, data.Password)
+	if err == repository.ErrEmailTaken {
+		utils.ResponseBadRequest(c, "Email has been used")
+		return
+	}
 	if err != nil {
 		utils.ResponseServerError(c)
 		return
 	}
-	user.UUID = uuid.New().String()
-	ok := repository.CreateUser(&user)
-	if ok {
-		utils.ResponseSuccess(c, "Register success", gin.H{
-			"email":    user.Email,
-			"password": user.Password,
-			"uuid":     user.UUID,
-		})
-	} else {
-		utils.ResponseBadRequest(c, "Email has been used")
-	}
+	utils.ResponseSuccess(c, "Register success, check your email for the confirmation code", gin.H{
+		"user": model.NewUserResponse(user),
+	})
 }
 
 // Login handler function
-func Login(c *gin.Context) {
+func (h *authHandler) Login(c *gin.Context) {
 	var data model.LoginData
-	var user model.User
-	var err error
-	var token string
-	c.BindJSON(&data)
+	if !bindJSON(c, &data) {
+		return
+	}
 	if len(data.Email) == 0 || len(data.Password) == 0 {
 		utils.ResponseBadRequest(c, "Please provide email and password")
 		return
 	}
-	user, err = repository.GetUserByEmail(data.Email)
-	if err != nil {
-		utils.ResponseServerError(c)
+	user, token, err := h.authUsecase.Login(c.Request.Context(), c.ClientIP(), data.Email, data.Password)
+	var limitedErr *ratelimit.LimitedError
+	if errors.As(err, &limitedErr) {
+		utils.ResponseTooManyRequests(c, limitedErr.Reason, limitedErr.RetryAfter)
 		return
 	}
-	if !user.Confirmed {
+	if err == usecase.ErrAccountNotConfirmed {
 		utils.ResponseBadRequest(c, "Please confirm your account")
 		return
 	}
-
-	if !utils.CheckPasswordHash(data.Password, user.Password) {
+	if err == usecase.ErrInvalidCredentials {
 		utils.ResponseBadRequest(c, "Invalid username or password")
 		return
 	}
-	token, err = utils.CreateToken(user.ID)
 	if err != nil {
 		utils.ResponseServerError(c)
 		return
 	}
 	utils.ResponseSuccess(c, "Login success", gin.H{
-		"user": gin.H{
-			"_id":      user.ID,
-			"username": user.Email,
-			"password": user.Password,
-		},
+		"user":  user,
 		"token": token,
 	})
-	return
 }
 
 // ConfirmAccount ...
-func ConfirmAccount(c *gin.Context) {
+func (h *authHandler) ConfirmAccount(c *gin.Context) {
 	var data model.ConfirmData
-	var user model.User
-	var err error
-	var ok bool
-	c.BindJSON(&data)
-	if len(data.Email) == 0 || len(data.Password) == 0 || len(data.UUID) == 0 {
-		utils.ResponseBadRequest(c, "Please provide email, password, and confirmation password")
-	}
-	user, err = repository.GetUserByEmail(data.Email)
-	if err != nil {
-		utils.ResponseServerError(c)
+	if !bindJSON(c, &data) {
+		return
+	}
+	if len(data.Email) == 0 || len(data.Password) == 0 || len(data.Code) == 0 {
+		utils.ResponseBadRequest(c, "Please provide email, password, and confirmation code")
 		return
 	}
-	if user.UUID != data.UUID {
+	err := h.authUsecase.ConfirmAccount(data.Email, data.Code)
+	if err == usecase.ErrWrongCode {
 		utils.ResponseBadRequest(c, "Wrong confirmation code")
 		return
 	}
-	ok, err = repository.ConfirmAccount(user.ID)
-	if !ok || err != nil {
+	if err != nil {
 		utils.ResponseServerError(c)
 		return
 	}
 	utils.ResponseSuccess(c, "Success to confirm account", gin.H{
-		"email": user.Email,
+		"email": data.Email,
 	})
 }
 
-// RequestPassword ...
-func RequestPassword(c *gin.Context) {
-	var data model.RequestPasswordData
-	var user model.User
-	var err error
-	var newUUID string
-	var ok bool
-	c.BindJSON(&data)
+// ResendConfirmation ...
+func (h *authHandler) ResendConfirmation(c *gin.Context) {
+	var data model.ResendConfirmationData
+	if !bindJSON(c, &data) {
+		return
+	}
 	if len(data.Email) == 0 {
 		utils.ResponseBadRequest(c, "Please provide email")
 		return
 	}
-	user, err = repository.GetUserByEmail(data.Email)
+	err := h.authUsecase.ResendConfirmation(data.Email)
 	if err != nil {
 		utils.ResponseServerError(c)
 		return
 	}
-	if user == (model.User{}) {
-		utils.ResponseNotFound(c, "User not found")
+	// Respond the same way whether the user exists or not, so emails can't be enumerated
+	utils.ResponseSuccess(c, "If the email is registered and not confirmed yet, a confirmation code has been sent", gin.H{
+		"email": data.Email,
+	})
+}
+
+// RequestPassword ...
+func (h *authHandler) RequestPassword(c *gin.Context) {
+	var data model.RequestPasswordData
+	if !bindJSON(c, &data) {
+		return
+	}
+	if len(data.Email) == 0 {
+		utils.ResponseBadRequest(c, "Please provide email")
 		return
 	}
-	newUUID = uuid.New().String()
-	ok, err = repository.NewUUID(newUUID, user.Email)
-	if !ok || err != nil {
+	err := h.authUsecase.RequestPassword(data.Email)
+	if err != nil {
 		utils.ResponseServerError(c)
 		return
 	}
-	utils.ResponseSuccess(c, "Success to get confirmation code", gin.H{
-		"email":            user.Email,
-		"confirmationCode": newUUID,
+	// Respond the same way whether the user exists or not, so emails can't be enumerated
+	utils.ResponseSuccess(c, "If the email is registered, a confirmation code has been sent", gin.H{
+		"email": data.Email,
 	})
 }
 
 // ChangePassword ...
-func ChangePassword(c *gin.Context) {
+func (h *authHandler) ChangePassword(c *gin.Context) {
 	var data model.ForgotPasswordData
-	var user model.User
-	var err error
-	var newPassword string
-	var ok bool
-	c.BindJSON(&data)
-	if len(data.Email) == 0 || len(data.NewPassword) == 0 || len(data.UUID) == 0 {
-		utils.ResponseBadRequest(c, "Please provide email, new password and confirmation code")
-		return
-	}
-	user, err = repository.GetUserByEmail(data.Email)
-	if err != nil {
-		utils.ResponseServerError(c)
+	if !bindJSON(c, &data) {
 		return
 	}
-	if user == (model.User{}) {
-		utils.ResponseNotFound(c, "User not found")
+	if len(data.Email) == 0 || len(data.NewPassword) == 0 || len(data.Code) == 0 {
+		utils.ResponseBadRequest(c, "Please provide email, new password and confirmation code")
 		return
 	}
-	if user.UUID != data.UUID {
+	err := h.authUsecase.ChangePassword(data.Email, data.Code, data.NewPassword)
+	if err == usecase.ErrWrongCode {
 		utils.ResponseBadRequest(c, "Wrong confirmation code")
 		return
 	}
-	newPassword, err = utils.HashPassword(data.NewPassword)
 	if err != nil {
 		utils.ResponseServerError(c)
 		return
 	}
-	ok, err = repository.ChangePassword(data.Email, newPassword)
-	if !ok || err != nil {
-		utils.ResponseServerError(c)
-		return
-	}
 	utils.ResponseSuccess(c, "Success to change password", gin.H{
-		"email": user.Email,
+		"email": data.Email,
 	})
 }
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"golang-restapi/mocks"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/usecase"
+	"net/http"
+	"ratelimit"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type authHandlerSuite struct {
+	suite.Suite
+	usecase *mocks.AuthUsecase
+	router  *gin.Engine
+}
+
+func (suite *authHandlerSuite) SetupTest() {
+	suite.usecase = new(mocks.AuthUsecase)
+	handler := InitializeAuthHandler(suite.usecase)
+
+	router := gin.New()
+	router.POST("/auth/register", handler.Register)
+	router.POST("/auth/login", handler.Login)
+	router.POST("/auth/confirm", handler.ConfirmAccount)
+	router.POST("/auth/confirm/resend", handler.ResendConfirmation)
+	router.POST("/auth/forgot-password/confirm", handler.ChangePassword)
+	suite.router = router
+}
+
+func (suite *authHandlerSuite) TestRegister() {
+	cases := []struct {
+		name        string
+		body        interface{}
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "registered", body: model.RegisterData{Email: "user@example.com", Password: "password"}, wantStatus: http.StatusOK},
+		{name: "missing password", body: model.RegisterData{Email: "user@example.com"}, wantStatus: http.StatusBadRequest, wantMessage: "Please provide email and password"},
+		{name: "invalid json", body: "{", wantStatus: http.StatusBadRequest, wantMessage: "Invalid request body"},
+		{name: "email taken", body: model.RegisterData{Email: "user@example.com", Password: "password"}, err: repository.ErrEmailTaken, wantStatus: http.StatusBadRequest, wantMessage: "Email has been used"},
+		{name: "server error", body: model.RegisterData{Email: "user@example.com", Password: "password"}, err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("Register", "user@example.com", "password").Return(model.User{ID: 1, Email: "user@example.com", Password: "hash"}, c.err)
+
+			response := serve(suite.router, http.MethodPost, "/auth/register", c.body)
+
+			suite.Equal(c.wantStatus, response.Code)
+			if len(c.wantMessage) > 0 {
+				suite.Equal(c.wantMessage, message(response))
+			}
+			suite.NotContains(response.Body.String(), "hash", "password hash is never sent")
+		})
+	}
+}
+
+func (suite *authHandlerSuite) TestLogin() {
+	cases := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+		retryAfter  string
+	}{
+		{name: "success", wantStatus: http.StatusOK, wantMessage: "Login success"},
+		{name: "not confirmed", err: usecase.ErrAccountNotConfirmed, wantStatus: http.StatusBadRequest, wantMessage: "Please confirm your account"},
+		{name: "wrong password", err: usecase.ErrInvalidCredentials, wantStatus: http.StatusBadRequest, wantMessage: "Invalid username or password"},
+		{name: "rate limited", err: &ratelimit.LimitedError{Reason: "Too many login attempts", RetryAfter: 1500 * time.Millisecond}, wantStatus: http.StatusTooManyRequests, wantMessage: "Too many login attempts", retryAfter: "2"},
+		{name: "server error", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("Login", mock.Anything, mock.AnythingOfType("string"), "user@example.com", "password").Return(model.UserResponse{ID: 1}, "token", c.err)
+
+			response := serve(suite.router, http.MethodPost, "/auth/login", model.LoginData{Email: "user@example.com", Password: "password"})
+
+			suite.Equal(c.wantStatus, response.Code)
+			if len(c.wantMessage) > 0 {
+				suite.Equal(c.wantMessage, message(response))
+			}
+			suite.Equal(c.retryAfter, response.Header().Get("Retry-After"))
+		})
+	}
+}
+
+func (suite *authHandlerSuite) TestConfirmAccount() {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "confirmed", wantStatus: http.StatusOK},
+		{name: "wrong code", err: usecase.ErrWrongCode, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("ConfirmAccount", "user@example.com", "code").Return(c.err)
+
+			response := serve(suite.router, http.MethodPost, "/auth/confirm", model.ConfirmData{Email: "user@example.com", Password: "password", Code: "code"})
+
+			suite.Equal(c.wantStatus, response.Code)
+		})
+	}
+}
+
+func (suite *authHandlerSuite) TestResendConfirmation() {
+	cases := []struct {
+		name       string
+		body       interface{}
+		err        error
+		wantStatus int
+	}{
+		{name: "sent", body: model.ResendConfirmationData{Email: "user@example.com"}, wantStatus: http.StatusOK},
+		{name: "missing email", body: model.ResendConfirmationData{}, wantStatus: http.StatusBadRequest},
+		{name: "server error", body: model.ResendConfirmationData{Email: "user@example.com"}, err: errors.New("smtp down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("ResendConfirmation", "user@example.com").Return(c.err)
+
+			response := serve(suite.router, http.MethodPost, "/auth/confirm/resend", c.body)
+
+			suite.Equal(c.wantStatus, response.Code)
+		})
+	}
+}
+
+func (suite *authHandlerSuite) TestChangePassword_MissingCode_Negative() {
+	response := serve(suite.router, http.MethodPost, "/auth/forgot-password/confirm", model.ForgotPasswordData{Email: "user@example.com", NewPassword: "password"})
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.usecase.AssertNotCalled(suite.T(), "ChangePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler(t *testing.T) {
+	suite.Run(t, new(authHandlerSuite))
+}
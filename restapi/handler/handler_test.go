@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = ioutil.Discard
+}
+
+// asUser -- Stand-in for the auth middleware, setting what a valid token would
+func asUser(userID uint64, orgID uint64, permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("userID", float64(userID))
+		c.Set("orgID", float64(orgID))
+		c.Set("permissions", permissions)
+		c.Next()
+	}
+}
+
+// serve -- Run one request through the router, body is encoded as JSON unless it's a string
+func serve(router http.Handler, method, path string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	switch b := body.(type) {
+	case nil:
+	case string:
+		payload = []byte(b)
+	default:
+		payload, _ = json.Marshal(b)
+	}
+	request := httptest.NewRequest(method, path, bytes.NewReader(payload))
+	request.Header.Set("Content-Type", "application/json")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+// message -- The message field of a JSON response
+func message(recorder *httptest.ResponseRecorder) string {
+	var body struct {
+		Message string `json:"message"`
+	}
+	json.Unmarshal(recorder.Body.Bytes(), &body)
+	return body.Message
+}
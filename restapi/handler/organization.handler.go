@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/usecase"
+	"golang-restapi/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler -- Routes for organizations, their members and invites
+type OrganizationHandler interface {
+	CreateOrganization(c *gin.Context)
+	GetOrganizations(c *gin.Context)
+	GetMembers(c *gin.Context)
+	Invite(c *gin.Context)
+	AcceptInvite(c *gin.Context)
+	SwitchOrganization(c *gin.Context)
+}
+
+type organizationHandler struct {
+	organizationUsecase usecase.OrganizationUsecase
+}
+
+// InitializeOrganizationHandler -- Create the organization handler
+func InitializeOrganizationHandler(organizationUsecase usecase.OrganizationUsecase) OrganizationHandler {
+	return &organizationHandler{
+		organizationUsecase: organizationUsecase,
+	}
+}
+
+// CreateOrganization -- Create an organization owned by the user, the returned token is scoped to it
+func (h *organizationHandler) CreateOrganization(c *gin.Context) {
+	var data model.CreateOrganizationData
+	if !bindJSON(c, &data) {
+		return
+	}
+	if len(data.Name) == 0 {
+		utils.ResponseBadRequest(c, "Please provide name")
+		return
+	}
+	org, token, err := h.organizationUsecase.CreateOrganization(actorFromContext(c), data.Name)
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to create organization", gin.H{
+		"organization": org,
+		"token":        token,
+	})
+}
+
+// GetOrganizations -- Get the organizations of the user
+func (h *organizationHandler) GetOrganizations(c *gin.Context) {
+	actor := actorFromContext(c)
+	orgs, err := h.organizationUsecase.GetOrganizations(actor)
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to get organizations", gin.H{
+		"organizations": orgs,
+		"current":       actor.OrgID,
+	})
+}
+
+// GetMembers -- Get the members of the current organization
+func (h *organizationHandler) GetMembers(c *gin.Context) {
+	members, err := h.organizationUsecase.GetMembers(actorFromContext(c))
+	if err == usecase.ErrNoOrganization {
+		responseNoOrganization(c)
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to get members", gin.H{
+		"members": members,
+	})
+}
+
+// Invite -- Email an invite to join the current organization
+func (h *organizationHandler) Invite(c *gin.Context) {
+	var data model.InviteData
+	if !bindJSON(c, &data) {
+		return
+	}
+	if len(data.Email) == 0 {
+		utils.ResponseBadRequest(c, "Please provide email")
+		return
+	}
+	err := h.organizationUsecase.Invite(actorFromContext(c), data.Email)
+	if err == usecase.ErrNoOrganization {
+		responseNoOrganization(c)
+		return
+	}
+	if err == usecase.ErrNotOrgOwner {
+		utils.ResponseForbidden(c, "Only owners can invite to the organization")
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to send invite", gin.H{
+		"email": data.Email,
+	})
+}
+
+// AcceptInvite -- Join the organization of an invite, the returned token is scoped to it
+func (h *organizationHandler) AcceptInvite(c *gin.Context) {
+	var data model.AcceptInviteData
+	if !bindJSON(c, &data) {
+		return
+	}
+	if len(data.Code) == 0 {
+		utils.ResponseBadRequest(c, "Please provide code")
+		return
+	}
+	orgID, token, err := h.organizationUsecase.AcceptInvite(actorFromContext(c), data.Code)
+	if err == repository.ErrInviteNotFound {
+		utils.ResponseBadRequest(c, "Wrong invite code")
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to join organization", gin.H{
+		"orgId": orgID,
+		"token": token,
+	})
+}
+
+// SwitchOrganization -- Get a token scoped to another organization of the user
+func (h *organizationHandler) SwitchOrganization(c *gin.Context) {
+	orgID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ResponseBadRequest(c, "Invalid organization id")
+		return
+	}
+	token, err := h.organizationUsecase.SwitchOrganization(actorFromContext(c), orgID)
+	if err == repository.ErrNotMember {
+		utils.ResponseNotFound(c, "Organization not found")
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to switch organization", gin.H{
+		"orgId": orgID,
+		"token": token,
+	})
+}
+
+func responseNoOrganization(c *gin.Context) {
+	utils.ResponseForbidden(c, "Please create or join an organization first")
+}
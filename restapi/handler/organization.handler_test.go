@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"golang-restapi/mocks"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/usecase"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type organizationHandlerSuite struct {
+	suite.Suite
+	usecase *mocks.OrganizationUsecase
+	router  *gin.Engine
+}
+
+var memberActor = model.Actor{UserID: 1, OrgID: 7, Permissions: []string{model.PermissionOrgReadOwn, model.PermissionOrgWriteOwn}}
+
+func (suite *organizationHandlerSuite) SetupTest() {
+	suite.usecase = new(mocks.OrganizationUsecase)
+	handler := InitializeOrganizationHandler(suite.usecase)
+
+	router := gin.New()
+	router.Use(asUser(memberActor.UserID, memberActor.OrgID, memberActor.Permissions...))
+	router.POST("/org/", handler.CreateOrganization)
+	router.POST("/org/invites", handler.Invite)
+	router.POST("/org/invites/accept", handler.AcceptInvite)
+	router.POST("/org/switch/:id", handler.SwitchOrganization)
+	suite.router = router
+}
+
+func (suite *organizationHandlerSuite) TestCreateOrganization_MissingName_Negative() {
+	response := serve(suite.router, http.MethodPost, "/org/", model.CreateOrganizationData{})
+
+	suite.Equal(http.StatusBadRequest, response.Code)
+	suite.Equal("Please provide name", message(response))
+}
+
+func (suite *organizationHandlerSuite) TestInvite() {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "sent", wantStatus: http.StatusOK},
+		{name: "not the owner", err: usecase.ErrNotOrgOwner, wantStatus: http.StatusForbidden},
+		{name: "no organization", err: usecase.ErrNoOrganization, wantStatus: http.StatusForbidden},
+		{name: "server error", err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("Invite", memberActor, "agent@example.com").Return(c.err)
+
+			response := serve(suite.router, http.MethodPost, "/org/invites", model.InviteData{Email: "agent@example.com"})
+
+			suite.Equal(c.wantStatus, response.Code)
+		})
+	}
+}
+
+func (suite *organizationHandlerSuite) TestAcceptInvite() {
+	cases := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "joined", wantStatus: http.StatusOK},
+		{name: "wrong code", err: repository.ErrInviteNotFound, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("AcceptInvite", memberActor, "code").Return(uint64(9), "token", c.err)
+
+			response := serve(suite.router, http.MethodPost, "/org/invites/accept", model.AcceptInviteData{Code: "code"})
+
+			suite.Equal(c.wantStatus, response.Code)
+		})
+	}
+}
+
+func (suite *organizationHandlerSuite) TestSwitchOrganization() {
+	cases := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "member", path: "/org/switch/9", wantStatus: http.StatusOK},
+		{name: "not a member", path: "/org/switch/9", err: repository.ErrNotMember, wantStatus: http.StatusNotFound},
+		{name: "invalid id", path: "/org/switch/nine", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("SwitchOrganization", memberActor, uint64(9)).Return("token", c.err)
+
+			response := serve(suite.router, http.MethodPost, c.path, nil)
+
+			suite.Equal(c.wantStatus, response.Code)
+		})
+	}
+}
+
+func TestOrganizationHandler(t *testing.T) {
+	suite.Run(t, new(organizationHandlerSuite))
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"golang-restapi/model"
+	"golang-restapi/utils"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit     = 20
+	maxPageLimit         = 100
+	defaultUpcomingHours = 24
+	maxUpcomingHours     = 24 * 30
+	// csvBatchSize -- Services read per query while streaming a CSV export
+	csvBatchSize = 500
+)
+
+// page -- Pagination read from the page and limit query parameters, page starts at 1
+type page struct {
+	Page  int
+	Limit int
+}
+
+// query -- The limit and offset to ask the repository for, one more than the page
+// size so the handler can tell whether there is a next page
+func (p page) query() (int, int) {
+	return p.Limit + 1, (p.Page - 1) * p.Limit
+}
+
+func parsePage(c *gin.Context) (page, error) {
+	number, err := queryInt(c, "page", 1)
+	if err != nil || number < 1 {
+		return page{}, errors.New("page must be a positive number")
+	}
+	limit, err := queryInt(c, "limit", defaultPageLimit)
+	if err != nil || limit < 1 || limit > maxPageLimit {
+		return page{}, fmt.Errorf("limit must be between 1 and %d", maxPageLimit)
+	}
+	return page{Page: number, Limit: limit}, nil
+}
+
+// queryInt -- Read an integer query parameter, falling back when it is missing
+func queryInt(c *gin.Context, key string, fallback int) (int, error) {
+	value := c.Query(key)
+	if len(value) == 0 {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// queryTime -- Read an RFC3339 query parameter, nil when it is missing
+func queryTime(c *gin.Context, key string) (*time.Time, error) {
+	value := c.Query(key)
+	if len(value) == 0 {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be an RFC3339 timestamp", key)
+	}
+	return &t, nil
+}
+
+// bindJSON -- Decode the body into data, responding with bad request when it isn't valid JSON
+func bindJSON(c *gin.Context, data interface{}) bool {
+	err := c.ShouldBindJSON(data)
+	if err != nil {
+		utils.ResponseBadRequest(c, "Invalid request body")
+		return false
+	}
+	return true
+}
+
+// actorFromContext -- The user, organization and permissions set by the auth middleware
+func actorFromContext(c *gin.Context) model.Actor {
+	return model.Actor{
+		UserID:      uint64(c.MustGet("userID").(float64)),
+		OrgID:       uint64(c.GetFloat64("orgID")),
+		Permissions: c.GetStringSlice("permissions"),
+	}
+}
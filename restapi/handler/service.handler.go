@@ -1,40 +1,310 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"golang-restapi/model"
 	"golang-restapi/repository"
-	"net/http"
+	"golang-restapi/usecase"
+	"golang-restapi/utils"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// ServiceHandler -- Routes for service requests and the vessel schedule
+type ServiceHandler interface {
+	CreateServiceRequest(c *gin.Context)
+	GetServices(c *gin.Context)
+	GetAllServices(c *gin.Context)
+	GetUpcomingArrivals(c *gin.Context)
+	GetUpcomingDepartures(c *gin.Context)
+	GetServiceByID(c *gin.Context)
+	UpdateServiceStatus(c *gin.Context)
+}
+
+type serviceHandler struct {
+	serviceUsecase usecase.ServiceUsecase
+}
+
+// InitializeServiceHandler -- Create the service handler
+func InitializeServiceHandler(serviceUsecase usecase.ServiceUsecase) ServiceHandler {
+	return &serviceHandler{
+		serviceUsecase: serviceUsecase,
+	}
+}
+
 // CreateServiceRequest -- create service request
-func CreateServiceRequest(c *gin.Context) {
-	userID := uint64(c.MustGet("userID").(float64))
-	var serviceRequest model.Service
-	c.Bind(&serviceRequest)
-	repository.CreateServiceRequest(&serviceRequest, userID)
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Success to create service request",
-		"data": gin.H{
-			"requestID":   serviceRequest.RequestID,
-			"status":      serviceRequest.Status,
-			"vesselName":  serviceRequest.VesselName,
-			"serviceType": serviceRequest.ServiceType,
-			"dataAgent":   serviceRequest.DataAgent,
-			"cargo":       serviceRequest.Cargo,
-			"etd":         serviceRequest.ETD,
-			"eta":         serviceRequest.ETA,
-		},
+func (h *serviceHandler) CreateServiceRequest(c *gin.Context) {
+	var data model.CreateServiceData
+	err := c.ShouldBindJSON(&data)
+	if err != nil {
+		utils.ResponseBadRequest(c, "Invalid request body, etd and eta must be RFC3339 timestamps")
+		return
+	}
+	if len(data.VesselName) == 0 || len(data.ServiceType) == 0 || len(data.DataAgent) == 0 || len(data.Cargo) == 0 {
+		utils.ResponseBadRequest(c, "Please provide vesselName, serviceType, dataAgent, cargo, etd and eta")
+		return
+	}
+	service, err := h.serviceUsecase.CreateServiceRequest(data, actorFromContext(c))
+	if err == usecase.ErrNoOrganization {
+		responseNoOrganization(c)
+		return
+	}
+	if err == model.ErrInvalidSchedule {
+		utils.ResponseBadRequest(c, "Please provide etd and eta, eta must be after etd")
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to create service request", gin.H{
+		"_id":         service.ID,
+		"requestID":   service.RequestID,
+		"status":      service.Status,
+		"vesselName":  service.VesselName,
+		"serviceType": service.ServiceType,
+		"dataAgent":   service.DataAgent,
+		"cargo":       service.Cargo,
+		"etd":         service.ETD,
+		"eta":         service.ETA,
 	})
 }
 
-// GetServices -- Get all services
-func GetServices(c *gin.Context) {
-	userID := uint64(c.MustGet("userID").(float64))
-	var services []model.Service = repository.GetServices(userID)
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Success to get all services",
-		"data":    services,
+// GetServices -- Get the services of the user, filtered by the query
+func (h *serviceHandler) GetServices(c *gin.Context) {
+	userID := actorFromContext(c).UserID
+	filter, err := parseServiceFilter(c)
+	if err != nil {
+		utils.ResponseBadRequest(c, err.Error())
+		return
+	}
+	filter.UserID = &userID
+	h.findServices(c, filter)
+}
+
+// GetAllServices -- Get services of every user, filtered by the query
+func (h *serviceHandler) GetAllServices(c *gin.Context) {
+	filter, err := parseServiceFilter(c)
+	if err != nil {
+		utils.ResponseBadRequest(c, err.Error())
+		return
+	}
+	h.findServices(c, filter)
+}
+
+func (h *serviceHandler) findServices(c *gin.Context, filter model.ServiceFilter) {
+	actor := actorFromContext(c)
+	if c.Query("format") == "csv" {
+		streamServicesCSV(c, "services.csv", func(limit int, offset int) ([]model.Service, error) {
+			filter.Limit, filter.Offset = limit, offset
+			return h.serviceUsecase.FindServices(filter, actor)
+		})
+		return
+	}
+	p, err := parsePage(c)
+	if err != nil {
+		utils.ResponseBadRequest(c, err.Error())
+		return
+	}
+	filter.Limit, filter.Offset = p.query()
+	services, err := h.serviceUsecase.FindServices(filter, actor)
+	if err != nil {
+		responseServicesError(c, err)
+		return
+	}
+	respondServices(c, "Success to get all services", services, p)
+}
+
+// GetUpcomingArrivals -- Get the services arriving within the next hours
+func (h *serviceHandler) GetUpcomingArrivals(c *gin.Context) {
+	h.getUpcomingServices(c, model.ScheduleArrivals)
+}
+
+// GetUpcomingDepartures -- Get the services departing within the next hours
+func (h *serviceHandler) GetUpcomingDepartures(c *gin.Context) {
+	h.getUpcomingServices(c, model.ScheduleDepartures)
+}
+
+// getUpcomingServices -- List active services for the event within the next `hours` hours (24 by default).
+// Users without service:read:any only see their own services.
+func (h *serviceHandler) getUpcomingServices(c *gin.Context, event string) {
+	hours, err := queryInt(c, "hours", defaultUpcomingHours)
+	if err != nil || hours < 1 || hours > maxUpcomingHours {
+		utils.ResponseBadRequest(c, fmt.Sprintf("hours must be between 1 and %d", maxUpcomingHours))
+		return
+	}
+	now := time.Now()
+	filter := model.UpcomingFilter{
+		Event: event,
+		Since: now,
+		Until: now.Add(time.Duration(hours) * time.Hour),
+	}
+	actor := actorFromContext(c)
+	if !actor.Can(model.PermissionServiceReadAny) {
+		filter.UserID = &actor.UserID
+	}
+	if c.Query("format") == "csv" {
+		streamServicesCSV(c, "upcoming-"+event+".csv", func(limit int, offset int) ([]model.Service, error) {
+			filter.Limit, filter.Offset = limit, offset
+			return h.serviceUsecase.GetUpcomingServices(filter, actor)
+		})
+		return
+	}
+	p, err := parsePage(c)
+	if err != nil {
+		utils.ResponseBadRequest(c, err.Error())
+		return
+	}
+	filter.Limit, filter.Offset = p.query()
+	services, err := h.serviceUsecase.GetUpcomingServices(filter, actor)
+	if err != nil {
+		responseServicesError(c, err)
+		return
+	}
+	respondServices(c, "Success to get upcoming "+event, services, p)
+}
+
+// GetServiceByID -- Get a service request with its status history
+func (h *serviceHandler) GetServiceByID(c *gin.Context) {
+	// GET /service/all predates /admin/services, gin can't route it next to /service/:id
+	if c.Param("id") == "all" {
+		if !actorFromContext(c).Can(model.PermissionServiceReadAny) {
+			utils.ResponseForbidden(c, "You don't have permission to access this resource")
+			return
+		}
+		h.GetAllServices(c)
+		return
+	}
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ResponseBadRequest(c, "Invalid service id")
+		return
+	}
+	service, history, err := h.serviceUsecase.GetService(serviceID, actorFromContext(c))
+	if err == usecase.ErrNoOrganization {
+		responseNoOrganization(c)
+		return
+	}
+	if err == repository.ErrServiceNotFound {
+		utils.ResponseNotFound(c, "Service request not found")
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to get service request", gin.H{
+		"service": service,
+		"history": history,
 	})
 }
+
+// UpdateServiceStatus -- Move a service request to another status
+func (h *serviceHandler) UpdateServiceStatus(c *gin.Context) {
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ResponseBadRequest(c, "Invalid service id")
+		return
+	}
+	var data model.UpdateServiceStatusData
+	if !bindJSON(c, &data) {
+		return
+	}
+	service, err := h.serviceUsecase.UpdateServiceStatus(serviceID, data.Status, data.Note, actorFromContext(c))
+	switch err {
+	case nil:
+		utils.ResponseSuccess(c, "Success to update service request status", gin.H{
+			"service": service,
+		})
+	case usecase.ErrNoOrganization:
+		responseNoOrganization(c)
+	case usecase.ErrInvalidStatus:
+		utils.ResponseBadRequest(c, "Please provide a valid status")
+	case repository.ErrServiceNotFound:
+		utils.ResponseNotFound(c, "Service request not found")
+	case usecase.ErrOnlyCancel:
+		utils.ResponseForbidden(c, "You can only cancel your own service requests")
+	case repository.ErrInvalidTransition:
+		utils.ResponseConflict(c, "Service request can't move from its current status to "+data.Status)
+	default:
+		utils.ResponseServerError(c)
+	}
+}
+
+// respondServices -- Send a page of services as JSON.
+// data stays the list of services, the page is described by the X-Page, X-Limit and X-Has-More headers.
+// The repository was asked for one service more than the page size to know if there is a next page.
+func respondServices(c *gin.Context, message string, services []model.Service, p page) {
+	hasMore := len(services) > p.Limit
+	if hasMore {
+		services = services[:p.Limit]
+	}
+	c.Header("X-Page", strconv.Itoa(p.Page))
+	c.Header("X-Limit", strconv.Itoa(p.Limit))
+	c.Header("X-Has-More", strconv.FormatBool(hasMore))
+	if services == nil {
+		services = []model.Service{}
+	}
+	utils.ResponseSuccess(c, message, services)
+}
+
+// streamServicesCSV -- Send every service matching the query as CSV, ignoring page and limit.
+// find is called with growing offsets, csvBatchSize services at a time, until a batch comes back short.
+func streamServicesCSV(c *gin.Context, filename string, find func(limit int, offset int) ([]model.Service, error)) {
+	var writer *utils.CSVWriter
+	for offset := 0; ; offset += csvBatchSize {
+		services, err := find(csvBatchSize, offset)
+		if err != nil && writer == nil {
+			responseServicesError(c, err)
+			return
+		}
+		if err != nil {
+			// the status is already sent, the file ends at the last complete batch
+			c.Error(err)
+			return
+		}
+		if writer == nil {
+			writer = utils.ResponseCSV(c, filename, model.ServiceCSVHeader)
+		}
+		for _, service := range services {
+			writer.Write(service.CSVRecord())
+		}
+		if err := writer.Flush(); err != nil || len(services) < csvBatchSize {
+			return
+		}
+	}
+}
+
+// responseServicesError -- Send the error of listing services
+func responseServicesError(c *gin.Context, err error) {
+	if err == usecase.ErrNoOrganization {
+		responseNoOrganization(c)
+		return
+	}
+	utils.ResponseServerError(c)
+}
+
+// parseServiceFilter -- Read vesselName, serviceType, cargo, from and to from the query
+func parseServiceFilter(c *gin.Context) (model.ServiceFilter, error) {
+	filter := model.ServiceFilter{
+		VesselName:  c.Query("vesselName"),
+		ServiceType: c.Query("serviceType"),
+		Cargo:       c.Query("cargo"),
+	}
+	var err error
+	filter.From, err = queryTime(c, "from")
+	if err != nil {
+		return filter, err
+	}
+	filter.To, err = queryTime(c, "to")
+	if err != nil {
+		return filter, err
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return filter, errors.New("to must not be before from")
+	}
+	return filter, nil
+}
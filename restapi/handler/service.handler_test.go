@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"golang-restapi/mocks"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/usecase"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type serviceHandlerSuite struct {
+	suite.Suite
+	usecase *mocks.ServiceUsecase
+	router  *gin.Engine
+}
+
+var (
+	ownerPermissions = []string{model.PermissionServiceReadOwn, model.PermissionServiceWriteOwn}
+	ownerActor       = model.Actor{UserID: 1, OrgID: 7, Permissions: ownerPermissions}
+)
+
+func (suite *serviceHandlerSuite) SetupTest() {
+	suite.usecase = new(mocks.ServiceUsecase)
+	handler := InitializeServiceHandler(suite.usecase)
+
+	router := gin.New()
+	router.Use(asUser(1, 7, ownerPermissions...))
+	router.POST("/service/", handler.CreateServiceRequest)
+	router.GET("/service/", handler.GetServices)
+	router.GET("/service/:id", handler.GetServiceByID)
+	router.PATCH("/service/:id/status", handler.UpdateServiceStatus)
+	router.GET("/schedule/arrivals", handler.GetUpcomingArrivals)
+	suite.router = router
+}
+
+func (suite *serviceHandlerSuite) TestCreateServiceRequest() {
+	etd := time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+	valid := model.CreateServiceData{VesselName: "vessel", ServiceType: "bunkering", DataAgent: "agent", Cargo: "oil", ETD: etd, ETA: etd.Add(time.Hour)}
+	cases := []struct {
+		name        string
+		body        interface{}
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "created", body: valid, wantStatus: http.StatusOK},
+		{name: "missing vessel", body: model.CreateServiceData{ETD: etd, ETA: etd.Add(time.Hour)}, wantStatus: http.StatusBadRequest, wantMessage: "Please provide vesselName, serviceType, dataAgent, cargo, etd and eta"},
+		{name: "etd not a timestamp", body: `{"vesselName": "vessel", "etd": "tomorrow"}`, wantStatus: http.StatusBadRequest, wantMessage: "Invalid request body, etd and eta must be RFC3339 timestamps"},
+		{name: "invalid schedule", body: valid, err: model.ErrInvalidSchedule, wantStatus: http.StatusBadRequest, wantMessage: "Please provide etd and eta, eta must be after etd"},
+		{name: "no organization", body: valid, err: usecase.ErrNoOrganization, wantStatus: http.StatusForbidden, wantMessage: "Please create or join an organization first"},
+		{name: "server error", body: valid, err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("CreateServiceRequest", valid, ownerActor).Return(model.Service{ID: 1, RequestID: 100000, Status: model.ServiceRequested}, c.err)
+
+			response := serve(suite.router, http.MethodPost, "/service/", c.body)
+
+			suite.Equal(c.wantStatus, response.Code)
+			if len(c.wantMessage) > 0 {
+				suite.Equal(c.wantMessage, message(response))
+			}
+		})
+	}
+}
+
+func (suite *serviceHandlerSuite) TestGetServices_OnlyOwn_Positive() {
+	suite.usecase.On("FindServices", mock.MatchedBy(func(filter model.ServiceFilter) bool {
+		return filter.UserID != nil && *filter.UserID == 1 && filter.VesselName == "ever" && filter.Limit == 3 && filter.Offset == 2
+	}), ownerActor).Return([]model.Service{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+
+	response := serve(suite.router, http.MethodGet, "/service/?vesselName=ever&page=2&limit=2", nil)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("true", response.Header().Get("X-Has-More"))
+	suite.Equal("2", response.Header().Get("X-Page"))
+	suite.Contains(response.Body.String(), `"data":[{"_id":1`)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *serviceHandlerSuite) TestGetServices_LegacyAll() {
+	response := serve(suite.router, http.MethodGet, "/service/all", nil)
+	suite.Equal(http.StatusForbidden, response.Code)
+
+	suite.usecase.On("FindServices", mock.MatchedBy(func(filter model.ServiceFilter) bool {
+		return filter.UserID == nil
+	}), mock.Anything).Return([]model.Service{}, nil)
+	router := gin.New()
+	router.Use(asUser(1, 7, model.PermissionServiceReadOwn, model.PermissionServiceReadAny))
+	router.GET("/service/:id", InitializeServiceHandler(suite.usecase).GetServiceByID)
+
+	response = serve(router, http.MethodGet, "/service/all", nil)
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Contains(response.Body.String(), `"data":[]`)
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *serviceHandlerSuite) TestGetServices_InvalidQuery_Negative() {
+	cases := []string{
+		"/service/?from=yesterday",
+		"/service/?from=2021-03-02T00:00:00Z&to=2021-03-01T00:00:00Z",
+		"/service/?limit=1000",
+		"/service/?page=0",
+	}
+
+	for _, path := range cases {
+		response := serve(suite.router, http.MethodGet, path, nil)
+		suite.Equal(http.StatusBadRequest, response.Code, path)
+	}
+	suite.usecase.AssertNotCalled(suite.T(), "FindServices", mock.Anything, mock.Anything)
+}
+
+func (suite *serviceHandlerSuite) TestGetUpcomingArrivals_CSV_Positive() {
+	eta := time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+	suite.usecase.On("GetUpcomingServices", mock.MatchedBy(func(filter model.UpcomingFilter) bool {
+		return filter.Event == model.ScheduleArrivals && filter.UserID != nil && filter.Until.Sub(filter.Since) == 6*time.Hour && filter.Offset == 0
+	}), ownerActor).Return([]model.Service{{RequestID: 100000, Status: model.ServiceApproved, VesselName: "vessel", ETD: eta.Add(-time.Hour), ETA: eta}}, nil)
+
+	response := serve(suite.router, http.MethodGet, "/schedule/arrivals?hours=6&format=csv", nil)
+
+	suite.Equal(http.StatusOK, response.Code)
+	suite.Equal("text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+	records, err := csv.NewReader(strings.NewReader(response.Body.String())).ReadAll()
+	suite.NoError(err)
+	suite.Equal([][]string{model.ServiceCSVHeader, {"100000", "approved", "vessel", "", "", "", "2021-03-01T07:00:00Z", "2021-03-01T08:00:00Z"}}, records)
+}
+
+func (suite *serviceHandlerSuite) TestGetServices_CSV_EveryBatch_Positive() {
+	batch := make([]model.Service, csvBatchSize)
+	suite.usecase.On("FindServices", mock.MatchedBy(func(filter model.ServiceFilter) bool {
+		return filter.Limit == csvBatchSize && filter.Offset == 0
+	}), ownerActor).Return(batch, nil)
+	suite.usecase.On("FindServices", mock.MatchedBy(func(filter model.ServiceFilter) bool {
+		return filter.Limit == csvBatchSize && filter.Offset == csvBatchSize
+	}), ownerActor).Return([]model.Service{{VesselName: "=HYPERLINK(\"http://evil\")", Cargo: "-2+3", DataAgent: "@agent"}}, nil)
+
+	// page and limit only apply to JSON, the export has every matching service
+	response := serve(suite.router, http.MethodGet, "/service/?format=csv&limit=1000", nil)
+
+	suite.Equal(http.StatusOK, response.Code)
+	records, err := csv.NewReader(strings.NewReader(response.Body.String())).ReadAll()
+	suite.NoError(err)
+	suite.Len(records, csvBatchSize+2)
+	last := records[len(records)-1]
+	suite.Equal(`'=HYPERLINK("http://evil")`, last[2])
+	suite.Equal("'@agent", last[4])
+	suite.Equal("'-2+3", last[5])
+	suite.usecase.AssertExpectations(suite.T())
+}
+
+func (suite *serviceHandlerSuite) TestGetServices_CSV_NoOrganization_Negative() {
+	suite.usecase.On("FindServices", mock.Anything, ownerActor).Return(nil, usecase.ErrNoOrganization)
+
+	response := serve(suite.router, http.MethodGet, "/service/?format=csv", nil)
+
+	suite.Equal(http.StatusForbidden, response.Code)
+	suite.NotEqual("text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+}
+
+func (suite *serviceHandlerSuite) TestGetServiceByID() {
+	cases := []struct {
+		name       string
+		path       string
+		err        error
+		wantStatus int
+	}{
+		{name: "found", path: "/service/10", wantStatus: http.StatusOK},
+		{name: "invalid id", path: "/service/ten", wantStatus: http.StatusBadRequest},
+		{name: "not found", path: "/service/10", err: repository.ErrServiceNotFound, wantStatus: http.StatusNotFound},
+		{name: "no organization", path: "/service/10", err: usecase.ErrNoOrganization, wantStatus: http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.usecase.On("GetService", uint64(10), ownerActor).Return(model.Service{ID: 10}, []model.ServiceStatusChange{}, c.err)
+
+			response := serve(suite.router, http.MethodGet, c.path, nil)
+
+			suite.Equal(c.wantStatus, response.Code)
+		})
+	}
+}
+
+func (suite *serviceHandlerSuite) TestUpdateServiceStatus() {
+	cases := []struct {
+		err        error
+		wantStatus int
+	}{
+		{err: nil, wantStatus: http.StatusOK},
+		{err: usecase.ErrNoOrganization, wantStatus: http.StatusForbidden},
+		{err: usecase.ErrInvalidStatus, wantStatus: http.StatusBadRequest},
+		{err: repository.ErrServiceNotFound, wantStatus: http.StatusNotFound},
+		{err: usecase.ErrOnlyCancel, wantStatus: http.StatusForbidden},
+		{err: repository.ErrInvalidTransition, wantStatus: http.StatusConflict},
+		{err: errors.New("db down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		suite.Run(fmt.Sprint(c.err), func() {
+			suite.SetupTest()
+			suite.usecase.On("UpdateServiceStatus", uint64(10), model.ServiceCancelled, "no longer needed", ownerActor).Return(model.Service{ID: 10, Status: model.ServiceCancelled}, c.err)
+
+			response := serve(suite.router, http.MethodPatch, "/service/10/status", model.UpdateServiceStatusData{Status: model.ServiceCancelled, Note: "no longer needed"})
+
+			suite.Equal(c.wantStatus, response.Code)
+		})
+	}
+}
+
+func TestServiceHandler(t *testing.T) {
+	suite.Run(t, new(serviceHandlerSuite))
+}
@@ -1,24 +1,80 @@
 package handler
 
 import (
-	"fmt"
+	"golang-restapi/model"
 	"golang-restapi/repository"
+	"golang-restapi/usecase"
+	"golang-restapi/utils"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+// UserHandler -- Routes for the current user and user management
+type UserHandler interface {
+	GetUserData(c *gin.Context)
+	AssignRole(c *gin.Context)
+}
+
+type userHandler struct {
+	userUsecase usecase.UserUsecase
+}
+
+// InitializeUserHandler -- Create the user handler
+func InitializeUserHandler(userUsecase usecase.UserUsecase) UserHandler {
+	return &userHandler{
+		userUsecase: userUsecase,
+	}
+}
+
 // GetUserData -- Retrieve user data
-func GetUserData(c *gin.Context) {
-	userID := uint64(c.MustGet("userID").(float64))
-	fmt.Println("GetUserData userID", userID)
-	user, _ := repository.GetUserByID(userID)
+func (h *userHandler) GetUserData(c *gin.Context) {
+	user, err := h.userUsecase.GetUser(actorFromContext(c).UserID)
+	if err == repository.ErrUserNotFound {
+		utils.ResponseNotFound(c, "User not found")
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Nice to see you bruh!",
-		"data": gin.H{
-			"_id":      user.ID,
-			"email":    user.Email,
-			"password": user.Password,
-		},
+		"data":    user,
+	})
+}
+
+// AssignRole -- Change the role of a user
+func (h *userHandler) AssignRole(c *gin.Context) {
+	var data model.AssignRoleData
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ResponseBadRequest(c, "Invalid user id")
+		return
+	}
+	if !bindJSON(c, &data) {
+		return
+	}
+	if len(data.Role) == 0 {
+		utils.ResponseBadRequest(c, "Please provide role")
+		return
+	}
+	err = h.userUsecase.AssignRole(userID, data.Role)
+	if err == repository.ErrRoleNotFound {
+		utils.ResponseBadRequest(c, "Role not found")
+		return
+	}
+	if err == repository.ErrUserNotFound {
+		utils.ResponseNotFound(c, "User not found")
+		return
+	}
+	if err != nil {
+		utils.ResponseServerError(c)
+		return
+	}
+	utils.ResponseSuccess(c, "Success to assign role", gin.H{
+		"_id":  userID,
+		"role": data.Role,
 	})
 }
@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidHeader -- Returned when a header value would inject other headers
+var ErrInvalidHeader = errors.New("mailer: header contains a line break")
+
+// Message -- Plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer -- Deliver emails to users
+type Mailer interface {
+	Send(message Message) error
+}
+
+// format -- Render the message as RFC 822 text
+func format(from string, message Message) ([]byte, error) {
+	for _, value := range []string{from, message.To, message.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "From: %s\r\n", from)
+	fmt.Fprintf(&builder, "To: %s\r\n", message.To)
+	fmt.Fprintf(&builder, "Subject: %s\r\n", message.Subject)
+	fmt.Fprintf(&builder, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return []byte(builder.String()), nil
+}
@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MemoryOutbox -- Keep sent emails in memory, used in tests
+type MemoryOutbox struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryOutbox -- Create an empty in-memory outbox
+func NewMemoryOutbox() *MemoryOutbox {
+	return &MemoryOutbox{}
+}
+
+// Send -- Append the message to the outbox
+func (outbox *MemoryOutbox) Send(message Message) error {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+	outbox.messages = append(outbox.messages, message)
+	return nil
+}
+
+// Messages -- Every message sent so far
+func (outbox *MemoryOutbox) Messages() []Message {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+	messages := make([]Message, len(outbox.messages))
+	copy(messages, outbox.messages)
+	return messages
+}
+
+// Last -- The most recent message sent to an address
+func (outbox *MemoryOutbox) Last(to string) (Message, bool) {
+	outbox.mu.Lock()
+	defer outbox.mu.Unlock()
+	for i := len(outbox.messages) - 1; i >= 0; i-- {
+		if outbox.messages[i].To == to {
+			return outbox.messages[i], true
+		}
+	}
+	return Message{}, false
+}
+
+type fileOutbox struct {
+	dir  string
+	from string
+}
+
+// NewFileOutbox -- Write every email as an .eml file into dir, handy for local development
+func NewFileOutbox(dir, from string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &fileOutbox{dir: dir, from: from}, nil
+}
+
+func (outbox *fileOutbox) Send(message Message) error {
+	body, err := format(outbox.from, message)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), sanitize(message.To))
+	return ioutil.WriteFile(filepath.Join(outbox.dir, name), body, 0600)
+}
+
+func sanitize(address string) string {
+	result := []rune(address)
+	for i, r := range result {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_') {
+			result[i] = '_'
+		}
+	}
+	return string(result)
+}
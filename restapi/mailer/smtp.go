@@ -0,0 +1,33 @@
+package mailer
+
+import (
+	"net"
+	"net/smtp"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer -- Send emails through an SMTP server, without auth when username is empty
+func NewSMTPMailer(host, port, username, password, from string) Mailer {
+	var auth smtp.Auth
+	if len(username) != 0 {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *smtpMailer) Send(message Message) error {
+	body, err := format(m.from, message)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{message.To}, body)
+}
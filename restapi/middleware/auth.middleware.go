@@ -25,18 +25,24 @@ func validateToken(c *gin.Context) {
 		})
 	} else {
 		token = strings.Split(token, "Bearer ")[1]
-		userID, err := getPayload(token)
+		claims, err := getPayload(token)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 				"message": "You shall not pass!",
 			})
+			return
 		}
-		c.Set("userID", userID)
+		c.Set("userID", claims["user_id"])
+		c.Set("role", claims["role"])
+		c.Set("permissions", getPermissions(claims))
+		// Tokens issued before organizations existed carry no org_id, they aren't scoped to any
+		orgID, _ := claims["org_id"].(float64)
+		c.Set("orgID", orgID)
 		c.Next()
 	}
 }
 
-func getPayload(tokenString string) (interface{}, error) {
+func getPayload(tokenString string) (jwt.MapClaims, error) {
 	claims := jwt.MapClaims{}
 	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(os.Getenv("SECRET_KEY")), nil
@@ -45,5 +51,16 @@ func getPayload(tokenString string) (interface{}, error) {
 		// panic(err)
 		return nil, err
 	}
-	return claims["user_id"], nil
+	return claims, nil
+}
+
+func getPermissions(claims jwt.MapClaims) []string {
+	values, _ := claims["permissions"].([]interface{})
+	permissions := make([]string, 0, len(values))
+	for _, value := range values {
+		if permission, ok := value.(string); ok {
+			permissions = append(permissions, permission)
+		}
+	}
+	return permissions
 }
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission -- Only let requests through when the token grants the permission
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, granted := range c.GetStringSlice("permissions") {
+			if granted == permission {
+				c.Next()
+				return
+			}
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"message": "You don't have permission to access this resource",
+		})
+	}
+}
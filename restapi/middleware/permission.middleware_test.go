@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"golang-restapi/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequirePermission(t *testing.T) {
+	cases := []struct {
+		name        string
+		permissions []string
+		wantStatus  int
+	}{
+		{name: "granted", permissions: []string{model.PermissionUserReadOwn, model.PermissionUserRoleAssign}, wantStatus: http.StatusOK},
+		{name: "other permissions only", permissions: []string{model.PermissionUserReadOwn}, wantStatus: http.StatusForbidden},
+		{name: "no permissions", wantStatus: http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(ctx *gin.Context) {
+				if c.permissions != nil {
+					ctx.Set("permissions", c.permissions)
+				}
+				ctx.Next()
+			})
+			router.PUT("/admin/user/:id/role", RequirePermission(model.PermissionUserRoleAssign), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, httptest.NewRequest(http.MethodPut, "/admin/user/2/role", nil))
+
+			assert.Equal(t, c.wantStatus, response.Code)
+		})
+	}
+}
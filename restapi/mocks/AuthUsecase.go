@@ -0,0 +1,120 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthUsecase is an autogenerated mock type for the AuthUsecase type
+type AuthUsecase struct {
+	mock.Mock
+}
+
+// ChangePassword provides a mock function with given fields: email, code, newPassword
+func (_m *AuthUsecase) ChangePassword(email string, code string, newPassword string) error {
+	ret := _m.Called(email, code, newPassword)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string) error); ok {
+		r0 = rf(email, code, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmAccount provides a mock function with given fields: email, code
+func (_m *AuthUsecase) ConfirmAccount(email string, code string) error {
+	ret := _m.Called(email, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(email, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Login provides a mock function with given fields: ctx, ip, email, password
+func (_m *AuthUsecase) Login(ctx context.Context, ip string, email string, password string) (model.UserResponse, string, error) {
+	ret := _m.Called(ctx, ip, email, password)
+
+	var r0 model.UserResponse
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) model.UserResponse); ok {
+		r0 = rf(ctx, ip, email, password)
+	} else {
+		r0 = ret.Get(0).(model.UserResponse)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) string); ok {
+		r1 = rf(ctx, ip, email, password)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, string) error); ok {
+		r2 = rf(ctx, ip, email, password)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Register provides a mock function with given fields: email, password
+func (_m *AuthUsecase) Register(email string, password string) (model.User, error) {
+	ret := _m.Called(email, password)
+
+	var r0 model.User
+	if rf, ok := ret.Get(0).(func(string, string) model.User); ok {
+		r0 = rf(email, password)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(email, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestPassword provides a mock function with given fields: email
+func (_m *AuthUsecase) RequestPassword(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResendConfirmation provides a mock function with given fields: email
+func (_m *AuthUsecase) ResendConfirmation(email string) error {
+	ret := _m.Called(email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
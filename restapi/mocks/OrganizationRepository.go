@@ -0,0 +1,139 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// OrganizationRepository is an autogenerated mock type for the OrganizationRepository type
+type OrganizationRepository struct {
+	mock.Mock
+}
+
+// AcceptInvite provides a mock function with given fields: email, codeHash, userID
+func (_m *OrganizationRepository) AcceptInvite(email string, codeHash string, userID uint64) (uint64, error) {
+	ret := _m.Called(email, codeHash, userID)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(string, string, uint64) uint64); ok {
+		r0 = rf(email, codeHash, userID)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, string, uint64) error); ok {
+		r1 = rf(email, codeHash, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateInvite provides a mock function with given fields: orgID, email, codeHash, invitedBy, ttl
+func (_m *OrganizationRepository) CreateInvite(orgID uint64, email string, codeHash string, invitedBy uint64, ttl time.Duration) error {
+	ret := _m.Called(orgID, email, codeHash, invitedBy, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, string, string, uint64, time.Duration) error); ok {
+		r0 = rf(orgID, email, codeHash, invitedBy, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateOrganization provides a mock function with given fields: name, ownerID
+func (_m *OrganizationRepository) CreateOrganization(name string, ownerID uint64) (model.Organization, error) {
+	ret := _m.Called(name, ownerID)
+
+	var r0 model.Organization
+	if rf, ok := ret.Get(0).(func(string, uint64) model.Organization); ok {
+		r0 = rf(name, ownerID)
+	} else {
+		r0 = ret.Get(0).(model.Organization)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, uint64) error); ok {
+		r1 = rf(name, ownerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMembers provides a mock function with given fields: orgID
+func (_m *OrganizationRepository) GetMembers(orgID uint64) ([]model.Membership, error) {
+	ret := _m.Called(orgID)
+
+	var r0 []model.Membership
+	if rf, ok := ret.Get(0).(func(uint64) []model.Membership); ok {
+		r0 = rf(orgID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Membership)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMembership provides a mock function with given fields: orgID, userID
+func (_m *OrganizationRepository) GetMembership(orgID uint64, userID uint64) (model.Membership, error) {
+	ret := _m.Called(orgID, userID)
+
+	var r0 model.Membership
+	if rf, ok := ret.Get(0).(func(uint64, uint64) model.Membership); ok {
+		r0 = rf(orgID, userID)
+	} else {
+		r0 = ret.Get(0).(model.Membership)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, uint64) error); ok {
+		r1 = rf(orgID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserOrganizations provides a mock function with given fields: userID
+func (_m *OrganizationRepository) GetUserOrganizations(userID uint64) ([]model.UserOrganization, error) {
+	ret := _m.Called(userID)
+
+	var r0 []model.UserOrganization
+	if rf, ok := ret.Get(0).(func(uint64) []model.UserOrganization); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.UserOrganization)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,151 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// OrganizationUsecase is an autogenerated mock type for the OrganizationUsecase type
+type OrganizationUsecase struct {
+	mock.Mock
+}
+
+// AcceptInvite provides a mock function with given fields: actor, code
+func (_m *OrganizationUsecase) AcceptInvite(actor model.Actor, code string) (uint64, string, error) {
+	ret := _m.Called(actor, code)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(model.Actor, string) uint64); ok {
+		r0 = rf(actor, code)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(model.Actor, string) string); ok {
+		r1 = rf(actor, code)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(model.Actor, string) error); ok {
+		r2 = rf(actor, code)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// CreateOrganization provides a mock function with given fields: actor, name
+func (_m *OrganizationUsecase) CreateOrganization(actor model.Actor, name string) (model.Organization, string, error) {
+	ret := _m.Called(actor, name)
+
+	var r0 model.Organization
+	if rf, ok := ret.Get(0).(func(model.Actor, string) model.Organization); ok {
+		r0 = rf(actor, name)
+	} else {
+		r0 = ret.Get(0).(model.Organization)
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(model.Actor, string) string); ok {
+		r1 = rf(actor, name)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(model.Actor, string) error); ok {
+		r2 = rf(actor, name)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMembers provides a mock function with given fields: actor
+func (_m *OrganizationUsecase) GetMembers(actor model.Actor) ([]model.Membership, error) {
+	ret := _m.Called(actor)
+
+	var r0 []model.Membership
+	if rf, ok := ret.Get(0).(func(model.Actor) []model.Membership); ok {
+		r0 = rf(actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Membership)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.Actor) error); ok {
+		r1 = rf(actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOrganizations provides a mock function with given fields: actor
+func (_m *OrganizationUsecase) GetOrganizations(actor model.Actor) ([]model.UserOrganization, error) {
+	ret := _m.Called(actor)
+
+	var r0 []model.UserOrganization
+	if rf, ok := ret.Get(0).(func(model.Actor) []model.UserOrganization); ok {
+		r0 = rf(actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.UserOrganization)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.Actor) error); ok {
+		r1 = rf(actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Invite provides a mock function with given fields: actor, email
+func (_m *OrganizationUsecase) Invite(actor model.Actor, email string) error {
+	ret := _m.Called(actor, email)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(model.Actor, string) error); ok {
+		r0 = rf(actor, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SwitchOrganization provides a mock function with given fields: actor, orgID
+func (_m *OrganizationUsecase) SwitchOrganization(actor model.Actor, orgID uint64) (string, error) {
+	ret := _m.Called(actor, orgID)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(model.Actor, uint64) string); ok {
+		r0 = rf(actor, orgID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.Actor, uint64) error); ok {
+		r1 = rf(actor, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,49 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoleRepository is an autogenerated mock type for the RoleRepository type
+type RoleRepository struct {
+	mock.Mock
+}
+
+// AssignRole provides a mock function with given fields: userID, roleName
+func (_m *RoleRepository) AssignRole(userID uint64, roleName string) error {
+	ret := _m.Called(userID, roleName)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, string) error); ok {
+		r0 = rf(userID, roleName)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetRoleByUserID provides a mock function with given fields: userID
+func (_m *RoleRepository) GetRoleByUserID(userID uint64) (model.Role, error) {
+	ret := _m.Called(userID)
+
+	var r0 model.Role
+	if rf, ok := ret.Get(0).(func(uint64) model.Role); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(model.Role)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
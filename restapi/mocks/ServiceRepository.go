@@ -0,0 +1,146 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ServiceRepository is an autogenerated mock type for the ServiceRepository type
+type ServiceRepository struct {
+	mock.Mock
+}
+
+// CreateServiceRequest provides a mock function with given fields: data, userID, orgID
+func (_m *ServiceRepository) CreateServiceRequest(data model.CreateServiceData, userID uint64, orgID uint64) (model.Service, error) {
+	ret := _m.Called(data, userID, orgID)
+
+	var r0 model.Service
+	if rf, ok := ret.Get(0).(func(model.CreateServiceData, uint64, uint64) model.Service); ok {
+		r0 = rf(data, userID, orgID)
+	} else {
+		r0 = ret.Get(0).(model.Service)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.CreateServiceData, uint64, uint64) error); ok {
+		r1 = rf(data, userID, orgID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindServices provides a mock function with given fields: filter
+func (_m *ServiceRepository) FindServices(filter model.ServiceFilter) ([]model.Service, error) {
+	ret := _m.Called(filter)
+
+	var r0 []model.Service
+	if rf, ok := ret.Get(0).(func(model.ServiceFilter) []model.Service); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.ServiceFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceByID provides a mock function with given fields: orgID, serviceID
+func (_m *ServiceRepository) GetServiceByID(orgID uint64, serviceID uint64) (model.Service, error) {
+	ret := _m.Called(orgID, serviceID)
+
+	var r0 model.Service
+	if rf, ok := ret.Get(0).(func(uint64, uint64) model.Service); ok {
+		r0 = rf(orgID, serviceID)
+	} else {
+		r0 = ret.Get(0).(model.Service)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, uint64) error); ok {
+		r1 = rf(orgID, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetServiceHistory provides a mock function with given fields: orgID, serviceID
+func (_m *ServiceRepository) GetServiceHistory(orgID uint64, serviceID uint64) ([]model.ServiceStatusChange, error) {
+	ret := _m.Called(orgID, serviceID)
+
+	var r0 []model.ServiceStatusChange
+	if rf, ok := ret.Get(0).(func(uint64, uint64) []model.ServiceStatusChange); ok {
+		r0 = rf(orgID, serviceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ServiceStatusChange)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, uint64) error); ok {
+		r1 = rf(orgID, serviceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUpcomingServices provides a mock function with given fields: filter
+func (_m *ServiceRepository) GetUpcomingServices(filter model.UpcomingFilter) ([]model.Service, error) {
+	ret := _m.Called(filter)
+
+	var r0 []model.Service
+	if rf, ok := ret.Get(0).(func(model.UpcomingFilter) []model.Service); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.UpcomingFilter) error); ok {
+		r1 = rf(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateServiceStatus provides a mock function with given fields: orgID, serviceID, status, changedBy, note
+func (_m *ServiceRepository) UpdateServiceStatus(orgID uint64, serviceID uint64, status string, changedBy uint64, note string) (model.Service, error) {
+	ret := _m.Called(orgID, serviceID, status, changedBy, note)
+
+	var r0 model.Service
+	if rf, ok := ret.Get(0).(func(uint64, uint64, string, uint64, string) model.Service); ok {
+		r0 = rf(orgID, serviceID, status, changedBy, note)
+	} else {
+		r0 = ret.Get(0).(model.Service)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, uint64, string, uint64, string) error); ok {
+		r1 = rf(orgID, serviceID, status, changedBy, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,132 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ServiceUsecase is an autogenerated mock type for the ServiceUsecase type
+type ServiceUsecase struct {
+	mock.Mock
+}
+
+// CreateServiceRequest provides a mock function with given fields: data, actor
+func (_m *ServiceUsecase) CreateServiceRequest(data model.CreateServiceData, actor model.Actor) (model.Service, error) {
+	ret := _m.Called(data, actor)
+
+	var r0 model.Service
+	if rf, ok := ret.Get(0).(func(model.CreateServiceData, model.Actor) model.Service); ok {
+		r0 = rf(data, actor)
+	} else {
+		r0 = ret.Get(0).(model.Service)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.CreateServiceData, model.Actor) error); ok {
+		r1 = rf(data, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindServices provides a mock function with given fields: filter, actor
+func (_m *ServiceUsecase) FindServices(filter model.ServiceFilter, actor model.Actor) ([]model.Service, error) {
+	ret := _m.Called(filter, actor)
+
+	var r0 []model.Service
+	if rf, ok := ret.Get(0).(func(model.ServiceFilter, model.Actor) []model.Service); ok {
+		r0 = rf(filter, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.ServiceFilter, model.Actor) error); ok {
+		r1 = rf(filter, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetService provides a mock function with given fields: serviceID, actor
+func (_m *ServiceUsecase) GetService(serviceID uint64, actor model.Actor) (model.Service, []model.ServiceStatusChange, error) {
+	ret := _m.Called(serviceID, actor)
+
+	var r0 model.Service
+	if rf, ok := ret.Get(0).(func(uint64, model.Actor) model.Service); ok {
+		r0 = rf(serviceID, actor)
+	} else {
+		r0 = ret.Get(0).(model.Service)
+	}
+
+	var r1 []model.ServiceStatusChange
+	if rf, ok := ret.Get(1).(func(uint64, model.Actor) []model.ServiceStatusChange); ok {
+		r1 = rf(serviceID, actor)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]model.ServiceStatusChange)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(uint64, model.Actor) error); ok {
+		r2 = rf(serviceID, actor)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetUpcomingServices provides a mock function with given fields: filter, actor
+func (_m *ServiceUsecase) GetUpcomingServices(filter model.UpcomingFilter, actor model.Actor) ([]model.Service, error) {
+	ret := _m.Called(filter, actor)
+
+	var r0 []model.Service
+	if rf, ok := ret.Get(0).(func(model.UpcomingFilter, model.Actor) []model.Service); ok {
+		r0 = rf(filter, actor)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Service)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(model.UpcomingFilter, model.Actor) error); ok {
+		r1 = rf(filter, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateServiceStatus provides a mock function with given fields: serviceID, status, note, actor
+func (_m *ServiceUsecase) UpdateServiceStatus(serviceID uint64, status string, note string, actor model.Actor) (model.Service, error) {
+	ret := _m.Called(serviceID, status, note, actor)
+
+	var r0 model.Service
+	if rf, ok := ret.Get(0).(func(uint64, string, string, model.Actor) model.Service); ok {
+		r0 = rf(serviceID, status, note, actor)
+	} else {
+		r0 = ret.Get(0).(model.Service)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, string, string, model.Actor) error); ok {
+		r1 = rf(serviceID, status, note, actor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,112 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserRepository is an autogenerated mock type for the UserRepository type
+type UserRepository struct {
+	mock.Mock
+}
+
+// ChangePassword provides a mock function with given fields: email, newPassword
+func (_m *UserRepository) ChangePassword(email string, newPassword string) error {
+	ret := _m.Called(email, newPassword)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(email, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConfirmAccount provides a mock function with given fields: userID
+func (_m *UserRepository) ConfirmAccount(userID uint64) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUser provides a mock function with given fields: user
+func (_m *UserRepository) CreateUser(user *model.User) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUser provides a mock function with given fields: userID
+func (_m *UserRepository) DeleteUser(userID uint64) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUserByEmail provides a mock function with given fields: email
+func (_m *UserRepository) GetUserByEmail(email string) (model.User, error) {
+	ret := _m.Called(email)
+
+	var r0 model.User
+	if rf, ok := ret.Get(0).(func(string) model.User); ok {
+		r0 = rf(email)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserByID provides a mock function with given fields: userID
+func (_m *UserRepository) GetUserByID(userID uint64) (model.User, error) {
+	ret := _m.Called(userID)
+
+	var r0 model.User
+	if rf, ok := ret.Get(0).(func(uint64) model.User); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(model.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
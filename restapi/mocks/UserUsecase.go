@@ -0,0 +1,49 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "golang-restapi/model"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserUsecase is an autogenerated mock type for the UserUsecase type
+type UserUsecase struct {
+	mock.Mock
+}
+
+// AssignRole provides a mock function with given fields: userID, role
+func (_m *UserUsecase) AssignRole(userID uint64, role string) error {
+	ret := _m.Called(userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, string) error); ok {
+		r0 = rf(userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetUser provides a mock function with given fields: userID
+func (_m *UserUsecase) GetUser(userID uint64) (model.UserResponse, error) {
+	ret := _m.Called(userID)
+
+	var r0 model.UserResponse
+	if rf, ok := ret.Get(0).(func(uint64) model.UserResponse); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(model.UserResponse)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
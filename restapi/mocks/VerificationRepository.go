@@ -0,0 +1,49 @@
+// Code generated by mockery v0.0.0-dev. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// VerificationRepository is an autogenerated mock type for the VerificationRepository type
+type VerificationRepository struct {
+	mock.Mock
+}
+
+// ConsumeVerificationCode provides a mock function with given fields: userID, purpose, codeHash
+func (_m *VerificationRepository) ConsumeVerificationCode(userID uint64, purpose string, codeHash string) (bool, error) {
+	ret := _m.Called(userID, purpose, codeHash)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(uint64, string, string) bool); ok {
+		r0 = rf(userID, purpose, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, string, string) error); ok {
+		r1 = rf(userID, purpose, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateVerificationCode provides a mock function with given fields: userID, purpose, codeHash, ttl
+func (_m *VerificationRepository) CreateVerificationCode(userID uint64, purpose string, codeHash string, ttl time.Duration) error {
+	ret := _m.Called(userID, purpose, codeHash, ttl)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint64, string, string, time.Duration) error); ok {
+		r0 = rf(userID, purpose, codeHash, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
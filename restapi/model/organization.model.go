@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Roles of a user inside an organization
+const (
+	OrgRoleOwner  = "owner"
+	OrgRoleMember = "member"
+)
+
+// InviteTTL -- Lifetime of an organization invite
+const InviteTTL = 7 * 24 * time.Hour
+
+// Organization -- Company of shipping agents sharing service requests
+type Organization struct {
+	ID        uint64    `json:"_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UserOrganization -- An organization the user belongs to, with the user's role in it
+type UserOrganization struct {
+	Organization
+	Role string `json:"role"`
+}
+
+// Membership -- A member of an organization
+type Membership struct {
+	OrgID    uint64    `json:"orgId"`
+	UserID   uint64    `json:"userId"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joinedAt"`
+}
+
+// CreateOrganizationData -- Used in CreateOrganization handler
+type CreateOrganizationData struct {
+	Name string `json:"name"`
+}
+
+// InviteData -- Used in Invite handler
+type InviteData struct {
+	Email string `json:"email"`
+}
+
+// AcceptInviteData -- Used in AcceptInvite handler
+type AcceptInviteData struct {
+	Code string `json:"code"`
+}
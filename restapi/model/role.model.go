@@ -0,0 +1,50 @@
+package model
+
+// Role names
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Permission names, formatted as resource:action:scope
+const (
+	PermissionUserReadOwn     = "user:read:own"
+	PermissionUserRoleAssign  = "user:role:assign"
+	PermissionServiceReadOwn  = "service:read:own"
+	PermissionServiceReadAny  = "service:read:any"
+	PermissionServiceWriteOwn = "service:write:own"
+	// PermissionServiceStatusAny -- Move any service request through its workflow, owners can only cancel
+	PermissionServiceStatusAny = "service:status:any"
+	PermissionOrgReadOwn       = "org:read:own"
+	PermissionOrgWriteOwn      = "org:write:own"
+)
+
+// Role -- Role of a user with its granted permissions
+type Role struct {
+	ID          uint64   `json:"_id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// AssignRoleData -- Used in AssignRole handler
+type AssignRoleData struct {
+	Role string `json:"role"`
+}
+
+// Actor -- The authenticated user making a request, read from the token.
+// OrgID is the organization the token is scoped to, zero when the user isn't in one yet.
+type Actor struct {
+	UserID      uint64
+	OrgID       uint64
+	Permissions []string
+}
+
+// Can -- Whether the token of the actor grants the permission
+func (actor Actor) Can(permission string) bool {
+	for _, granted := range actor.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
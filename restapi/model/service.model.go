@@ -1,15 +1,144 @@
 package model
 
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Service request states
+const (
+	ServiceRequested  = "requested"
+	ServiceApproved   = "approved"
+	ServiceInProgress = "in_progress"
+	ServiceCompleted  = "completed"
+	ServiceCancelled  = "cancelled"
+)
+
+// serviceTransitions -- The states a service request can move to from each state,
+// completed and cancelled are final
+var serviceTransitions = map[string][]string{
+	ServiceRequested:  {ServiceApproved, ServiceCancelled},
+	ServiceApproved:   {ServiceInProgress, ServiceCancelled},
+	ServiceInProgress: {ServiceCompleted, ServiceCancelled},
+}
+
+// CanTransition -- Whether a service request may move from one state to another
+func CanTransition(from string, to string) bool {
+	for _, next := range serviceTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsServiceStatus -- Whether the status is one of the service request states
+func IsServiceStatus(status string) bool {
+	switch status {
+	case ServiceRequested, ServiceApproved, ServiceInProgress, ServiceCompleted, ServiceCancelled:
+		return true
+	}
+	return false
+}
+
 // Service -- Representing service request
 type Service struct {
-	ID          uint64 `json:"_id"`
-	RequestID   uint64 `json:"requestId"`
-	Status      string `json:"status"`
-	VesselName  string `json:"vesselName"`
-	ServiceType string `json:"serviceType"`
-	DataAgent   string `json:"dataAgent"`
-	Cargo       string `json:"cargo"`
-	ETD         string `json:"etd"`
-	ETA         string `json:"eta"`
-	UserID      uint64 `json:"userID"`
+	ID          uint64    `json:"_id"`
+	RequestID   uint64    `json:"requestId"`
+	Status      string    `json:"status"`
+	VesselName  string    `json:"vesselName"`
+	ServiceType string    `json:"serviceType"`
+	DataAgent   string    `json:"dataAgent"`
+	Cargo       string    `json:"cargo"`
+	ETD         time.Time `json:"etd"`
+	ETA         time.Time `json:"eta"`
+	UserID      uint64    `json:"userID"`
+	OrgID       uint64    `json:"orgId"`
+}
+
+// ServiceStatusChange -- One entry of the status history of a service request
+type ServiceStatusChange struct {
+	ID         uint64    `json:"_id"`
+	ServiceID  uint64    `json:"serviceId"`
+	FromStatus *string   `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	ChangedBy  uint64    `json:"changedBy"`
+	Note       string    `json:"note"`
+	ChangedAt  time.Time `json:"changedAt"`
+}
+
+// ServiceCSVHeader -- Column names of Service.CSVRecord
+var ServiceCSVHeader = []string{"request_id", "status", "vessel_name", "service_type", "data_agent", "cargo", "etd", "eta"}
+
+// CSVRecord -- The service as a row of a CSV export, times are RFC3339 in UTC
+func (service Service) CSVRecord() []string {
+	return []string{
+		strconv.FormatUint(service.RequestID, 10),
+		service.Status,
+		service.VesselName,
+		service.ServiceType,
+		service.DataAgent,
+		service.Cargo,
+		service.ETD.UTC().Format(time.RFC3339),
+		service.ETA.UTC().Format(time.RFC3339),
+	}
+}
+
+// ErrInvalidSchedule -- Returned when the ETA of a service request isn't after its ETD
+var ErrInvalidSchedule = errors.New("eta must be after etd")
+
+// CreateServiceData -- Used in CreateServiceRequest handler, the request id and status are set by the server
+type CreateServiceData struct {
+	VesselName  string    `json:"vesselName"`
+	ServiceType string    `json:"serviceType"`
+	DataAgent   string    `json:"dataAgent"`
+	Cargo       string    `json:"cargo"`
+	ETD         time.Time `json:"etd"`
+	ETA         time.Time `json:"eta"`
+}
+
+// ValidateSchedule -- Both times must be set and the vessel must arrive after it departs
+func (data CreateServiceData) ValidateSchedule() error {
+	if data.ETD.IsZero() || data.ETA.IsZero() || !data.ETA.After(data.ETD) {
+		return ErrInvalidSchedule
+	}
+	return nil
+}
+
+// ServiceFilter -- Narrows down the services returned by FindServices, zero values don't filter
+// except OrgID which is always applied. From and To select services whose ETD to ETA period overlaps the window.
+type ServiceFilter struct {
+	OrgID       uint64
+	UserID      *uint64
+	VesselName  string
+	ServiceType string
+	Cargo       string
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+}
+
+// Schedule events listed by GetUpcomingServices
+const (
+	ScheduleArrivals   = "arrivals"
+	ScheduleDepartures = "departures"
+)
+
+// UpcomingFilter -- Selects active services of the organization arriving or departing between Since and Until
+type UpcomingFilter struct {
+	OrgID  uint64
+	UserID *uint64
+	Event  string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+	Offset int
+}
+
+// UpdateServiceStatusData -- Used in UpdateServiceStatus handler
+type UpdateServiceStatusData struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
 }
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from string
+		to   string
+		want bool
+	}{
+		{from: ServiceRequested, to: ServiceApproved, want: true},
+		{from: ServiceRequested, to: ServiceCancelled, want: true},
+		{from: ServiceRequested, to: ServiceInProgress, want: false},
+		{from: ServiceRequested, to: ServiceCompleted, want: false},
+		{from: ServiceApproved, to: ServiceInProgress, want: true},
+		{from: ServiceApproved, to: ServiceRequested, want: false},
+		{from: ServiceInProgress, to: ServiceCompleted, want: true},
+		{from: ServiceInProgress, to: ServiceCancelled, want: true},
+		{from: ServiceCompleted, to: ServiceCancelled, want: false},
+		{from: ServiceCancelled, to: ServiceRequested, want: false},
+		{from: ServiceApproved, to: ServiceApproved, want: false},
+		{from: "unknown", to: ServiceApproved, want: false},
+	}
+
+	for _, c := range cases {
+		if got := CanTransition(c.from, c.to); got != c.want {
+			t.Fatalf("CanTransition(%q, %q): expected %v, got %v", c.from, c.to, c.want, got)
+		}
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	etd := time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		data CreateServiceData
+		want error
+	}{
+		{name: "eta after etd", data: CreateServiceData{ETD: etd, ETA: etd.Add(time.Hour)}, want: nil},
+		{name: "eta equal to etd", data: CreateServiceData{ETD: etd, ETA: etd}, want: ErrInvalidSchedule},
+		{name: "eta before etd", data: CreateServiceData{ETD: etd, ETA: etd.Add(-time.Hour)}, want: ErrInvalidSchedule},
+		{name: "missing etd", data: CreateServiceData{ETA: etd}, want: ErrInvalidSchedule},
+		{name: "missing eta", data: CreateServiceData{ETD: etd}, want: ErrInvalidSchedule},
+	}
+
+	for _, c := range cases {
+		if got := c.data.ValidateSchedule(); got != c.want {
+			t.Fatalf("%s: expected %v, got %v", c.name, c.want, got)
+		}
+	}
+}
+
+func TestServiceCSVRecord(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	service := Service{
+		RequestID:   100001,
+		Status:      ServiceApproved,
+		VesselName:  "Ever Given",
+		ServiceType: "bunkering",
+		DataAgent:   "agent, ltd",
+		Cargo:       "containers",
+		ETD:         time.Date(2021, 3, 1, 15, 0, 0, 0, jakarta),
+		ETA:         time.Date(2021, 3, 2, 15, 0, 0, 0, jakarta),
+	}
+
+	record := service.CSVRecord()
+	want := []string{"100001", "approved", "Ever Given", "bunkering", "agent, ltd", "containers", "2021-03-01T08:00:00Z", "2021-03-02T08:00:00Z"}
+	if len(record) != len(ServiceCSVHeader) {
+		t.Fatalf("expected %d columns, got %d", len(ServiceCSVHeader), len(record))
+	}
+	for i := range want {
+		if record[i] != want[i] {
+			t.Fatalf("column %s: expected %q, got %q", ServiceCSVHeader[i], want[i], record[i])
+		}
+	}
+}
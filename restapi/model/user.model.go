@@ -1,19 +1,41 @@
 package model
 
-// User Model
+// User Model -- Persistence model, use UserResponse when sending a user to the client
 type User struct {
 	ID        uint64 `json:"_id"`
 	Email     string `json:"email"`
-	Password  string `json:"password"`
-	UUID      string `json:"uuid"`
+	Password  string `json:"-"`
 	Confirmed bool   `json:"confirmed"`
 }
 
+// UserResponse -- Public representation of a user, without sensitive fields
+type UserResponse struct {
+	ID        uint64 `json:"_id"`
+	Email     string `json:"email"`
+	Confirmed bool   `json:"confirmed"`
+	Role      string `json:"role,omitempty"`
+}
+
+// NewUserResponse -- Only copy the fields that are safe to expose
+func NewUserResponse(user User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		Email:     user.Email,
+		Confirmed: user.Confirmed,
+	}
+}
+
+// RegisterData --- Used in Register handler
+type RegisterData struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
 // ConfirmData ---  Used in ConfirmAccount handler
 type ConfirmData struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
-	UUID     string `json:"uuid"`
+	Code     string `json:"code"`
 }
 
 // LoginData --- Used in Login handler
@@ -22,6 +44,11 @@ type LoginData struct {
 	Password string `json:"password"`
 }
 
+// ResendConfirmationData -- Used in ResendConfirmation
+type ResendConfirmationData struct {
+	Email string `json:"email"`
+}
+
 // RequestPasswordData -- Used in RequestPassword
 type RequestPasswordData struct {
 	Email string `json:"email"`
@@ -30,6 +57,6 @@ type RequestPasswordData struct {
 // ForgotPasswordData --- Used in ForgotPassword handler
 type ForgotPasswordData struct {
 	Email       string `json:"email"`
-	UUID        string `json:"uuid"`
+	Code        string `json:"code"`
 	NewPassword string `json:"newPassword"`
 }
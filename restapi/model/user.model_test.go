@@ -0,0 +1,39 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const hashedPassword = "$2a$14$ajq8Q7fbtFRQvXpdCq7Jcuy.Rx1h/L4J60Otx.gyNLbAYctGMJ9tK"
+
+func TestUserNeverSerializesPassword(t *testing.T) {
+	user := User{ID: 1, Email: "email", Password: hashedPassword, Confirmed: true}
+	cases := []struct {
+		name  string
+		value interface{}
+	}{
+		{name: "user", value: user},
+		{name: "user response", value: NewUserResponse(user)},
+		{name: "user response with role", value: UserResponse{ID: 1, Email: "email", Role: RoleAdmin}},
+	}
+
+	for _, c := range cases {
+		body, err := json.Marshal(c.value)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", c.name, err)
+		}
+		if strings.Contains(string(body), "password") || strings.Contains(string(body), hashedPassword) {
+			t.Fatalf("%s: expected no password in %s", c.name, body)
+		}
+	}
+}
+
+func TestNewUserResponse(t *testing.T) {
+	response := NewUserResponse(User{ID: 1, Email: "email", Password: hashedPassword, Confirmed: true})
+	expected := UserResponse{ID: 1, Email: "email", Confirmed: true}
+	if response != expected {
+		t.Fatalf("Expected %v, got %v", expected, response)
+	}
+}
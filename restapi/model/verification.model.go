@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// Purposes of a verification code
+const (
+	PurposeConfirmAccount = "confirm_account"
+	PurposeResetPassword  = "reset_password"
+)
+
+// Lifetime of a verification code
+const (
+	ConfirmAccountCodeTTL = 24 * time.Hour
+	ResetPasswordCodeTTL  = 15 * time.Minute
+)
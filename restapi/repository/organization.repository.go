@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"golang-restapi/model"
+	"time"
+)
+
+// ErrNotMember -- Returned when the user doesn't belong to the organization
+var ErrNotMember = errors.New("user is not a member of the organization")
+
+// ErrInviteNotFound -- Returned when an invite code is unknown, accepted, expired or meant for another email
+var ErrInviteNotFound = errors.New("invite not found")
+
+// OrganizationRepository -- Organizations, their members and invites
+type OrganizationRepository interface {
+	CreateOrganization(name string, ownerID uint64) (model.Organization, error)
+	GetUserOrganizations(userID uint64) ([]model.UserOrganization, error)
+	GetMembership(orgID uint64, userID uint64) (model.Membership, error)
+	GetMembers(orgID uint64) ([]model.Membership, error)
+	CreateInvite(orgID uint64, email string, codeHash string, invitedBy uint64, ttl time.Duration) error
+	AcceptInvite(email string, codeHash string, userID uint64) (uint64, error)
+}
+
+type organizationRepository struct {
+	db *sql.DB
+}
+
+// InitializeOrganizationRepository -- Create an organization repository on top of db
+func InitializeOrganizationRepository(db *sql.DB) OrganizationRepository {
+	return &organizationRepository{
+		db: db,
+	}
+}
+
+// CreateOrganization -- Create an organization owned by ownerID
+func (r *organizationRepository) CreateOrganization(name string, ownerID uint64) (model.Organization, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Organization{}, err
+	}
+	defer tx.Rollback()
+
+	org := model.Organization{Name: name}
+	err = tx.QueryRow(`
+		INSERT INTO organizations (name)
+		VALUES ($1)
+		RETURNING _id, created_at;
+	`, name).Scan(&org.ID, &org.CreatedAt)
+	if err != nil {
+		return model.Organization{}, err
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO memberships (org_id, user_id, role)
+		VALUES ($1, $2, $3);
+	`, org.ID, ownerID, model.OrgRoleOwner)
+	if err != nil {
+		return model.Organization{}, err
+	}
+
+	return org, tx.Commit()
+}
+
+// GetUserOrganizations -- Get the organizations of a user, the one joined first comes first
+func (r *organizationRepository) GetUserOrganizations(userID uint64) ([]model.UserOrganization, error) {
+	sqlQuery := `
+		SELECT o._id, o.name, o.created_at, m.role FROM memberships m
+		JOIN organizations o ON o._id = m.org_id
+		WHERE m.user_id = $1
+		ORDER BY m.joined_at, o._id;
+	`
+	rows, err := r.db.Query(sqlQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []model.UserOrganization{}
+	for rows.Next() {
+		var org model.UserOrganization
+		err = rows.Scan(
+			&org.ID,
+			&org.Name,
+			&org.CreatedAt,
+			&org.Role,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, org)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+const membershipQuery = `
+	SELECT m.org_id, m.user_id, u.email, m.role, m.joined_at FROM memberships m
+	JOIN users u ON u._id = m.user_id
+`
+
+func scanMembership(row rowScanner) (model.Membership, error) {
+	var membership model.Membership
+	err := row.Scan(
+		&membership.OrgID,
+		&membership.UserID,
+		&membership.Email,
+		&membership.Role,
+		&membership.JoinedAt,
+	)
+	return membership, err
+}
+
+// GetMembership -- Get the membership of a user in an organization, ErrNotMember is returned when there is none
+func (r *organizationRepository) GetMembership(orgID uint64, userID uint64) (model.Membership, error) {
+	membership, err := scanMembership(r.db.QueryRow(membershipQuery+`
+		WHERE m.org_id = $1 AND m.user_id = $2;
+	`, orgID, userID))
+	if err == sql.ErrNoRows {
+		return model.Membership{}, ErrNotMember
+	}
+	return membership, err
+}
+
+// GetMembers -- Get the members of an organization, oldest first
+func (r *organizationRepository) GetMembers(orgID uint64) ([]model.Membership, error) {
+	rows, err := r.db.Query(membershipQuery+`
+		WHERE m.org_id = $1
+		ORDER BY m.joined_at, m.user_id;
+	`, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []model.Membership{}
+	for rows.Next() {
+		membership, err := scanMembership(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, membership)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateInvite -- Store a hashed invite code for the email
+func (r *organizationRepository) CreateInvite(orgID uint64, email string, codeHash string, invitedBy uint64, ttl time.Duration) error {
+	_, err := r.db.Exec(`
+		INSERT INTO organization_invites (org_id, email, code_hash, invited_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5);
+	`, orgID, email, codeHash, invitedBy, time.Now().Add(ttl))
+	return err
+}
+
+// AcceptInvite -- Use up the invite sent to email and add the user to its organization,
+// returns the organization id or ErrInviteNotFound
+func (r *organizationRepository) AcceptInvite(email string, codeHash string, userID uint64) (uint64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var orgID uint64
+	err = tx.QueryRow(`
+		UPDATE organization_invites
+		SET accepted_at=NOW()
+		WHERE email=$1 AND code_hash=$2
+			AND accepted_at IS NULL AND expires_at > NOW()
+		RETURNING org_id
+	`, email, codeHash).Scan(&orgID)
+	if err == sql.ErrNoRows {
+		return 0, ErrInviteNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Accepting an invite to an organization the user already belongs to keeps the current role
+	_, err = tx.Exec(`
+		INSERT INTO memberships (org_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (org_id, user_id) DO NOTHING;
+	`, orgID, userID, model.OrgRoleMember)
+	if err != nil {
+		return 0, err
+	}
+
+	return orgID, tx.Commit()
+}
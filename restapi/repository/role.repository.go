@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"golang-restapi/model"
+)
+
+// ErrRoleNotFound -- Returned when the requested role doesn't exist
+var ErrRoleNotFound = errors.New("role not found")
+
+// ErrUserNotFound -- Returned when the requested user doesn't exist
+var ErrUserNotFound = errors.New("user not found")
+
+// RoleRepository -- Roles and the permissions they grant
+type RoleRepository interface {
+	GetRoleByUserID(userID uint64) (model.Role, error)
+	AssignRole(userID uint64, roleName string) error
+}
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+// InitializeRoleRepository -- Create a role repository on top of db
+func InitializeRoleRepository(db *sql.DB) RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+// GetRoleByUserID -- Get the role and permissions of a user
+func (r *roleRepository) GetRoleByUserID(userID uint64) (model.Role, error) {
+	sqlQuery := `
+		SELECT r._id, r.name, p.name FROM users u
+		JOIN roles r ON r._id = u.role_id
+		LEFT JOIN role_permissions rp ON rp.role_id = r._id
+		LEFT JOIN permissions p ON p._id = rp.permission_id
+		WHERE u._id = $1
+		ORDER BY p.name;
+	`
+	rows, err := r.db.Query(sqlQuery, userID)
+	if err != nil {
+		return model.Role{}, err
+	}
+	defer rows.Close()
+	var role model.Role
+	for rows.Next() {
+		var permission *string
+		err = rows.Scan(
+			&role.ID,
+			&role.Name,
+			&permission,
+		)
+		if err != nil {
+			return model.Role{}, err
+		}
+		if permission != nil {
+			role.Permissions = append(role.Permissions, *permission)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return model.Role{}, err
+	}
+	return role, nil
+}
+
+// AssignRole -- Change the role of a user
+func (r *roleRepository) AssignRole(userID uint64, roleName string) error {
+	var roleID uint64
+	err := r.db.QueryRow(`SELECT _id FROM roles WHERE name = $1`, roleName).Scan(&roleID)
+	if err == sql.ErrNoRows {
+		return ErrRoleNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	result, err := r.db.Exec(`UPDATE users SET role_id = $1 WHERE _id = $2`, roleID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
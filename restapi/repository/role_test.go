@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"golang-restapi/model"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// roleSuite -- Runs against the database in TEST_DATABASE_URL (a lib/pq connection string)
+// with sql/base.sql applied, and is skipped when it isn't set
+type roleSuite struct {
+	suite.Suite
+	db     *sql.DB
+	users  UserRepository
+	roles  RoleRepository
+	userID uint64
+}
+
+func (suite *roleSuite) SetupSuite() {
+	connStr := os.Getenv("TEST_DATABASE_URL")
+	if len(connStr) == 0 {
+		suite.T().Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", connStr)
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.Ping())
+	suite.db = db
+	suite.users = InitializeUserRepository(db)
+	suite.roles = InitializeRoleRepository(db)
+}
+
+func (suite *roleSuite) TearDownSuite() {
+	if suite.db != nil {
+		suite.db.Close()
+	}
+}
+
+func (suite *roleSuite) SetupTest() {
+	user := model.User{Email: fmt.Sprintf("role-%d@example.com", time.Now().UnixNano()), Password: "hash"}
+	suite.Require().NoError(suite.users.CreateUser(&user))
+	suite.userID = user.ID
+}
+
+func (suite *roleSuite) TearDownTest() {
+	suite.db.Exec(`DELETE FROM users WHERE _id = $1`, suite.userID)
+}
+
+func (suite *roleSuite) TestNewUserHasUserRole() {
+	role, err := suite.roles.GetRoleByUserID(suite.userID)
+	suite.NoError(err)
+	suite.Equal(model.RoleUser, role.Name)
+	suite.Contains(role.Permissions, model.PermissionServiceWriteOwn)
+	suite.NotContains(role.Permissions, model.PermissionUserRoleAssign)
+}
+
+func (suite *roleSuite) TestAssignRoleGrantsItsPermissions() {
+	suite.NoError(suite.roles.AssignRole(suite.userID, model.RoleAdmin))
+
+	role, err := suite.roles.GetRoleByUserID(suite.userID)
+	suite.NoError(err)
+	suite.Equal(model.RoleAdmin, role.Name)
+	suite.Contains(role.Permissions, model.PermissionUserRoleAssign)
+	suite.Contains(role.Permissions, model.PermissionServiceReadAny)
+}
+
+func (suite *roleSuite) TestAssignUnknownRole() {
+	suite.Equal(ErrRoleNotFound, suite.roles.AssignRole(suite.userID, "superuser"))
+
+	role, err := suite.roles.GetRoleByUserID(suite.userID)
+	suite.NoError(err)
+	suite.Equal(model.RoleUser, role.Name, "the role is left unchanged")
+}
+
+func (suite *roleSuite) TestAssignRoleToUnknownUser() {
+	suite.Equal(ErrUserNotFound, suite.roles.AssignRole(suite.userID+1000000, model.RoleAdmin))
+}
+
+func TestRoleRepository(t *testing.T) {
+	suite.Run(t, new(roleSuite))
+}
@@ -1,81 +1,287 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	"golang-restapi/config"
 	"golang-restapi/model"
+	"strings"
 )
 
-// CreateServiceRequest -- create service request
-func CreateServiceRequest(service *model.Service, userID uint64) {
+// ErrServiceNotFound -- Returned when the requested service request doesn't exist
+var ErrServiceNotFound = errors.New("service request not found")
+
+// ErrInvalidTransition -- Returned when a service request can't move from its current status to the requested one
+var ErrInvalidTransition = errors.New("invalid status transition")
+
+// ServiceRepository -- Service requests, their schedule and status history.
+// Every method is scoped to an organization, requests of other organizations look like they don't exist.
+type ServiceRepository interface {
+	CreateServiceRequest(data model.CreateServiceData, userID uint64, orgID uint64) (model.Service, error)
+	FindServices(filter model.ServiceFilter) ([]model.Service, error)
+	GetUpcomingServices(filter model.UpcomingFilter) ([]model.Service, error)
+	GetServiceByID(orgID uint64, serviceID uint64) (model.Service, error)
+	GetServiceHistory(orgID uint64, serviceID uint64) ([]model.ServiceStatusChange, error)
+	UpdateServiceStatus(orgID uint64, serviceID uint64, status string, changedBy uint64, note string) (model.Service, error)
+}
+
+type serviceRepository struct {
+	db *sql.DB
+}
+
+// InitializeServiceRepository -- Create a service repository on top of db
+func InitializeServiceRepository(db *sql.DB) ServiceRepository {
+	return &serviceRepository{
+		db: db,
+	}
+}
+
+const serviceColumns = `_id, request_id, status, vessel_name, service_type, data_agent, cargo, etd, eta, user_id, org_id`
+
+// rowScanner -- Implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanService(row rowScanner) (model.Service, error) {
+	var service model.Service
+	err := row.Scan(
+		&service.ID,
+		&service.RequestID,
+		&service.Status,
+		&service.VesselName,
+		&service.ServiceType,
+		&service.DataAgent,
+		&service.Cargo,
+		&service.ETD,
+		&service.ETA,
+		&service.UserID,
+		&service.OrgID,
+	)
+	return service, err
+}
+
+// CreateServiceRequest -- create service request, the request id and the initial status are set by the database
+func (r *serviceRepository) CreateServiceRequest(data model.CreateServiceData, userID uint64, orgID uint64) (model.Service, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Service{}, err
+	}
+	defer tx.Rollback()
+
 	sqlQuery := `
 		INSERT INTO services (
-			request_id, 
-			status, 
-			vessel_name, 
+			vessel_name,
 			service_type,
 			data_agent,
 			cargo,
 			etd,
 			eta,
-			user_id
+			user_id,
+			org_id
 		)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + serviceColumns + `;
 	`
-
-	_, err := config.DB.Exec(sqlQuery,
-		service.RequestID,
-		service.Status,
-		service.VesselName,
-		service.ServiceType,
-		service.DataAgent,
-		service.Cargo,
-		service.ETD,
-		service.ETA,
+	service, err := scanService(tx.QueryRow(sqlQuery,
+		data.VesselName,
+		data.ServiceType,
+		data.DataAgent,
+		data.Cargo,
+		data.ETD,
+		data.ETA,
 		userID,
-	)
+		orgID,
+	))
+	if err != nil {
+		return model.Service{}, err
+	}
+
+	err = insertStatusChange(tx, service.ID, nil, service.Status, userID, "")
 	if err != nil {
-		panic(err)
+		return model.Service{}, err
 	}
-	fmt.Printf("Success to create service request %v\n", *service)
+
+	return service, tx.Commit()
 }
 
-// GetServices -- Get all services by a user
-func GetServices(userID uint64) []model.Service {
+// FindServices -- Get the services of the filter's organization matching the filter, ordered by id
+func (r *serviceRepository) FindServices(filter model.ServiceFilter) ([]model.Service, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	addCondition("org_id = $%d", filter.OrgID)
+	if filter.UserID != nil {
+		addCondition("user_id = $%d", *filter.UserID)
+	}
+	if len(filter.VesselName) > 0 {
+		addCondition("vessel_name ILIKE $%d", "%"+escapeLike(filter.VesselName)+"%")
+	}
+	if len(filter.ServiceType) > 0 {
+		addCondition("LOWER(service_type) = LOWER($%d)", filter.ServiceType)
+	}
+	if len(filter.Cargo) > 0 {
+		addCondition("cargo ILIKE $%d", "%"+escapeLike(filter.Cargo)+"%")
+	}
+	if filter.From != nil {
+		addCondition("eta >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("etd <= $%d", *filter.To)
+	}
+
+	sqlQuery := `SELECT ` + serviceColumns + ` FROM services WHERE ` + strings.Join(conditions, " AND ")
+	args = append(args, filter.Limit, filter.Offset)
+	sqlQuery += fmt.Sprintf(` ORDER BY _id LIMIT $%d OFFSET $%d;`, len(args)-1, len(args))
+	return r.queryServices(sqlQuery, args...)
+}
+
+// GetUpcomingServices -- Get the active services of the filter's organization arriving or departing in its window, soonest first
+func (r *serviceRepository) GetUpcomingServices(filter model.UpcomingFilter) ([]model.Service, error) {
+	column := "eta"
+	if filter.Event == model.ScheduleDepartures {
+		column = "etd"
+	}
+	args := []interface{}{filter.OrgID, filter.Since, filter.Until, model.ServiceCancelled, model.ServiceCompleted}
+	sqlQuery := `SELECT ` + serviceColumns + ` FROM services
+		WHERE org_id = $1 AND ` + column + ` >= $2 AND ` + column + ` < $3 AND status NOT IN ($4, $5)`
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		sqlQuery += fmt.Sprintf(` AND user_id = $%d`, len(args))
+	}
+	args = append(args, filter.Limit, filter.Offset)
+	sqlQuery += fmt.Sprintf(` ORDER BY %s, _id LIMIT $%d OFFSET $%d;`, column, len(args)-1, len(args))
+	return r.queryServices(sqlQuery, args...)
+}
+
+// escapeLike -- Escape the wildcards of a LIKE pattern so user input is matched literally
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *serviceRepository) queryServices(sqlQuery string, args ...interface{}) ([]model.Service, error) {
+	rows, err := r.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result := []model.Service{}
+	for rows.Next() {
+		service, err := scanService(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, service)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetServiceByID -- Get a single service request of the organization
+func (r *serviceRepository) GetServiceByID(orgID uint64, serviceID uint64) (model.Service, error) {
+	sqlQuery := `
+		SELECT ` + serviceColumns + ` FROM services
+		WHERE _id = $1 AND org_id = $2;
+	`
+	service, err := scanService(r.db.QueryRow(sqlQuery, serviceID, orgID))
+	if err == sql.ErrNoRows {
+		return model.Service{}, ErrServiceNotFound
+	}
+	return service, err
+}
+
+// GetServiceHistory -- Get the status changes of a service request of the organization, oldest first
+func (r *serviceRepository) GetServiceHistory(orgID uint64, serviceID uint64) ([]model.ServiceStatusChange, error) {
 	sqlQuery := `
-		SELECT * FROM services
-		WHERE user_id = $1;
-		
+		SELECT h._id, h.service_id, h.from_status, h.to_status, h.changed_by, h.note, h.changed_at
+		FROM service_status_history h
+		JOIN services s ON s._id = h.service_id
+		WHERE h.service_id = $1 AND s.org_id = $2
+		ORDER BY h.changed_at, h._id;
 	`
-	rows, err := config.DB.Query(sqlQuery, userID)
+	rows, err := r.db.Query(sqlQuery, serviceID, orgID)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	defer rows.Close()
-	var result []model.Service
+	result := []model.ServiceStatusChange{}
 	for rows.Next() {
-		var service = model.Service{}
+		var change model.ServiceStatusChange
 		err = rows.Scan(
-			&service.ID,
-			&service.RequestID,
-			&service.Status,
-			&service.VesselName,
-			&service.ServiceType,
-			&service.DataAgent,
-			&service.Cargo,
-			&service.ETD,
-			&service.ETA,
-			&service.UserID,
+			&change.ID,
+			&change.ServiceID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.ChangedBy,
+			&change.Note,
+			&change.ChangedAt,
 		)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
-		result = append(result, service)
+		result = append(result, change)
 	}
 	err = rows.Err()
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
-	fmt.Println("GetServices result", result)
-	return result
+	return result, nil
+}
+
+// UpdateServiceStatus -- Move a service request to a new status and record who did it.
+// The row is locked while checking the transition so concurrent updates can't skip a state.
+func (r *serviceRepository) UpdateServiceStatus(orgID uint64, serviceID uint64, status string, changedBy uint64, note string) (model.Service, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return model.Service{}, err
+	}
+	defer tx.Rollback()
+
+	sqlQuery := `
+		SELECT ` + serviceColumns + ` FROM services
+		WHERE _id = $1 AND org_id = $2
+		FOR UPDATE;
+	`
+	service, err := scanService(tx.QueryRow(sqlQuery, serviceID, orgID))
+	if err == sql.ErrNoRows {
+		return model.Service{}, ErrServiceNotFound
+	}
+	if err != nil {
+		return model.Service{}, err
+	}
+
+	if !model.CanTransition(service.Status, status) {
+		return model.Service{}, ErrInvalidTransition
+	}
+
+	_, err = tx.Exec(`UPDATE services SET status = $1 WHERE _id = $2 AND org_id = $3`, status, serviceID, orgID)
+	if err != nil {
+		return model.Service{}, err
+	}
+
+	from := service.Status
+	err = insertStatusChange(tx, serviceID, &from, status, changedBy, note)
+	if err != nil {
+		return model.Service{}, err
+	}
+
+	service.Status = status
+	return service, tx.Commit()
+}
+
+func insertStatusChange(tx *sql.Tx, serviceID uint64, from *string, to string, changedBy uint64, note string) error {
+	sqlQuery := `
+		INSERT INTO service_status_history (service_id, from_status, to_status, changed_by, note)
+		VALUES ($1, $2, $3, $4, $5);
+	`
+	_, err := tx.Exec(sqlQuery, serviceID, from, to, changedBy, note)
+	return err
 }
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"golang-restapi/model"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+// tenantSuite -- Runs against the database in TEST_DATABASE_URL (a lib/pq connection string)
+// with sql/base.sql applied, and is skipped when it isn't set
+type tenantSuite struct {
+	suite.Suite
+	db             *sql.DB
+	users          UserRepository
+	organizations  OrganizationRepository
+	services       ServiceRepository
+	userIDs        []uint64
+	orgA, orgB     uint64
+	ownerA, ownerB uint64
+	service        model.Service
+}
+
+func (suite *tenantSuite) SetupSuite() {
+	connStr := os.Getenv("TEST_DATABASE_URL")
+	if len(connStr) == 0 {
+		suite.T().Skip("TEST_DATABASE_URL is not set")
+	}
+	db, err := sql.Open("postgres", connStr)
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.Ping())
+	suite.db = db
+	suite.users = InitializeUserRepository(db)
+	suite.organizations = InitializeOrganizationRepository(db)
+	suite.services = InitializeServiceRepository(db)
+}
+
+func (suite *tenantSuite) TearDownSuite() {
+	if suite.db != nil {
+		suite.db.Close()
+	}
+}
+
+func (suite *tenantSuite) SetupTest() {
+	suite.ownerA = suite.createUser("a")
+	suite.ownerB = suite.createUser("b")
+	orgA, err := suite.organizations.CreateOrganization("Tenant A", suite.ownerA)
+	suite.Require().NoError(err)
+	orgB, err := suite.organizations.CreateOrganization("Tenant B", suite.ownerB)
+	suite.Require().NoError(err)
+	suite.orgA, suite.orgB = orgA.ID, orgB.ID
+
+	etd := time.Now().Add(time.Hour)
+	suite.service, err = suite.services.CreateServiceRequest(model.CreateServiceData{
+		VesselName:  "Tenant A vessel",
+		ServiceType: "bunkering",
+		DataAgent:   "agent",
+		Cargo:       "oil",
+		ETD:         etd,
+		ETA:         etd.Add(2 * time.Hour),
+	}, suite.ownerA, suite.orgA)
+	suite.Require().NoError(err)
+}
+
+func (suite *tenantSuite) TearDownTest() {
+	suite.db.Exec(`DELETE FROM organizations WHERE _id IN ($1, $2)`, suite.orgA, suite.orgB)
+	for _, userID := range suite.userIDs {
+		suite.db.Exec(`DELETE FROM users WHERE _id = $1`, userID)
+	}
+	suite.userIDs = nil
+}
+
+func (suite *tenantSuite) createUser(name string) uint64 {
+	user := model.User{Email: fmt.Sprintf("tenant-%s-%d@example.com", name, time.Now().UnixNano()), Password: "hash"}
+	suite.Require().NoError(suite.users.CreateUser(&user))
+	suite.userIDs = append(suite.userIDs, user.ID)
+	return user.ID
+}
+
+func (suite *tenantSuite) TestOwnOrganizationSeesService() {
+	service, err := suite.services.GetServiceByID(suite.orgA, suite.service.ID)
+	suite.NoError(err)
+	suite.Equal(suite.orgA, service.OrgID)
+
+	history, err := suite.services.GetServiceHistory(suite.orgA, suite.service.ID)
+	suite.NoError(err)
+	suite.Len(history, 1)
+}
+
+func (suite *tenantSuite) TestOtherOrganizationCannotRead() {
+	_, err := suite.services.GetServiceByID(suite.orgB, suite.service.ID)
+	suite.Equal(ErrServiceNotFound, err)
+
+	history, err := suite.services.GetServiceHistory(suite.orgB, suite.service.ID)
+	suite.NoError(err)
+	suite.Empty(history)
+
+	services, err := suite.services.FindServices(model.ServiceFilter{OrgID: suite.orgB, VesselName: "Tenant A vessel", Limit: 100})
+	suite.NoError(err)
+	suite.Empty(services)
+
+	services, err = suite.services.GetUpcomingServices(model.UpcomingFilter{
+		OrgID: suite.orgB,
+		Event: model.ScheduleDepartures,
+		Since: time.Now(),
+		Until: time.Now().Add(24 * time.Hour),
+		Limit: 100,
+	})
+	suite.NoError(err)
+	suite.Empty(services)
+}
+
+func (suite *tenantSuite) TestOtherOrganizationCannotModify() {
+	_, err := suite.services.UpdateServiceStatus(suite.orgB, suite.service.ID, model.ServiceCancelled, suite.ownerB, "")
+	suite.Equal(ErrServiceNotFound, err)
+
+	service, err := suite.services.GetServiceByID(suite.orgA, suite.service.ID)
+	suite.NoError(err)
+	suite.Equal(model.ServiceRequested, service.Status)
+
+	history, err := suite.services.GetServiceHistory(suite.orgA, suite.service.ID)
+	suite.NoError(err)
+	suite.Len(history, 1, "no status change was recorded")
+}
+
+func (suite *tenantSuite) TestInviteOnlyJoinsInvitedEmail() {
+	codeHash := strings.Repeat("a", 64)
+	suite.Require().NoError(suite.organizations.CreateInvite(suite.orgA, "invited@example.com", codeHash, suite.ownerA, time.Hour))
+
+	_, err := suite.organizations.AcceptInvite("someone-else@example.com", codeHash, suite.ownerB)
+	suite.Equal(ErrInviteNotFound, err, "the code only works for the invited email")
+	_, err = suite.organizations.GetMembership(suite.orgA, suite.ownerB)
+	suite.Equal(ErrNotMember, err)
+
+	orgID, err := suite.organizations.AcceptInvite("invited@example.com", codeHash, suite.ownerB)
+	suite.NoError(err)
+	suite.Equal(suite.orgA, orgID)
+	_, err = suite.organizations.AcceptInvite("invited@example.com", codeHash, suite.ownerB)
+	suite.Equal(ErrInviteNotFound, err, "invites are single use")
+
+	membership, err := suite.organizations.GetMembership(suite.orgA, suite.ownerB)
+	suite.NoError(err)
+	suite.Equal(model.OrgRoleMember, membership.Role)
+}
+
+func TestTenantIsolation(t *testing.T) {
+	suite.Run(t, new(tenantSuite))
+}
@@ -1,81 +1,82 @@
 package repository
 
 import (
-	"fmt"
-	"golang-restapi/config"
+	"database/sql"
+	"errors"
 	"golang-restapi/model"
+
+	"github.com/lib/pq"
 )
 
-// CreateUser -- Create user
-func CreateUser(user *model.User) bool {
+// ErrEmailTaken -- Returned when another user already registered with the email
+var ErrEmailTaken = errors.New("email has been used")
+
+// UserRepository -- Users and their credentials
+type UserRepository interface {
+	CreateUser(user *model.User) error
+	GetUserByEmail(email string) (model.User, error)
+	GetUserByID(userID uint64) (model.User, error)
+	ConfirmAccount(userID uint64) error
+	ChangePassword(email string, newPassword string) error
+	DeleteUser(userID uint64) error
+}
+
+type userRepository struct {
+	db *sql.DB
+}
+
+// InitializeUserRepository -- Create a user repository on top of db
+func InitializeUserRepository(db *sql.DB) UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}
+
+// CreateUser -- Create user with the default role, the generated id is set on user
+func (r *userRepository) CreateUser(user *model.User) error {
 	sqlQuery := `
-		INSERT INTO users (email, password, uuid) 
-		VALUES ($1, $2, $3);
+		INSERT INTO users (email, password, role_id)
+		VALUES ($1, $2, (SELECT _id FROM roles WHERE name = $3))
+		RETURNING _id;
 	`
 
-	_, err := config.DB.Exec(sqlQuery, user.Email, user.Password, user.UUID)
-	if err != nil {
-		// panic(err)
-		return false
+	err := r.db.QueryRow(sqlQuery, user.Email, user.Password, model.RoleUser).Scan(&user.ID)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrEmailTaken
 	}
-	fmt.Printf("Success to create user %v\n", *user)
-	return true
+	return err
 }
 
-// GetUserByEmail -- Get user by Email
-func GetUserByEmail(email string) (model.User, error) {
+// GetUserByEmail -- Get user by Email, the zero user is returned when there is none
+func (r *userRepository) GetUserByEmail(email string) (model.User, error) {
 	sqlQuery := `
-		SELECT _id, email, password, uuid, confirmed FROM users
+		SELECT _id, email, password, confirmed FROM users
 		WHERE email = $1;
 	`
-	rows, err := config.DB.Query(sqlQuery, email)
-	if err != nil {
-		return model.User{}, err
-	}
-	defer rows.Close()
-	var user model.User
-	for rows.Next() {
-		err = rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.Password,
-			&user.UUID,
-			&user.Confirmed,
-		)
-		if err != nil {
-			return model.User{}, err
-		}
-	}
-	err = rows.Err()
-	if err != nil {
-		return model.User{}, err
-	}
-	return user, nil
+	return r.getUser(sqlQuery, email)
 }
 
-// GetUserByID -- Get user data
-func GetUserByID(userID uint64) (model.User, error) {
+// GetUserByID -- Get user data, the zero user is returned when there is none
+func (r *userRepository) GetUserByID(userID uint64) (model.User, error) {
 	sqlQuery := `
-		SELECT _id, email, password FROM users
-		WHERE _id = $1
+		SELECT _id, email, password, confirmed FROM users
+		WHERE _id = $1;
 	`
-	rows, err := config.DB.Query(sqlQuery, userID)
-	if err != nil {
-		return model.User{}, err
-	}
-	defer rows.Close()
+	return r.getUser(sqlQuery, userID)
+}
+
+func (r *userRepository) getUser(sqlQuery string, arg interface{}) (model.User, error) {
 	var user model.User
-	for rows.Next() {
-		err = rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.Password,
-		)
-		if err != nil {
-			return model.User{}, err
-		}
+	err := r.db.QueryRow(sqlQuery, arg).Scan(
+		&user.ID,
+		&user.Email,
+		&user.Password,
+		&user.Confirmed,
+	)
+	if err == sql.ErrNoRows {
+		return model.User{}, nil
 	}
-	err = rows.Err()
 	if err != nil {
 		return model.User{}, err
 	}
@@ -83,46 +84,35 @@ func GetUserByID(userID uint64) (model.User, error) {
 }
 
 // ConfirmAccount --  Update confirm field
-func ConfirmAccount(userID uint64) (bool, error) {
+func (r *userRepository) ConfirmAccount(userID uint64) error {
 	sqlQuery := `
 		UPDATE users
 		SET confirmed=TRUE
 		WHERE _id=$1
 	`
 
-	_, err := config.DB.Query(sqlQuery, userID)
-	if err != nil {
-		return false, err
-	}
-	return true, nil
+	_, err := r.db.Exec(sqlQuery, userID)
+	return err
 }
 
-// NewUUID --
-func NewUUID(uuid, email string) (bool, error) {
+// ChangePassword -- Forgot Password
+func (r *userRepository) ChangePassword(email string, newPassword string) error {
 	sqlQuery := `
 		UPDATE users
-		SET uuid=$1
+		SET password=$1
 		WHERE email=$2
 	`
 
-	_, err := config.DB.Query(sqlQuery, uuid, email)
-	if err != nil {
-		return false, err
-	}
-	return true, nil
+	_, err := r.db.Exec(sqlQuery, newPassword, email)
+	return err
 }
 
-// ChangePassword -- Forgot Password
-func ChangePassword(email string, newPassword string) (bool, error) {
+// DeleteUser -- Delete the user, its verification codes are deleted with it
+func (r *userRepository) DeleteUser(userID uint64) error {
 	sqlQuery := `
-		UPDATE users
-		SET password=$1
-		WHERE email=$2
+		DELETE FROM users
+		WHERE _id = $1;
 	`
-
-	_, err := config.DB.Query(sqlQuery, newPassword, email)
-	if err != nil {
-		return false, err
-	}
-	return true, nil
+	_, err := r.db.Exec(sqlQuery, userID)
+	return err
 }
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+)
+
+// VerificationRepository -- Hashed single-use codes confirming accounts and password resets
+type VerificationRepository interface {
+	CreateVerificationCode(userID uint64, purpose, codeHash string, ttl time.Duration) error
+	ConsumeVerificationCode(userID uint64, purpose, codeHash string) (bool, error)
+}
+
+type verificationRepository struct {
+	db *sql.DB
+}
+
+// InitializeVerificationRepository -- Create a verification code repository on top of db
+func InitializeVerificationRepository(db *sql.DB) VerificationRepository {
+	return &verificationRepository{
+		db: db,
+	}
+}
+
+// CreateVerificationCode -- Store a hashed code, previous unused codes with the same purpose stop working
+func (r *verificationRepository) CreateVerificationCode(userID uint64, purpose, codeHash string, ttl time.Duration) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`
+		UPDATE verification_codes
+		SET used_at=NOW()
+		WHERE user_id=$1 AND purpose=$2 AND used_at IS NULL
+	`, userID, purpose)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	_, err = tx.Exec(`
+		INSERT INTO verification_codes (user_id, purpose, code_hash, expires_at)
+		VALUES ($1, $2, $3, $4);
+	`, userID, purpose, codeHash, time.Now().Add(ttl))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConsumeVerificationCode -- Mark a valid code as used, returns false when it's unknown, used or expired
+func (r *verificationRepository) ConsumeVerificationCode(userID uint64, purpose, codeHash string) (bool, error) {
+	var id uint64
+	err := r.db.QueryRow(`
+		UPDATE verification_codes
+		SET used_at=NOW()
+		WHERE user_id=$1 AND purpose=$2 AND code_hash=$3
+			AND used_at IS NULL AND expires_at > NOW()
+		RETURNING _id
+	`, userID, purpose, codeHash).Scan(&id)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
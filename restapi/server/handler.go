@@ -0,0 +1,21 @@
+package server
+
+import "golang-restapi/handler"
+
+// Handlers -- Every handler of the app
+type Handlers struct {
+	AuthHandler         handler.AuthHandler
+	UserHandler         handler.UserHandler
+	ServiceHandler      handler.ServiceHandler
+	OrganizationHandler handler.OrganizationHandler
+}
+
+// SetupHandlers -- Create the handlers on top of the usecases
+func SetupHandlers(uscs *Usecases) *Handlers {
+	return &Handlers{
+		AuthHandler:         handler.InitializeAuthHandler(uscs.AuthUsecase),
+		UserHandler:         handler.InitializeUserHandler(uscs.UserUsecase),
+		ServiceHandler:      handler.InitializeServiceHandler(uscs.ServiceUsecase),
+		OrganizationHandler: handler.InitializeOrganizationHandler(uscs.OrganizationUsecase),
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import (
+	"database/sql"
+	"golang-restapi/repository"
+)
+
+// Repositories -- Every repository of the app
+type Repositories struct {
+	UserRepository         repository.UserRepository
+	RoleRepository         repository.RoleRepository
+	VerificationRepository repository.VerificationRepository
+	ServiceRepository      repository.ServiceRepository
+	OrganizationRepository repository.OrganizationRepository
+}
+
+// SetupRepositories -- Create the repositories on top of db
+func SetupRepositories(db *sql.DB) *Repositories {
+	return &Repositories{
+		UserRepository:         repository.InitializeUserRepository(db),
+		RoleRepository:         repository.InitializeRoleRepository(db),
+		VerificationRepository: repository.InitializeVerificationRepository(db),
+		ServiceRepository:      repository.InitializeServiceRepository(db),
+		OrganizationRepository: repository.InitializeOrganizationRepository(db),
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"golang-restapi/config"
+	"golang-restapi/middleware"
+	"golang-restapi/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes -- Mount every route of the app on router
+func RegisterRoutes(r *gin.Engine, h *Handlers) {
+	// Routes for authentication
+	authRoute := r.Group("/auth")
+	{
+		authRoute.POST("/register", h.AuthHandler.Register)
+		authRoute.POST("/login", h.AuthHandler.Login)
+		authRoute.POST("/confirm", h.AuthHandler.ConfirmAccount)
+		authRoute.POST("/confirm/resend", h.AuthHandler.ResendConfirmation)
+		authRoute.POST("/forgot-password", h.AuthHandler.RequestPassword)
+		authRoute.POST("/forgot-password/confirm", h.AuthHandler.ChangePassword)
+	}
+
+	// Routes for User
+	userRoute := r.Group("/user")
+	userRoute.Use(middleware.AuthMiddleware())
+	{
+		userRoute.GET("/", middleware.RequirePermission(model.PermissionUserReadOwn), h.UserHandler.GetUserData)
+	}
+
+	// Routes for user management
+	adminRoute := r.Group("/admin")
+	adminRoute.Use(middleware.AuthMiddleware())
+	{
+		adminRoute.PUT("/user/:id/role", middleware.RequirePermission(model.PermissionUserRoleAssign), h.UserHandler.AssignRole)
+		adminRoute.GET("/services", middleware.RequirePermission(model.PermissionServiceReadAny), h.ServiceHandler.GetAllServices)
+	}
+
+	// Routes for organizations, service requests are scoped to the organization of the token
+	orgRoute := r.Group("/org")
+	orgRoute.Use(middleware.AuthMiddleware())
+	{
+		orgRoute.POST("/", middleware.RequirePermission(model.PermissionOrgWriteOwn), h.OrganizationHandler.CreateOrganization)
+		orgRoute.GET("/", middleware.RequirePermission(model.PermissionOrgReadOwn), h.OrganizationHandler.GetOrganizations)
+		orgRoute.GET("/members", middleware.RequirePermission(model.PermissionOrgReadOwn), h.OrganizationHandler.GetMembers)
+		orgRoute.POST("/invites", middleware.RequirePermission(model.PermissionOrgWriteOwn), h.OrganizationHandler.Invite)
+		orgRoute.POST("/invites/accept", middleware.RequirePermission(model.PermissionOrgWriteOwn), h.OrganizationHandler.AcceptInvite)
+		orgRoute.POST("/switch/:id", middleware.RequirePermission(model.PermissionOrgReadOwn), h.OrganizationHandler.SwitchOrganization)
+	}
+
+	// Routes for Service
+	serviceRoute := r.Group("/service")
+	serviceRoute.Use(middleware.AuthMiddleware())
+	{
+		serviceRoute.POST("/", middleware.RequirePermission(model.PermissionServiceWriteOwn), h.ServiceHandler.CreateServiceRequest)
+		serviceRoute.GET("/", middleware.RequirePermission(model.PermissionServiceReadOwn), h.ServiceHandler.GetServices)
+		serviceRoute.GET("/:id", middleware.RequirePermission(model.PermissionServiceReadOwn), h.ServiceHandler.GetServiceByID)
+		serviceRoute.PATCH("/:id/status", middleware.RequirePermission(model.PermissionServiceWriteOwn), h.ServiceHandler.UpdateServiceStatus)
+	}
+
+	// Routes for the vessel schedule
+	scheduleRoute := r.Group("/schedule")
+	scheduleRoute.Use(middleware.AuthMiddleware())
+	{
+		scheduleRoute.GET("/arrivals", middleware.RequirePermission(model.PermissionServiceReadOwn), h.ServiceHandler.GetUpcomingArrivals)
+		scheduleRoute.GET("/departures", middleware.RequirePermission(model.PermissionServiceReadOwn), h.ServiceHandler.GetUpcomingDepartures)
+	}
+}
+
+// SetupServer -- Connect to the database, mailer and rate limiter and wire every layer into a router
+func SetupServer() *gin.Engine {
+	r := gin.Default()
+
+	db := config.ConnectDB()
+	config.BootstrapAdmin(db)
+	repos := SetupRepositories(db)
+	uscs := SetupUsecases(repos, config.NewMailer(), config.NewLoginLimiter())
+	hndlrs := SetupHandlers(uscs)
+
+	RegisterRoutes(r, hndlrs)
+
+	return r
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"golang-restapi/mailer"
+	"golang-restapi/usecase"
+	"ratelimit"
+)
+
+// Usecases -- Every usecase of the app
+type Usecases struct {
+	AuthUsecase         usecase.AuthUsecase
+	UserUsecase         usecase.UserUsecase
+	ServiceUsecase      usecase.ServiceUsecase
+	OrganizationUsecase usecase.OrganizationUsecase
+}
+
+// SetupUsecases -- Create the usecases on top of the repositories
+func SetupUsecases(repos *Repositories, m mailer.Mailer, loginLimiter *ratelimit.Limiter) *Usecases {
+	return &Usecases{
+		AuthUsecase:         usecase.InitializeAuthUsecase(repos.UserRepository, repos.RoleRepository, repos.VerificationRepository, repos.OrganizationRepository, m, loginLimiter),
+		UserUsecase:         usecase.InitializeUserUsecase(repos.UserRepository, repos.RoleRepository),
+		ServiceUsecase:      usecase.InitializeServiceUsecase(repos.ServiceRepository),
+		OrganizationUsecase: usecase.InitializeOrganizationUsecase(repos.OrganizationRepository, repos.UserRepository, repos.RoleRepository, m),
+	}
+}
@@ -1,23 +1,132 @@
+-- Create roles table
+CREATE TABLE roles (
+	_id SERIAL PRIMARY KEY,
+	name VARCHAR(64) UNIQUE NOT NULL
+);
+
+-- Create permissions table
+CREATE TABLE permissions (
+	_id SERIAL PRIMARY KEY,
+	name VARCHAR(128) UNIQUE NOT NULL
+);
+
+-- Create role_permissions table
+CREATE TABLE role_permissions (
+	role_id INT NOT NULL,
+	permission_id INT NOT NULL,
+	PRIMARY KEY (role_id, permission_id),
+	FOREIGN KEY (role_id) REFERENCES roles(_id) ON DELETE CASCADE,
+	FOREIGN KEY (permission_id) REFERENCES permissions(_id) ON DELETE CASCADE
+);
+
+-- Seed default roles and permissions
+INSERT INTO roles (name) VALUES ('user'), ('admin');
+INSERT INTO permissions (name) VALUES
+	('user:read:own'),
+	('user:role:assign'),
+	('service:read:own'),
+	('service:read:any'),
+	('service:write:own'),
+	('service:status:any'),
+	('org:read:own'),
+	('org:write:own');
+INSERT INTO role_permissions (role_id, permission_id)
+	SELECT r._id, p._id FROM roles r, permissions p
+	WHERE r.name = 'user' AND p.name IN ('user:read:own', 'service:read:own', 'service:write:own', 'org:read:own', 'org:write:own');
+INSERT INTO role_permissions (role_id, permission_id)
+	SELECT r._id, p._id FROM roles r, permissions p
+	WHERE r.name = 'admin';
+
 -- Create users Table
 CREATE TABLE users (
     _id SERIAL PRIMARY KEY,
     email VARCHAR(256) UNIQUE NOT NULL,
     password VARCHAR(256) NOT NULL,
-	uuid VARCHAR(256),
-	confirmed BOOLEAN DEFAULT FALSE
+	confirmed BOOLEAN DEFAULT FALSE,
+	role_id INT NOT NULL,
+	FOREIGN KEY (role_id) REFERENCES roles(_id)
+);
+
+-- Create verification_codes table, codes are stored as SHA-256 hashes
+CREATE TABLE verification_codes (
+	_id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	purpose VARCHAR(32) NOT NULL,
+	code_hash CHAR(64) NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL,
+	used_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (user_id) REFERENCES users(_id) ON DELETE CASCADE
 );
+CREATE INDEX verification_codes_lookup ON verification_codes (user_id, purpose, code_hash);
 
--- Create services table
+-- Create organizations table, service requests belong to an organization
+CREATE TABLE organizations (
+	_id SERIAL PRIMARY KEY,
+	name VARCHAR(256) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Create memberships table, the owner of an organization can invite other users
+CREATE TABLE memberships (
+	org_id INT NOT NULL,
+	user_id INT NOT NULL,
+	role VARCHAR(32) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+	joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (org_id, user_id),
+	FOREIGN KEY (org_id) REFERENCES organizations(_id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(_id) ON DELETE CASCADE
+);
+CREATE INDEX memberships_user_id ON memberships (user_id, joined_at);
+
+-- Create organization_invites table, codes are stored as SHA-256 hashes
+CREATE TABLE organization_invites (
+	_id SERIAL PRIMARY KEY,
+	org_id INT NOT NULL,
+	email VARCHAR(256) NOT NULL,
+	code_hash CHAR(64) NOT NULL,
+	invited_by INT NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL,
+	accepted_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (org_id) REFERENCES organizations(_id) ON DELETE CASCADE,
+	FOREIGN KEY (invited_by) REFERENCES users(_id) ON DELETE CASCADE
+);
+CREATE INDEX organization_invites_lookup ON organization_invites (email, code_hash);
+
+-- Create services table, request ids are generated by the server
+CREATE SEQUENCE service_request_id_seq START WITH 100000;
 CREATE TABLE services (
 	_id SERIAL PRIMARY KEY,
-	request_id INT UNIQUE NOT NULL,
-	status VARCHAR(64) NOT NULL,
+	request_id INT UNIQUE NOT NULL DEFAULT nextval('service_request_id_seq'),
+	status VARCHAR(64) NOT NULL DEFAULT 'requested'
+		CHECK (status IN ('requested', 'approved', 'in_progress', 'completed', 'cancelled')),
 	vessel_name VARCHAR(256) NOT NULL,
 	service_type VARCHAR(256) NOT NULL,
 	data_agent VARCHAR(256) NOT NULL,
 	cargo VARCHAR(256) NOT NULL,
-	etd VARCHAR(256) NOT NULL,
-	eta VARCHAR(256) NOT NULL,
+	etd TIMESTAMPTZ NOT NULL,
+	eta TIMESTAMPTZ NOT NULL,
     user_id INT NOT NULL,
-    FOREIGN KEY (user_id) REFERENCES users(_id) ON DELETE CASCADE
-);
\ No newline at end of file
+	org_id INT NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(_id) ON DELETE CASCADE,
+	FOREIGN KEY (org_id) REFERENCES organizations(_id) ON DELETE CASCADE,
+	CONSTRAINT services_eta_after_etd CHECK (eta > etd)
+);
+CREATE INDEX services_org_id ON services (org_id, _id);
+CREATE INDEX services_etd ON services (etd);
+CREATE INDEX services_eta ON services (eta);
+
+-- Create service_status_history table, one row per status change of a service request
+CREATE TABLE service_status_history (
+	_id SERIAL PRIMARY KEY,
+	service_id INT NOT NULL,
+	from_status VARCHAR(64),
+	to_status VARCHAR(64) NOT NULL,
+	changed_by INT NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (service_id) REFERENCES services(_id) ON DELETE CASCADE,
+	FOREIGN KEY (changed_by) REFERENCES users(_id) ON DELETE CASCADE
+);
+CREATE INDEX service_status_history_service_id ON service_status_history (service_id, changed_at);
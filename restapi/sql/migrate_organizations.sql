@@ -0,0 +1,60 @@
+-- Scope service requests to organizations.
+-- Every user who already has service requests gets a personal organization they own,
+-- and their requests are moved into it.
+BEGIN;
+
+INSERT INTO permissions (name) VALUES ('org:read:own'), ('org:write:own');
+INSERT INTO role_permissions (role_id, permission_id)
+	SELECT r._id, p._id FROM roles r, permissions p
+	WHERE r.name IN ('user', 'admin') AND p.name IN ('org:read:own', 'org:write:own');
+
+CREATE TABLE organizations (
+	_id SERIAL PRIMARY KEY,
+	name VARCHAR(256) NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE memberships (
+	org_id INT NOT NULL,
+	user_id INT NOT NULL,
+	role VARCHAR(32) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'member')),
+	joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	PRIMARY KEY (org_id, user_id),
+	FOREIGN KEY (org_id) REFERENCES organizations(_id) ON DELETE CASCADE,
+	FOREIGN KEY (user_id) REFERENCES users(_id) ON DELETE CASCADE
+);
+CREATE INDEX memberships_user_id ON memberships (user_id, joined_at);
+
+CREATE TABLE organization_invites (
+	_id SERIAL PRIMARY KEY,
+	org_id INT NOT NULL,
+	email VARCHAR(256) NOT NULL,
+	code_hash CHAR(64) NOT NULL,
+	invited_by INT NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL,
+	accepted_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (org_id) REFERENCES organizations(_id) ON DELETE CASCADE,
+	FOREIGN KEY (invited_by) REFERENCES users(_id) ON DELETE CASCADE
+);
+CREATE INDEX organization_invites_lookup ON organization_invites (email, code_hash);
+
+-- Remember which user each personal organization was created for while backfilling
+ALTER TABLE organizations ADD COLUMN personal_for INT;
+INSERT INTO organizations (name, personal_for)
+	SELECT u.email, u._id FROM users u
+	WHERE EXISTS (SELECT 1 FROM services s WHERE s.user_id = u._id);
+INSERT INTO memberships (org_id, user_id, role)
+	SELECT _id, personal_for, 'owner' FROM organizations;
+
+ALTER TABLE services ADD COLUMN org_id INT;
+UPDATE services s SET org_id = o._id
+	FROM organizations o WHERE o.personal_for = s.user_id;
+ALTER TABLE organizations DROP COLUMN personal_for;
+
+ALTER TABLE services
+	ALTER COLUMN org_id SET NOT NULL,
+	ADD FOREIGN KEY (org_id) REFERENCES organizations(_id) ON DELETE CASCADE;
+CREATE INDEX services_org_id ON services (org_id, _id);
+
+COMMIT;
@@ -0,0 +1,44 @@
+-- Add roles and permissions to a database created before they existed.
+-- Every existing user gets the 'user' role; promote the first admin by
+-- setting ADMIN_EMAIL when starting the server.
+BEGIN;
+
+CREATE TABLE roles (
+	_id SERIAL PRIMARY KEY,
+	name VARCHAR(64) UNIQUE NOT NULL
+);
+
+CREATE TABLE permissions (
+	_id SERIAL PRIMARY KEY,
+	name VARCHAR(128) UNIQUE NOT NULL
+);
+
+CREATE TABLE role_permissions (
+	role_id INT NOT NULL,
+	permission_id INT NOT NULL,
+	PRIMARY KEY (role_id, permission_id),
+	FOREIGN KEY (role_id) REFERENCES roles(_id) ON DELETE CASCADE,
+	FOREIGN KEY (permission_id) REFERENCES permissions(_id) ON DELETE CASCADE
+);
+
+INSERT INTO roles (name) VALUES ('user'), ('admin');
+INSERT INTO permissions (name) VALUES
+	('user:read:own'),
+	('user:role:assign'),
+	('service:read:own'),
+	('service:read:any'),
+	('service:write:own');
+INSERT INTO role_permissions (role_id, permission_id)
+	SELECT r._id, p._id FROM roles r, permissions p
+	WHERE r.name = 'user' AND p.name IN ('user:read:own', 'service:read:own', 'service:write:own');
+INSERT INTO role_permissions (role_id, permission_id)
+	SELECT r._id, p._id FROM roles r, permissions p
+	WHERE r.name = 'admin';
+
+ALTER TABLE users ADD COLUMN role_id INT;
+UPDATE users SET role_id = (SELECT _id FROM roles WHERE name = 'user');
+ALTER TABLE users
+	ALTER COLUMN role_id SET NOT NULL,
+	ADD FOREIGN KEY (role_id) REFERENCES roles(_id);
+
+COMMIT;
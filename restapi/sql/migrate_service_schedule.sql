@@ -0,0 +1,16 @@
+-- Migrate services.etd and services.eta from free text to timestamptz.
+-- Values must already be parseable timestamps, rows that aren't will make the migration fail
+-- so they can be fixed by hand instead of being silently lost.
+BEGIN;
+
+ALTER TABLE services
+	ALTER COLUMN etd TYPE TIMESTAMPTZ USING etd::timestamptz,
+	ALTER COLUMN eta TYPE TIMESTAMPTZ USING eta::timestamptz;
+
+ALTER TABLE services
+	ADD CONSTRAINT services_eta_after_etd CHECK (eta > etd);
+
+CREATE INDEX services_etd ON services (etd);
+CREATE INDEX services_eta ON services (eta);
+
+COMMIT;
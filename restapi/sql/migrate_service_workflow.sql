@@ -0,0 +1,49 @@
+-- Move service requests to the status workflow.
+-- Request ids are generated by the server from now on and legacy free-text statuses
+-- are mapped onto the workflow, each existing request gets its first history entry.
+BEGIN;
+
+INSERT INTO permissions (name) VALUES ('service:status:any');
+INSERT INTO role_permissions (role_id, permission_id)
+	SELECT r._id, p._id FROM roles r, permissions p
+	WHERE r.name = 'admin' AND p.name = 'service:status:any';
+
+CREATE SEQUENCE service_request_id_seq START WITH 100000;
+SELECT setval('service_request_id_seq', GREATEST(100000, (SELECT MAX(request_id) + 1 FROM services)), false);
+ALTER TABLE services ALTER COLUMN request_id SET DEFAULT nextval('service_request_id_seq');
+ALTER SEQUENCE service_request_id_seq OWNED BY services.request_id;
+
+CREATE TABLE service_status_history (
+	_id SERIAL PRIMARY KEY,
+	service_id INT NOT NULL,
+	from_status VARCHAR(64),
+	to_status VARCHAR(64) NOT NULL,
+	changed_by INT NOT NULL,
+	note TEXT NOT NULL DEFAULT '',
+	changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (service_id) REFERENCES services(_id) ON DELETE CASCADE,
+	FOREIGN KEY (changed_by) REFERENCES users(_id) ON DELETE CASCADE
+);
+CREATE INDEX service_status_history_service_id ON service_status_history (service_id, changed_at);
+
+-- Map legacy free-text statuses, keeping the original text in the first history entry
+WITH mapped AS (
+	UPDATE services s SET status = CASE
+		WHEN lower(trim(legacy.status)) IN ('approved', 'accepted', 'confirmed') THEN 'approved'
+		WHEN lower(trim(legacy.status)) IN ('in_progress', 'in progress', 'in-progress', 'ongoing', 'processing', 'started') THEN 'in_progress'
+		WHEN lower(trim(legacy.status)) IN ('completed', 'complete', 'done', 'finished', 'closed') THEN 'completed'
+		WHEN lower(trim(legacy.status)) IN ('cancelled', 'canceled', 'rejected', 'declined') THEN 'cancelled'
+		ELSE 'requested'
+	END
+	FROM services legacy
+	WHERE legacy._id = s._id
+	RETURNING s._id, s.user_id, s.status, legacy.status AS legacy_status
+)
+INSERT INTO service_status_history (service_id, from_status, to_status, changed_by, note)
+	SELECT _id, NULL, status, user_id, 'migrated from legacy status "' || legacy_status || '"' FROM mapped;
+
+ALTER TABLE services
+	ALTER COLUMN status SET DEFAULT 'requested',
+	ADD CHECK (status IN ('requested', 'approved', 'in_progress', 'completed', 'cancelled'));
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Move confirmation and password reset codes out of users.uuid into hashed, single-use codes.
+-- Codes sent before the migration stop working; unconfirmed users can ask for a new one
+-- through POST /auth/confirm/resend.
+BEGIN;
+
+CREATE TABLE verification_codes (
+	_id SERIAL PRIMARY KEY,
+	user_id INT NOT NULL,
+	purpose VARCHAR(32) NOT NULL,
+	code_hash CHAR(64) NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL,
+	used_at TIMESTAMPTZ,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+	FOREIGN KEY (user_id) REFERENCES users(_id) ON DELETE CASCADE
+);
+CREATE INDEX verification_codes_lookup ON verification_codes (user_id, purpose, code_hash);
+
+ALTER TABLE users DROP COLUMN uuid;
+
+COMMIT;
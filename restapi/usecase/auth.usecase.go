@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang-restapi/mailer"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/utils"
+	"ratelimit"
+)
+
+// ErrAccountNotConfirmed -- Returned when logging in before the account is confirmed
+var ErrAccountNotConfirmed = errors.New("account is not confirmed")
+
+// ErrInvalidCredentials -- Returned when the password doesn't match
+var ErrInvalidCredentials = errors.New("invalid username or password")
+
+// ErrWrongCode -- Returned when a verification code is unknown, used or expired
+var ErrWrongCode = errors.New("wrong confirmation code")
+
+// AuthUsecase -- Registration, login and the verification code flows
+type AuthUsecase interface {
+	Register(email string, password string) (model.User, error)
+	Login(ctx context.Context, ip string, email string, password string) (model.UserResponse, string, error)
+	ConfirmAccount(email string, code string) error
+	ResendConfirmation(email string) error
+	RequestPassword(email string) error
+	ChangePassword(email string, code string, newPassword string) error
+}
+
+type authUsecase struct {
+	userRepository         repository.UserRepository
+	roleRepository         repository.RoleRepository
+	verificationRepository repository.VerificationRepository
+	organizationRepository repository.OrganizationRepository
+	mailer                 mailer.Mailer
+	loginLimiter           *ratelimit.Limiter
+}
+
+// InitializeAuthUsecase -- Create the auth usecase, codes are delivered through m and logins throttled by limiter
+func InitializeAuthUsecase(
+	userRepository repository.UserRepository,
+	roleRepository repository.RoleRepository,
+	verificationRepository repository.VerificationRepository,
+	organizationRepository repository.OrganizationRepository,
+	m mailer.Mailer,
+	limiter *ratelimit.Limiter,
+) AuthUsecase {
+	return &authUsecase{
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
+		verificationRepository: verificationRepository,
+		organizationRepository: organizationRepository,
+		mailer:                 m,
+		loginLimiter:           limiter,
+	}
+}
+
+// Register -- Create an unconfirmed user and email the confirmation code,
+// repository.ErrEmailTaken is returned when the email is already used.
+// The user is removed again when the code can't be sent, so registering can be retried.
+func (u *authUsecase) Register(email string, password string) (model.User, error) {
+	var err error
+	user := model.User{Email: email}
+	user.Password, err = utils.HashPassword(password)
+	if err != nil {
+		return model.User{}, err
+	}
+	err = u.userRepository.CreateUser(&user)
+	if err != nil {
+		return model.User{}, err
+	}
+	err = u.sendVerificationCode(user, model.PurposeConfirmAccount)
+	if err != nil {
+		deleteErr := u.userRepository.DeleteUser(user.ID)
+		if deleteErr != nil {
+			return model.User{}, fmt.Errorf("send confirmation code: %v, remove user: %w", err, deleteErr)
+		}
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func (u *authUsecase) sendVerificationCode(user model.User, purpose string) error {
+	code, err := utils.GenerateCode()
+	if err != nil {
+		return err
+	}
+	message := mailer.Message{To: user.Email}
+	ttl := model.ConfirmAccountCodeTTL
+	switch purpose {
+	case model.PurposeConfirmAccount:
+		message.Subject = "Confirm your account"
+		message.Body = fmt.Sprintf("Your confirmation code is %s\nIt expires in %s.", code, ttl)
+	case model.PurposeResetPassword:
+		ttl = model.ResetPasswordCodeTTL
+		message.Subject = "Reset your password"
+		message.Body = fmt.Sprintf("Your password reset code is %s\nIt expires in %s. Ignore this email if you didn't ask for it.", code, ttl)
+	}
+	err = u.verificationRepository.CreateVerificationCode(user.ID, purpose, utils.HashCode(code), ttl)
+	if err != nil {
+		return err
+	}
+	return u.mailer.Send(message)
+}
+
+// Login -- Check the credentials and return the user with a token carrying its permissions,
+// scoped to the organization the user joined first. Rejected attempts return a *ratelimit.LimitedError.
+func (u *authUsecase) Login(ctx context.Context, ip string, email string, password string) (model.UserResponse, string, error) {
+	err := u.loginLimiter.Allow(ctx, ip, email)
+	if err != nil {
+		return model.UserResponse{}, "", err
+	}
+	user, err := u.userRepository.GetUserByEmail(email)
+	if err != nil {
+		return model.UserResponse{}, "", err
+	}
+	// An unknown email counts as a failed attempt so it can't be told apart from a wrong password
+	if user == (model.User{}) || !utils.CheckPasswordHash(password, user.Password) {
+		err = u.loginLimiter.Failure(ctx, ip, email)
+		if err != nil {
+			return model.UserResponse{}, "", err
+		}
+		return model.UserResponse{}, "", ErrInvalidCredentials
+	}
+	err = u.loginLimiter.Success(ctx, ip, email)
+	if err != nil {
+		return model.UserResponse{}, "", err
+	}
+	if !user.Confirmed {
+		return model.UserResponse{}, "", ErrAccountNotConfirmed
+	}
+	role, err := u.roleRepository.GetRoleByUserID(user.ID)
+	if err != nil {
+		return model.UserResponse{}, "", err
+	}
+	orgs, err := u.organizationRepository.GetUserOrganizations(user.ID)
+	if err != nil {
+		return model.UserResponse{}, "", err
+	}
+	var orgID uint64
+	if len(orgs) > 0 {
+		orgID = orgs[0].ID
+	}
+	token, err := utils.CreateToken(user.ID, role, orgID)
+	if err != nil {
+		return model.UserResponse{}, "", err
+	}
+	userResponse := model.NewUserResponse(user)
+	userResponse.Role = role.Name
+	return userResponse, token, nil
+}
+
+// ConfirmAccount -- Confirm the account when the code is valid
+func (u *authUsecase) ConfirmAccount(email string, code string) error {
+	user, err := u.consumeCode(email, model.PurposeConfirmAccount, code)
+	if err != nil {
+		return err
+	}
+	return u.userRepository.ConfirmAccount(user.ID)
+}
+
+// ResendConfirmation -- Email a new confirmation code, unknown and confirmed emails are ignored so they can't be enumerated
+func (u *authUsecase) ResendConfirmation(email string) error {
+	user, err := u.userRepository.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	if user == (model.User{}) || user.Confirmed {
+		return nil
+	}
+	return u.sendVerificationCode(user, model.PurposeConfirmAccount)
+}
+
+// RequestPassword -- Email a password reset code, unknown emails are ignored so they can't be enumerated
+func (u *authUsecase) RequestPassword(email string) error {
+	user, err := u.userRepository.GetUserByEmail(email)
+	if err != nil {
+		return err
+	}
+	if user == (model.User{}) {
+		return nil
+	}
+	return u.sendVerificationCode(user, model.PurposeResetPassword)
+}
+
+// ChangePassword -- Set a new password when the reset code is valid
+func (u *authUsecase) ChangePassword(email string, code string, newPassword string) error {
+	_, err := u.consumeCode(email, model.PurposeResetPassword, code)
+	if err != nil {
+		return err
+	}
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	return u.userRepository.ChangePassword(email, hashed)
+}
+
+// consumeCode -- Use up the code of the user, ErrWrongCode is returned for unknown users too
+func (u *authUsecase) consumeCode(email string, purpose string, code string) (model.User, error) {
+	user, err := u.userRepository.GetUserByEmail(email)
+	if err != nil {
+		return model.User{}, err
+	}
+	if user == (model.User{}) {
+		return model.User{}, ErrWrongCode
+	}
+	ok, err := u.verificationRepository.ConsumeVerificationCode(user.ID, purpose, utils.HashCode(code))
+	if err != nil {
+		return model.User{}, err
+	}
+	if !ok {
+		return model.User{}, ErrWrongCode
+	}
+	return user, nil
+}
@@ -0,0 +1,268 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"golang-restapi/mailer"
+	"golang-restapi/mocks"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/utils"
+	"io/ioutil"
+	"log"
+	"os"
+	"ratelimit"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type authUsecaseSuite struct {
+	suite.Suite
+	userRepository         *mocks.UserRepository
+	roleRepository         *mocks.RoleRepository
+	verificationRepository *mocks.VerificationRepository
+	organizationRepository *mocks.OrganizationRepository
+	outbox                 *mailer.MemoryOutbox
+	usecase                AuthUsecase
+	// bcrypt hash of "password" with the minimum cost, so comparing it stays fast
+	passwordHash string
+}
+
+func (suite *authUsecaseSuite) SetupSuite() {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	suite.Require().NoError(err)
+	suite.passwordHash = string(hash)
+}
+
+func (suite *authUsecaseSuite) SetupTest() {
+	suite.userRepository = new(mocks.UserRepository)
+	suite.roleRepository = new(mocks.RoleRepository)
+	suite.verificationRepository = new(mocks.VerificationRepository)
+	suite.organizationRepository = new(mocks.OrganizationRepository)
+	suite.outbox = mailer.NewMemoryOutbox()
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryBackend(), ratelimit.Config{
+		PerIP:            ratelimit.Limit{Burst: 100, Every: time.Second},
+		PerAccount:       ratelimit.Limit{Burst: 100, Every: time.Second},
+		LockoutThreshold: 2,
+		LockoutBase:      time.Minute,
+		LockoutMax:       time.Hour,
+		FailureWindow:    time.Hour,
+	}, log.New(ioutil.Discard, "", 0))
+	suite.usecase = InitializeAuthUsecase(suite.userRepository, suite.roleRepository, suite.verificationRepository, suite.organizationRepository, suite.outbox, limiter)
+}
+
+// failingMailer -- Mailer whose every delivery fails
+type failingMailer struct{}
+
+func (failingMailer) Send(message mailer.Message) error {
+	return errors.New("smtp down")
+}
+
+func (suite *authUsecaseSuite) TestRegister() {
+	cases := []struct {
+		name       string
+		createErr  error
+		codeErr    error
+		mailFails  bool
+		wantErr    error
+		wantEmail  bool
+		wantDelete bool
+	}{
+		{name: "registered", wantEmail: true},
+		{name: "email taken", createErr: repository.ErrEmailTaken, wantErr: repository.ErrEmailTaken},
+		{name: "code not stored", codeErr: errors.New("db down"), wantErr: errors.New("db down"), wantDelete: true},
+		{name: "mail not sent", mailFails: true, wantErr: errors.New("smtp down"), wantDelete: true},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			if c.mailFails {
+				suite.usecase.(*authUsecase).mailer = failingMailer{}
+			}
+			suite.userRepository.On("CreateUser", mock.AnythingOfType("*model.User")).Return(c.createErr).Run(func(args mock.Arguments) {
+				args.Get(0).(*model.User).ID = 1
+			})
+			suite.userRepository.On("DeleteUser", uint64(1)).Return(nil)
+			suite.verificationRepository.On("CreateVerificationCode", uint64(1), model.PurposeConfirmAccount, mock.AnythingOfType("string"), model.ConfirmAccountCodeTTL).Return(c.codeErr)
+
+			user, err := suite.usecase.Register("user@example.com", "password")
+
+			suite.Equal(c.wantErr, err)
+			suite.Equal(c.wantEmail, len(suite.outbox.Messages()) == 1, "confirmation email")
+			if c.wantDelete {
+				suite.userRepository.AssertCalled(suite.T(), "DeleteUser", uint64(1))
+			} else {
+				suite.userRepository.AssertNotCalled(suite.T(), "DeleteUser", mock.Anything)
+			}
+			if c.wantErr == nil {
+				suite.Equal(uint64(1), user.ID)
+				suite.True(utils.CheckPasswordHash("password", user.Password), "password is stored hashed")
+			}
+		})
+	}
+}
+
+func (suite *authUsecaseSuite) TestResendConfirmation() {
+	cases := []struct {
+		name      string
+		user      model.User
+		wantEmail bool
+	}{
+		{name: "unconfirmed email", user: model.User{ID: 1, Email: "user@example.com"}, wantEmail: true},
+		{name: "confirmed email", user: model.User{ID: 1, Email: "user@example.com", Confirmed: true}, wantEmail: false},
+		{name: "unknown email", user: model.User{}, wantEmail: false},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.userRepository.On("GetUserByEmail", "user@example.com").Return(c.user, nil)
+			suite.verificationRepository.On("CreateVerificationCode", uint64(1), model.PurposeConfirmAccount, mock.AnythingOfType("string"), model.ConfirmAccountCodeTTL).Return(nil)
+
+			err := suite.usecase.ResendConfirmation("user@example.com")
+
+			suite.NoError(err)
+			suite.Equal(c.wantEmail, len(suite.outbox.Messages()) == 1, "confirmation email")
+		})
+	}
+}
+
+func (suite *authUsecaseSuite) TestLogin() {
+	confirmed := model.User{ID: 1, Email: "user@example.com", Password: suite.passwordHash, Confirmed: true}
+	unconfirmed := confirmed
+	unconfirmed.Confirmed = false
+	cases := []struct {
+		name     string
+		user     model.User
+		password string
+		orgs     []model.UserOrganization
+		attempts int
+		wantErr  error
+		limited  bool
+		wantOrg  float64
+	}{
+		{name: "success without organization", user: confirmed, password: "password"},
+		{name: "scoped to the first organization", user: confirmed, password: "password", orgs: []model.UserOrganization{{Organization: model.Organization{ID: 7}}, {Organization: model.Organization{ID: 3}}}, wantOrg: 7},
+		{name: "not confirmed", user: unconfirmed, password: "password", wantErr: ErrAccountNotConfirmed},
+		{name: "not confirmed with wrong password", user: unconfirmed, password: "wrong", wantErr: ErrInvalidCredentials},
+		{name: "unknown user", user: model.User{}, password: "password", wantErr: ErrInvalidCredentials},
+		{name: "unknown user locked after repeated failures", user: model.User{}, password: "password", attempts: 2, limited: true},
+		{name: "wrong password", user: confirmed, password: "wrong", wantErr: ErrInvalidCredentials},
+		{name: "locked after repeated failures", user: confirmed, password: "wrong", attempts: 2, limited: true},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.userRepository.On("GetUserByEmail", "user@example.com").Return(c.user, nil)
+			suite.roleRepository.On("GetRoleByUserID", uint64(1)).Return(model.Role{ID: 1, Name: model.RoleUser}, nil)
+			suite.organizationRepository.On("GetUserOrganizations", uint64(1)).Return(c.orgs, nil)
+
+			var user model.UserResponse
+			var token string
+			var err error
+			for i := 0; i <= c.attempts; i++ {
+				user, token, err = suite.usecase.Login(context.Background(), "127.0.0.1", "user@example.com", c.password)
+			}
+
+			if c.limited {
+				var limitedErr *ratelimit.LimitedError
+				suite.True(errors.As(err, &limitedErr), "expected a limited error, got %v", err)
+				return
+			}
+			suite.Equal(c.wantErr, err)
+			if c.wantErr == nil {
+				suite.Equal(model.RoleUser, user.Role)
+				suite.Equal(c.wantOrg, tokenClaims(suite.T(), token)["org_id"])
+			}
+		})
+	}
+}
+
+func (suite *authUsecaseSuite) TestConfirmAccount() {
+	user := model.User{ID: 1, Email: "user@example.com"}
+	cases := []struct {
+		name      string
+		user      model.User
+		codeValid bool
+		wantErr   error
+	}{
+		{name: "confirmed", user: user, codeValid: true},
+		{name: "wrong code", user: user, codeValid: false, wantErr: ErrWrongCode},
+		{name: "unknown user", user: model.User{}, wantErr: ErrWrongCode},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.userRepository.On("GetUserByEmail", "user@example.com").Return(c.user, nil)
+			suite.verificationRepository.On("ConsumeVerificationCode", uint64(1), model.PurposeConfirmAccount, utils.HashCode("code")).Return(c.codeValid, nil)
+			suite.userRepository.On("ConfirmAccount", uint64(1)).Return(nil)
+
+			err := suite.usecase.ConfirmAccount("user@example.com", "code")
+
+			suite.Equal(c.wantErr, err)
+			if c.wantErr == nil {
+				suite.userRepository.AssertCalled(suite.T(), "ConfirmAccount", uint64(1))
+			} else {
+				suite.userRepository.AssertNotCalled(suite.T(), "ConfirmAccount", mock.Anything)
+			}
+		})
+	}
+}
+
+func (suite *authUsecaseSuite) TestRequestPassword() {
+	cases := []struct {
+		name      string
+		user      model.User
+		wantEmail bool
+	}{
+		{name: "registered email", user: model.User{ID: 1, Email: "user@example.com"}, wantEmail: true},
+		{name: "unknown email", user: model.User{}, wantEmail: false},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.userRepository.On("GetUserByEmail", "user@example.com").Return(c.user, nil)
+			suite.verificationRepository.On("CreateVerificationCode", uint64(1), model.PurposeResetPassword, mock.AnythingOfType("string"), model.ResetPasswordCodeTTL).Return(nil)
+
+			err := suite.usecase.RequestPassword("user@example.com")
+
+			suite.NoError(err)
+			suite.Equal(c.wantEmail, len(suite.outbox.Messages()) == 1, "reset email")
+		})
+	}
+}
+
+func (suite *authUsecaseSuite) TestChangePassword_WrongCode_Negative() {
+	suite.userRepository.On("GetUserByEmail", "user@example.com").Return(model.User{ID: 1, Email: "user@example.com"}, nil)
+	suite.verificationRepository.On("ConsumeVerificationCode", uint64(1), model.PurposeResetPassword, utils.HashCode("code")).Return(false, nil)
+
+	err := suite.usecase.ChangePassword("user@example.com", "code", "new password")
+
+	suite.Equal(ErrWrongCode, err)
+	suite.userRepository.AssertNotCalled(suite.T(), "ChangePassword", mock.Anything, mock.Anything)
+}
+
+// tokenClaims -- Verify and decode a token issued by utils.CreateToken
+func tokenClaims(t *testing.T, token string) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(os.Getenv("SECRET_KEY")), nil
+	})
+	if err != nil {
+		t.Fatalf("expected a valid token, got %v", err)
+	}
+	return claims
+}
+
+func TestAuthUsecase(t *testing.T) {
+	suite.Run(t, new(authUsecaseSuite))
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"golang-restapi/mailer"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/utils"
+)
+
+// ErrNoOrganization -- Returned when the token of the actor isn't scoped to an organization
+var ErrNoOrganization = errors.New("user is not in an organization")
+
+// ErrNotOrgOwner -- Returned when a member who doesn't own the organization tries to manage it
+var ErrNotOrgOwner = errors.New("only owners can manage the organization")
+
+// OrganizationUsecase -- Organizations, memberships and invites.
+// Methods returning a token issue one scoped to the organization the actor ends up in.
+type OrganizationUsecase interface {
+	CreateOrganization(actor model.Actor, name string) (model.Organization, string, error)
+	GetOrganizations(actor model.Actor) ([]model.UserOrganization, error)
+	GetMembers(actor model.Actor) ([]model.Membership, error)
+	Invite(actor model.Actor, email string) error
+	AcceptInvite(actor model.Actor, code string) (uint64, string, error)
+	SwitchOrganization(actor model.Actor, orgID uint64) (string, error)
+}
+
+type organizationUsecase struct {
+	organizationRepository repository.OrganizationRepository
+	userRepository         repository.UserRepository
+	roleRepository         repository.RoleRepository
+	mailer                 mailer.Mailer
+}
+
+// InitializeOrganizationUsecase -- Create the organization usecase, invites are delivered through m
+func InitializeOrganizationUsecase(
+	organizationRepository repository.OrganizationRepository,
+	userRepository repository.UserRepository,
+	roleRepository repository.RoleRepository,
+	m mailer.Mailer,
+) OrganizationUsecase {
+	return &organizationUsecase{
+		organizationRepository: organizationRepository,
+		userRepository:         userRepository,
+		roleRepository:         roleRepository,
+		mailer:                 m,
+	}
+}
+
+// CreateOrganization -- Create an organization owned by the actor
+func (u *organizationUsecase) CreateOrganization(actor model.Actor, name string) (model.Organization, string, error) {
+	org, err := u.organizationRepository.CreateOrganization(name, actor.UserID)
+	if err != nil {
+		return model.Organization{}, "", err
+	}
+	token, err := issueToken(u.roleRepository, actor.UserID, org.ID)
+	if err != nil {
+		return model.Organization{}, "", err
+	}
+	return org, token, nil
+}
+
+// GetOrganizations -- Get the organizations the actor belongs to
+func (u *organizationUsecase) GetOrganizations(actor model.Actor) ([]model.UserOrganization, error) {
+	return u.organizationRepository.GetUserOrganizations(actor.UserID)
+}
+
+// GetMembers -- Get the members of the actor's current organization
+func (u *organizationUsecase) GetMembers(actor model.Actor) ([]model.Membership, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+	return u.organizationRepository.GetMembers(actor.OrgID)
+}
+
+// Invite -- Email an invite code to join the actor's current organization, only owners can invite
+func (u *organizationUsecase) Invite(actor model.Actor, email string) error {
+	if actor.OrgID == 0 {
+		return ErrNoOrganization
+	}
+	membership, err := u.organizationRepository.GetMembership(actor.OrgID, actor.UserID)
+	if err == repository.ErrNotMember {
+		return ErrNoOrganization
+	}
+	if err != nil {
+		return err
+	}
+	if membership.Role != model.OrgRoleOwner {
+		return ErrNotOrgOwner
+	}
+
+	code, err := utils.GenerateCode()
+	if err != nil {
+		return err
+	}
+	err = u.organizationRepository.CreateInvite(actor.OrgID, email, utils.HashCode(code), actor.UserID, model.InviteTTL)
+	if err != nil {
+		return err
+	}
+	return u.mailer.Send(mailer.Message{
+		To:      email,
+		Subject: "You have been invited to an organization",
+		Body:    fmt.Sprintf("%s invited you to their organization.\nYour invite code is %s\nIt expires in %s.", membership.Email, code, model.InviteTTL),
+	})
+}
+
+// AcceptInvite -- Join the organization of an invite sent to the actor's email,
+// repository.ErrInviteNotFound is returned when the code isn't valid for the actor
+func (u *organizationUsecase) AcceptInvite(actor model.Actor, code string) (uint64, string, error) {
+	user, err := u.userRepository.GetUserByID(actor.UserID)
+	if err != nil {
+		return 0, "", err
+	}
+	if user == (model.User{}) {
+		return 0, "", repository.ErrUserNotFound
+	}
+	orgID, err := u.organizationRepository.AcceptInvite(user.Email, utils.HashCode(code), user.ID)
+	if err != nil {
+		return 0, "", err
+	}
+	token, err := issueToken(u.roleRepository, actor.UserID, orgID)
+	if err != nil {
+		return 0, "", err
+	}
+	return orgID, token, nil
+}
+
+// SwitchOrganization -- Issue a token scoped to another organization of the actor,
+// repository.ErrNotMember is returned when the actor doesn't belong to it
+func (u *organizationUsecase) SwitchOrganization(actor model.Actor, orgID uint64) (string, error) {
+	_, err := u.organizationRepository.GetMembership(orgID, actor.UserID)
+	if err != nil {
+		return "", err
+	}
+	return issueToken(u.roleRepository, actor.UserID, orgID)
+}
+
+// issueToken -- Create a token carrying the current role and permissions of the user, scoped to orgID
+func issueToken(roleRepository repository.RoleRepository, userID uint64, orgID uint64) (string, error) {
+	role, err := roleRepository.GetRoleByUserID(userID)
+	if err != nil {
+		return "", err
+	}
+	return utils.CreateToken(userID, role, orgID)
+}
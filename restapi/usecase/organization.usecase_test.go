@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"golang-restapi/mailer"
+	"golang-restapi/mocks"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"golang-restapi/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type organizationUsecaseSuite struct {
+	suite.Suite
+	organizationRepository *mocks.OrganizationRepository
+	userRepository         *mocks.UserRepository
+	roleRepository         *mocks.RoleRepository
+	outbox                 *mailer.MemoryOutbox
+	usecase                OrganizationUsecase
+}
+
+func (suite *organizationUsecaseSuite) SetupTest() {
+	suite.organizationRepository = new(mocks.OrganizationRepository)
+	suite.userRepository = new(mocks.UserRepository)
+	suite.roleRepository = new(mocks.RoleRepository)
+	suite.outbox = mailer.NewMemoryOutbox()
+	suite.usecase = InitializeOrganizationUsecase(suite.organizationRepository, suite.userRepository, suite.roleRepository, suite.outbox)
+	suite.roleRepository.On("GetRoleByUserID", mock.AnythingOfType("uint64")).Return(model.Role{Name: model.RoleUser}, nil)
+}
+
+func (suite *organizationUsecaseSuite) TestCreateOrganization_Positive() {
+	suite.organizationRepository.On("CreateOrganization", "Acme Shipping", uint64(1)).Return(model.Organization{ID: 7, Name: "Acme Shipping"}, nil)
+
+	org, token, err := suite.usecase.CreateOrganization(model.Actor{UserID: 1}, "Acme Shipping")
+
+	suite.NoError(err)
+	suite.Equal(uint64(7), org.ID)
+	suite.Equal(float64(7), tokenClaims(suite.T(), token)["org_id"], "token is scoped to the new organization")
+}
+
+func (suite *organizationUsecaseSuite) TestInvite() {
+	cases := []struct {
+		name       string
+		actor      model.Actor
+		membership model.Membership
+		memberErr  error
+		wantErr    error
+	}{
+		{name: "owner invites", actor: model.Actor{UserID: 1, OrgID: 7}, membership: model.Membership{Role: model.OrgRoleOwner, Email: "owner@example.com"}},
+		{name: "member invites", actor: model.Actor{UserID: 1, OrgID: 7}, membership: model.Membership{Role: model.OrgRoleMember}, wantErr: ErrNotOrgOwner},
+		{name: "token of an organization the user left", actor: model.Actor{UserID: 1, OrgID: 7}, memberErr: repository.ErrNotMember, wantErr: ErrNoOrganization},
+		{name: "no organization", actor: model.Actor{UserID: 1}, wantErr: ErrNoOrganization},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.organizationRepository.On("GetMembership", c.actor.OrgID, c.actor.UserID).Return(c.membership, c.memberErr)
+			suite.organizationRepository.On("CreateInvite", uint64(7), "agent@example.com", mock.AnythingOfType("string"), uint64(1), model.InviteTTL).Return(nil)
+
+			err := suite.usecase.Invite(c.actor, "agent@example.com")
+
+			suite.Equal(c.wantErr, err)
+			messages := suite.outbox.Messages()
+			if c.wantErr != nil {
+				suite.Empty(messages)
+				suite.organizationRepository.AssertNotCalled(suite.T(), "CreateInvite", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			suite.Len(messages, 1)
+			suite.Equal("agent@example.com", messages[0].To)
+			// the stored hash must belong to the code that was emailed
+			codeHash := suite.organizationRepository.Calls[len(suite.organizationRepository.Calls)-1].Arguments.String(2)
+			code := strings.Fields(strings.Split(messages[0].Body, "invite code is ")[1])[0]
+			suite.Equal(utils.HashCode(code), codeHash)
+		})
+	}
+}
+
+func (suite *organizationUsecaseSuite) TestAcceptInvite() {
+	cases := []struct {
+		name      string
+		acceptErr error
+		wantErr   error
+	}{
+		{name: "accepted"},
+		{name: "wrong code or email", acceptErr: repository.ErrInviteNotFound, wantErr: repository.ErrInviteNotFound},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.userRepository.On("GetUserByID", uint64(2)).Return(model.User{ID: 2, Email: "agent@example.com"}, nil)
+			suite.organizationRepository.On("AcceptInvite", "agent@example.com", utils.HashCode("code"), uint64(2)).Return(uint64(7), c.acceptErr)
+
+			orgID, token, err := suite.usecase.AcceptInvite(model.Actor{UserID: 2}, "code")
+
+			suite.Equal(c.wantErr, err)
+			if c.wantErr == nil {
+				suite.Equal(uint64(7), orgID)
+				suite.Equal(float64(7), tokenClaims(suite.T(), token)["org_id"])
+			}
+		})
+	}
+}
+
+func (suite *organizationUsecaseSuite) TestSwitchOrganization() {
+	cases := []struct {
+		name      string
+		memberErr error
+		wantErr   error
+	}{
+		{name: "member", memberErr: nil},
+		{name: "not a member", memberErr: repository.ErrNotMember, wantErr: repository.ErrNotMember},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.organizationRepository.On("GetMembership", uint64(9), uint64(1)).Return(model.Membership{OrgID: 9, UserID: 1}, c.memberErr)
+
+			token, err := suite.usecase.SwitchOrganization(model.Actor{UserID: 1, OrgID: 7}, 9)
+
+			suite.Equal(c.wantErr, err)
+			if c.wantErr == nil {
+				suite.Equal(float64(9), tokenClaims(suite.T(), token)["org_id"])
+			} else {
+				suite.Empty(token)
+			}
+		})
+	}
+}
+
+func (suite *organizationUsecaseSuite) TestGetMembers_NoOrganization_Negative() {
+	_, err := suite.usecase.GetMembers(model.Actor{UserID: 1})
+
+	suite.Equal(ErrNoOrganization, err)
+	suite.organizationRepository.AssertNotCalled(suite.T(), "GetMembers", mock.Anything)
+}
+
+func TestOrganizationUsecase(t *testing.T) {
+	suite.Run(t, new(organizationUsecaseSuite))
+}
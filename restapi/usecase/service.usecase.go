@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"errors"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+)
+
+// ErrInvalidStatus -- Returned when the requested status isn't a service request state
+var ErrInvalidStatus = errors.New("invalid service status")
+
+// ErrOnlyCancel -- Returned when an owner without service:status:any tries anything but cancelling
+var ErrOnlyCancel = errors.New("owners can only cancel their service requests")
+
+// ServiceUsecase -- Service requests, their schedule and their status workflow
+// Every method is scoped to the organization of the actor and returns ErrNoOrganization when there is none.
+type ServiceUsecase interface {
+	CreateServiceRequest(data model.CreateServiceData, actor model.Actor) (model.Service, error)
+	FindServices(filter model.ServiceFilter, actor model.Actor) ([]model.Service, error)
+	GetUpcomingServices(filter model.UpcomingFilter, actor model.Actor) ([]model.Service, error)
+	GetService(serviceID uint64, actor model.Actor) (model.Service, []model.ServiceStatusChange, error)
+	UpdateServiceStatus(serviceID uint64, status string, note string, actor model.Actor) (model.Service, error)
+}
+
+type serviceUsecase struct {
+	serviceRepository repository.ServiceRepository
+}
+
+// InitializeServiceUsecase -- Create the service usecase
+func InitializeServiceUsecase(serviceRepository repository.ServiceRepository) ServiceUsecase {
+	return &serviceUsecase{
+		serviceRepository: serviceRepository,
+	}
+}
+
+// CreateServiceRequest -- Create a service request in the actor's organization,
+// model.ErrInvalidSchedule is returned when ETA isn't after ETD
+func (u *serviceUsecase) CreateServiceRequest(data model.CreateServiceData, actor model.Actor) (model.Service, error) {
+	if actor.OrgID == 0 {
+		return model.Service{}, ErrNoOrganization
+	}
+	err := data.ValidateSchedule()
+	if err != nil {
+		return model.Service{}, err
+	}
+	return u.serviceRepository.CreateServiceRequest(data, actor.UserID, actor.OrgID)
+}
+
+// FindServices -- Get the services of the actor's organization matching the filter
+func (u *serviceUsecase) FindServices(filter model.ServiceFilter, actor model.Actor) ([]model.Service, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+	filter.OrgID = actor.OrgID
+	return u.serviceRepository.FindServices(filter)
+}
+
+// GetUpcomingServices -- Get the active services of the actor's organization arriving or departing in the filter's window
+func (u *serviceUsecase) GetUpcomingServices(filter model.UpcomingFilter, actor model.Actor) ([]model.Service, error) {
+	if actor.OrgID == 0 {
+		return nil, ErrNoOrganization
+	}
+	filter.OrgID = actor.OrgID
+	return u.serviceRepository.GetUpcomingServices(filter)
+}
+
+// GetService -- Get a service request with its status history.
+// Actors without service:read:any only see their own requests, others look like they don't exist.
+func (u *serviceUsecase) GetService(serviceID uint64, actor model.Actor) (model.Service, []model.ServiceStatusChange, error) {
+	service, err := u.visibleService(serviceID, actor, model.PermissionServiceReadAny)
+	if err != nil {
+		return model.Service{}, nil, err
+	}
+	history, err := u.serviceRepository.GetServiceHistory(actor.OrgID, service.ID)
+	if err != nil {
+		return model.Service{}, nil, err
+	}
+	return service, history, nil
+}
+
+// UpdateServiceStatus -- Move a service request to another status.
+// Actors with service:status:any can make any valid transition, owners can only cancel their own requests.
+func (u *serviceUsecase) UpdateServiceStatus(serviceID uint64, status string, note string, actor model.Actor) (model.Service, error) {
+	if !model.IsServiceStatus(status) {
+		return model.Service{}, ErrInvalidStatus
+	}
+	service, err := u.visibleService(serviceID, actor, model.PermissionServiceStatusAny)
+	if err != nil {
+		return model.Service{}, err
+	}
+	if !actor.Can(model.PermissionServiceStatusAny) && status != model.ServiceCancelled {
+		return model.Service{}, ErrOnlyCancel
+	}
+	return u.serviceRepository.UpdateServiceStatus(actor.OrgID, service.ID, status, actor.UserID, note)
+}
+
+// visibleService -- Load the service request of the actor's organization when the actor owns it
+// or has anyPermission, repository.ErrServiceNotFound is returned otherwise
+func (u *serviceUsecase) visibleService(serviceID uint64, actor model.Actor, anyPermission string) (model.Service, error) {
+	if actor.OrgID == 0 {
+		return model.Service{}, ErrNoOrganization
+	}
+	service, err := u.serviceRepository.GetServiceByID(actor.OrgID, serviceID)
+	if err != nil {
+		return model.Service{}, err
+	}
+	if service.UserID != actor.UserID && !actor.Can(anyPermission) {
+		return model.Service{}, repository.ErrServiceNotFound
+	}
+	return service, nil
+}
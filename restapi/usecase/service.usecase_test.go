@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"golang-restapi/mocks"
+	"golang-restapi/model"
+	"golang-restapi/repository"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type serviceUsecaseSuite struct {
+	suite.Suite
+	repository *mocks.ServiceRepository
+	usecase    ServiceUsecase
+}
+
+func (suite *serviceUsecaseSuite) SetupTest() {
+	suite.repository = new(mocks.ServiceRepository)
+	suite.usecase = InitializeServiceUsecase(suite.repository)
+}
+
+// Users 1 and 2 work for organization 1, user 3 administers organization 1 and user 4 administers organization 2
+var (
+	owner      = model.Actor{UserID: 1, OrgID: 1, Permissions: []string{model.PermissionServiceReadOwn, model.PermissionServiceWriteOwn}}
+	colleague  = model.Actor{UserID: 2, OrgID: 1, Permissions: []string{model.PermissionServiceReadOwn, model.PermissionServiceWriteOwn}}
+	admin      = model.Actor{UserID: 3, OrgID: 1, Permissions: []string{model.PermissionServiceReadAny, model.PermissionServiceStatusAny}}
+	otherAdmin = model.Actor{UserID: 4, OrgID: 2, Permissions: []string{model.PermissionServiceReadAny, model.PermissionServiceStatusAny}}
+	homeless   = model.Actor{UserID: 5, Permissions: []string{model.PermissionServiceReadOwn, model.PermissionServiceWriteOwn}}
+)
+
+// mockTenantRepository -- Service 10 belongs to user 1 in organization 1, like the real repository
+// any other organization asking for it gets ErrServiceNotFound
+func (suite *serviceUsecaseSuite) mockTenantRepository() {
+	service := model.Service{ID: 10, UserID: owner.UserID, OrgID: 1, Status: model.ServiceRequested}
+	suite.repository.On("GetServiceByID", uint64(1), uint64(10)).Return(service, nil)
+	suite.repository.On("GetServiceByID", mock.AnythingOfType("uint64"), uint64(10)).Return(model.Service{}, repository.ErrServiceNotFound)
+	suite.repository.On("GetServiceHistory", uint64(1), uint64(10)).Return([]model.ServiceStatusChange{{ServiceID: 10, ToStatus: model.ServiceRequested}}, nil)
+}
+
+func (suite *serviceUsecaseSuite) TestCreateServiceRequest() {
+	etd := time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		actor   model.Actor
+		eta     time.Time
+		wantErr error
+	}{
+		{name: "valid schedule", actor: owner, eta: etd.Add(time.Hour)},
+		{name: "eta before etd", actor: owner, eta: etd.Add(-time.Hour), wantErr: model.ErrInvalidSchedule},
+		{name: "no organization", actor: homeless, eta: etd.Add(time.Hour), wantErr: ErrNoOrganization},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			data := model.CreateServiceData{VesselName: "vessel", ETD: etd, ETA: c.eta}
+			suite.repository.On("CreateServiceRequest", data, c.actor.UserID, c.actor.OrgID).Return(model.Service{ID: 1, Status: model.ServiceRequested}, nil)
+
+			_, err := suite.usecase.CreateServiceRequest(data, c.actor)
+
+			suite.Equal(c.wantErr, err)
+			if c.wantErr != nil {
+				suite.repository.AssertNotCalled(suite.T(), "CreateServiceRequest", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func (suite *serviceUsecaseSuite) TestFindServices_ScopedToOrganization() {
+	cases := []struct {
+		name    string
+		actor   model.Actor
+		filter  model.ServiceFilter
+		wantErr error
+	}{
+		{name: "own organization", actor: owner},
+		{name: "organization in the filter is ignored", actor: otherAdmin, filter: model.ServiceFilter{OrgID: 1}},
+		{name: "no organization", actor: homeless, wantErr: ErrNoOrganization},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.repository.On("FindServices", mock.Anything).Return([]model.Service{}, nil)
+			suite.repository.On("GetUpcomingServices", mock.Anything).Return([]model.Service{}, nil)
+
+			_, err := suite.usecase.FindServices(c.filter, c.actor)
+			suite.Equal(c.wantErr, err)
+			_, err = suite.usecase.GetUpcomingServices(model.UpcomingFilter{OrgID: c.filter.OrgID}, c.actor)
+			suite.Equal(c.wantErr, err)
+
+			if c.wantErr != nil {
+				suite.repository.AssertNotCalled(suite.T(), "FindServices", mock.Anything)
+				suite.repository.AssertNotCalled(suite.T(), "GetUpcomingServices", mock.Anything)
+				return
+			}
+			suite.repository.AssertCalled(suite.T(), "FindServices", mock.MatchedBy(func(filter model.ServiceFilter) bool {
+				return filter.OrgID == c.actor.OrgID
+			}))
+			suite.repository.AssertCalled(suite.T(), "GetUpcomingServices", mock.MatchedBy(func(filter model.UpcomingFilter) bool {
+				return filter.OrgID == c.actor.OrgID
+			}))
+		})
+	}
+}
+
+func (suite *serviceUsecaseSuite) TestGetService() {
+	cases := []struct {
+		name    string
+		actor   model.Actor
+		wantErr error
+	}{
+		{name: "owner", actor: owner},
+		{name: "read any in the organization", actor: admin},
+		{name: "colleague without read any", actor: colleague, wantErr: repository.ErrServiceNotFound},
+		{name: "read any in another organization", actor: otherAdmin, wantErr: repository.ErrServiceNotFound},
+		{name: "no organization", actor: homeless, wantErr: ErrNoOrganization},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.mockTenantRepository()
+
+			service, history, err := suite.usecase.GetService(10, c.actor)
+
+			suite.Equal(c.wantErr, err)
+			if c.wantErr == nil {
+				suite.Equal(uint64(10), service.ID)
+				suite.Len(history, 1)
+			} else {
+				suite.repository.AssertNotCalled(suite.T(), "GetServiceHistory", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func (suite *serviceUsecaseSuite) TestUpdateServiceStatus() {
+	cases := []struct {
+		name      string
+		actor     model.Actor
+		status    string
+		updateErr error
+		wantErr   error
+	}{
+		{name: "owner cancels", actor: owner, status: model.ServiceCancelled},
+		{name: "owner approves", actor: owner, status: model.ServiceApproved, wantErr: ErrOnlyCancel},
+		{name: "colleague cancels", actor: colleague, status: model.ServiceCancelled, wantErr: repository.ErrServiceNotFound},
+		{name: "admin approves", actor: admin, status: model.ServiceApproved},
+		{name: "admin of another organization approves", actor: otherAdmin, status: model.ServiceApproved, wantErr: repository.ErrServiceNotFound},
+		{name: "admin of another organization cancels", actor: otherAdmin, status: model.ServiceCancelled, wantErr: repository.ErrServiceNotFound},
+		{name: "no organization", actor: homeless, status: model.ServiceCancelled, wantErr: ErrNoOrganization},
+		{name: "unknown status", actor: admin, status: "shipped", wantErr: ErrInvalidStatus},
+		{name: "invalid transition", actor: admin, status: model.ServiceCompleted, updateErr: repository.ErrInvalidTransition, wantErr: repository.ErrInvalidTransition},
+	}
+
+	for _, c := range cases {
+		suite.Run(c.name, func() {
+			suite.SetupTest()
+			suite.mockTenantRepository()
+			suite.repository.On("UpdateServiceStatus", uint64(1), uint64(10), c.status, c.actor.UserID, "note").Return(model.Service{ID: 10, Status: c.status}, c.updateErr)
+
+			service, err := suite.usecase.UpdateServiceStatus(10, c.status, "note", c.actor)
+
+			suite.Equal(c.wantErr, err)
+			if c.wantErr == nil {
+				suite.Equal(c.status, service.Status)
+			}
+			if c.actor.OrgID != 1 {
+				suite.repository.AssertNotCalled(suite.T(), "UpdateServiceStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestServiceUsecase(t *testing.T) {
+	suite.Run(t, new(serviceUsecaseSuite))
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"golang-restapi/model"
+	"golang-restapi/repository"
+)
+
+// UserUsecase -- Reading users and managing their roles
+type UserUsecase interface {
+	GetUser(userID uint64) (model.UserResponse, error)
+	AssignRole(userID uint64, role string) error
+}
+
+type userUsecase struct {
+	userRepository repository.UserRepository
+	roleRepository repository.RoleRepository
+}
+
+// InitializeUserUsecase -- Create the user usecase
+func InitializeUserUsecase(userRepository repository.UserRepository, roleRepository repository.RoleRepository) UserUsecase {
+	return &userUsecase{
+		userRepository: userRepository,
+		roleRepository: roleRepository,
+	}
+}
+
+// GetUser -- Get the public data of a user, repository.ErrUserNotFound is returned when there is none
+func (u *userUsecase) GetUser(userID uint64) (model.UserResponse, error) {
+	user, err := u.userRepository.GetUserByID(userID)
+	if err != nil {
+		return model.UserResponse{}, err
+	}
+	if user == (model.User{}) {
+		return model.UserResponse{}, repository.ErrUserNotFound
+	}
+	return model.NewUserResponse(user), nil
+}
+
+// AssignRole -- Change the role of a user, returns repository.ErrRoleNotFound or repository.ErrUserNotFound
+func (u *userUsecase) AssignRole(userID uint64, role string) error {
+	return u.roleRepository.AssignRole(userID, role)
+}
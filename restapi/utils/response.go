@@ -1,13 +1,18 @@
 package utils
 
 import (
+	"encoding/csv"
 	"net/http"
+	"ratelimit"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 // ResponseSuccess ...
-func ResponseSuccess(c *gin.Context, message string, data gin.H) {
+func ResponseSuccess(c *gin.Context, message string, data interface{}) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": message,
 		"data":    data,
@@ -41,3 +46,66 @@ func ResponseNotFound(c *gin.Context, message string) {
 		"message": message,
 	})
 }
+
+// ResponseTooManyRequests ...
+func ResponseTooManyRequests(c *gin.Context, message string, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.FormatInt(ratelimit.RetryAfterSeconds(retryAfter), 10))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"message": message,
+	})
+}
+
+// ResponseForbidden ...
+func ResponseForbidden(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+		"message": message,
+	})
+}
+
+// ResponseConflict ...
+func ResponseConflict(c *gin.Context, message string) {
+	c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+		"message": message,
+	})
+}
+
+// CSVWriter -- Rows of a CSV attachment, written to the client as they are flushed
+type CSVWriter struct {
+	writer *csv.Writer
+	c      *gin.Context
+}
+
+// ResponseCSV -- Start a CSV attachment and write its header, the rows follow through the returned writer
+func ResponseCSV(c *gin.Context, filename string, header []string) *CSVWriter {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	w := &CSVWriter{writer: csv.NewWriter(c.Writer), c: c}
+	w.Write(header)
+	return w
+}
+
+// Write -- Add a row, cells a spreadsheet would run as a formula are escaped
+func (w *CSVWriter) Write(record []string) error {
+	escaped := make([]string, len(record))
+	for i, cell := range record {
+		escaped[i] = EscapeCSVCell(cell)
+	}
+	return w.writer.Write(escaped)
+}
+
+// Flush -- Send the rows written so far to the client
+func (w *CSVWriter) Flush() error {
+	w.writer.Flush()
+	w.c.Writer.Flush()
+	return w.writer.Error()
+}
+
+// EscapeCSVCell -- Prefix a cell starting with =, +, -, @, tab or carriage return with a quote
+// so spreadsheets show it as text instead of evaluating it
+func EscapeCSVCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
@@ -1,17 +1,21 @@
 package utils
 
 import (
+	"golang-restapi/model"
 	"os"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
-// CreateToken -- Create user token
-func CreateToken(userID uint64) (string, error) {
+// CreateToken -- Create user token carrying the user's role, permissions and the organization it is scoped to
+func CreateToken(userID uint64, role model.Role, orgID uint64) (string, error) {
 	var err error
 	atClaims := jwt.MapClaims{}
 	atClaims["authorized"] = true
 	atClaims["user_id"] = userID
+	atClaims["role"] = role.Name
+	atClaims["permissions"] = role.Permissions
+	atClaims["org_id"] = orgID
 	at := jwt.NewWithClaims(jwt.SigningMethodHS256, atClaims)
 	token, err := at.SignedString([]byte(os.Getenv("SECRET_KEY")))
 	if err != nil {
@@ -28,8 +28,13 @@ func GetConfig() *models.Config {
 			Username: os.Getenv("POSTGRES_USER"),
 			Password: os.Getenv("POSTGRES_PASSWORD"),
 		},
-		TimeZone:  "Asia/Jakarta",
-		SecretKey: os.Getenv("SECRET_KEY"),
+		RateLimit: models.RateLimitConfig{
+			Backend:   os.Getenv("RATE_LIMIT_BACKEND"),
+			RedisAddr: os.Getenv("REDIS_ADDR"),
+		},
+		TimeZone:   "Asia/Jakarta",
+		SecretKey:  os.Getenv("SECRET_KEY"),
+		AdminEmail: os.Getenv("ADMIN_EMAIL"),
 	}
 
 	return config
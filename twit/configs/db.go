@@ -2,6 +2,7 @@ package configs
 
 import (
 	"fmt"
+	"log"
 	"twit/models"
 
 	"gorm.io/driver/postgres"
@@ -33,7 +34,70 @@ func InitializeDB(config *models.Config) *gorm.DB {
 	return db
 }
 
-func AutoMigrate(db *gorm.DB) {
+func AutoMigrate(db *gorm.DB, config *models.Config) {
 	// Register model and schema
-	db.AutoMigrate(&models.User{})
+	db.AutoMigrate(&models.Permission{}, &models.Role{}, &models.User{}, &models.RecoveryCode{}, &models.Tweet{}, &models.Follow{})
+
+	SeedRoles(db)
+	BootstrapAdmin(db, config.AdminEmail)
+}
+
+func SeedRoles(db *gorm.DB) {
+	// Make sure the default roles and their permissions exist
+	for roleName, permissionNames := range models.DefaultRolePermissions {
+		var permissions []models.Permission
+		for _, permissionName := range permissionNames {
+			permission := models.Permission{Name: permissionName}
+			db.Where(models.Permission{Name: permissionName}).FirstOrCreate(&permission)
+			permissions = append(permissions, permission)
+		}
+
+		role := models.Role{Name: roleName}
+		db.Where(models.Role{Name: roleName}).FirstOrCreate(&role)
+		db.Model(&role).Association("Permissions").Replace(permissions)
+	}
+
+	// Users created before roles existed have no role and would be denied everything
+	var userRole models.Role
+	if err := db.Where(models.Role{Name: models.RoleUser}).First(&userRole).Error; err != nil {
+		log.Println("Error loading the user role", err)
+		return
+	}
+	result := db.Unscoped().Model(&models.User{}).Where("role_id IS NULL OR role_id = 0").Update("role_id", userRole.ID)
+	if result.Error != nil {
+		log.Println("Error backfilling user roles", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Println("Assigned the user role to existing users:", result.RowsAffected)
+	}
+}
+
+// BootstrapAdmin promotes the user registered with email to admin while there is no admin yet,
+// so the first admin can assign roles to everyone else. It does nothing when email is empty or an
+// admin exists, so demoting that user later lasts across restarts.
+func BootstrapAdmin(db *gorm.DB, email string) {
+	if email == "" {
+		return
+	}
+
+	var adminRole models.Role
+	if err := db.Where(models.Role{Name: models.RoleAdmin}).First(&adminRole).Error; err != nil {
+		log.Println("Error loading the admin role", err)
+		return
+	}
+
+	var admins int64
+	if err := db.Model(&models.User{}).Where("role_id = ?", adminRole.ID).Count(&admins).Error; err != nil {
+		log.Println("Error counting admins", err)
+		return
+	}
+	if admins > 0 {
+		return
+	}
+
+	result := db.Model(&models.User{}).Where("email = ?", email).Update("role_id", adminRole.ID)
+	if result.Error != nil {
+		log.Println("Error bootstrapping the admin", result.Error)
+	} else if result.RowsAffected == 0 {
+		log.Println("ADMIN_EMAIL is set but no such user is registered yet, restart after registering it")
+	}
 }
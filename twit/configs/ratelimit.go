@@ -0,0 +1,18 @@
+package configs
+
+import (
+	"ratelimit"
+	"twit/models"
+)
+
+func InitializeLoginLimiter(config *models.Config) *ratelimit.Limiter {
+	// Use Redis when several instances have to share the counters
+	var backend ratelimit.Backend
+	if config.RateLimit.Backend == "redis" {
+		backend = ratelimit.NewRedisBackend(InitializeRedis(config))
+	} else {
+		backend = ratelimit.NewMemoryBackend()
+	}
+
+	return ratelimit.NewLimiter(backend, ratelimit.DefaultConfig(), nil)
+}
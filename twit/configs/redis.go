@@ -0,0 +1,22 @@
+package configs
+
+import (
+	"context"
+	"twit/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+func InitializeRedis(config *models.Config) *redis.Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     config.RateLimit.RedisAddr,
+		Password: "", // no password set
+		DB:       0,  // use default DB
+	})
+
+	if err := rdb.Ping(context.Background()).Err(); err != nil {
+		panic("Failed to connect to redis")
+	}
+
+	return rdb
+}
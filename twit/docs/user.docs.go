@@ -87,3 +87,47 @@ type getProfileResponseWrapper struct {
 	// in:body
 	Body responses.LoginUserResponse
 }
+
+// ================================ LIST USERS ================================
+
+// swagger:route GET /admin/users UserManagement getUsers
+// Get Users. Requires the user:read:any permission.
+// responses:
+//   200: getUsersResponse
+//   403: getUsersResponse
+// Security:
+//   Bearer: []
+
+// This text will appear as description of your response body.
+// swagger:response getUsersResponse
+type getUsersResponseWrapper struct {
+	// in:body
+	Body responses.Response
+}
+
+// ================================ ASSIGN ROLE ================================
+
+// swagger:route PUT /admin/users/{id}/role UserManagement assignRole
+// Assign Role. Requires the user:role:assign permission.
+// responses:
+//   200: assignRoleResponse
+//   403: assignRoleResponse
+// Security:
+//   Bearer: []
+
+// This text will appear as description of your response body.
+// swagger:response assignRoleResponse
+type assignRoleResponseWrapper struct {
+	// in:body
+	Body responses.Response
+}
+
+// swagger:parameters assignRole
+type assignRoleParamsWrapper struct {
+	// in:path
+	ID uint `json:"id"`
+
+	// This text will appear as description of your request body.
+	// in:body
+	Body requests.AssignRoleRequest
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"twit/models/responses"
+	"twit/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type followHandler struct {
+	followUsecase usecases.FollowUsecase
+}
+
+type FollowHandler interface {
+	Follow(ctx *gin.Context)
+	Unfollow(ctx *gin.Context)
+}
+
+func InitFollowHandler(followUsecase usecases.FollowUsecase) FollowHandler {
+	return &followHandler{
+		followUsecase,
+	}
+}
+
+func (followHandler *followHandler) Follow(ctx *gin.Context) {
+	followeeID, parseErr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if parseErr != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: "Invalid user id",
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	err := followHandler.followUsecase.Follow(ctx.MustGet("UserID").(uint), uint(followeeID))
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Success to follow user",
+			Data:    struct{}{},
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
+
+func (followHandler *followHandler) Unfollow(ctx *gin.Context) {
+	followeeID, parseErr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if parseErr != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: "Invalid user id",
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	err := followHandler.followUsecase.Unfollow(ctx.MustGet("UserID").(uint), uint(followeeID))
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Success to unfollow user",
+			Data:    struct{}{},
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
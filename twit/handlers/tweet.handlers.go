@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"twit/models/requests"
+	"twit/models/responses"
+	"twit/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type tweetHandler struct {
+	tweetUsecase usecases.TweetUsecase
+}
+
+type TweetHandler interface {
+	CreateTweet(ctx *gin.Context)
+	DeleteTweet(ctx *gin.Context)
+	GetHomeTimeline(ctx *gin.Context)
+}
+
+func InitTweetHandler(tweetUsecase usecases.TweetUsecase) TweetHandler {
+	return &tweetHandler{
+		tweetUsecase,
+	}
+}
+
+func (tweetHandler *tweetHandler) CreateTweet(ctx *gin.Context) {
+	var request requests.CreateTweetRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	tweet, err := tweetHandler.tweetUsecase.CreateTweet(ctx.MustGet("UserID").(uint), request.Text)
+	if err == nil {
+		ctx.JSON(http.StatusCreated, responses.Response{
+			Success: true,
+			Message: "Success to create tweet",
+			Data:    tweet,
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
+
+func (tweetHandler *tweetHandler) DeleteTweet(ctx *gin.Context) {
+	tweetID, parseErr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if parseErr != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: "Invalid tweet id",
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	err := tweetHandler.tweetUsecase.DeleteTweet(ctx.MustGet("UserID").(uint), uint(tweetID))
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Success to delete tweet",
+			Data:    struct{}{},
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
+
+func (tweetHandler *tweetHandler) GetHomeTimeline(ctx *gin.Context) {
+	limit, parseErr := strconv.Atoi(ctx.DefaultQuery("limit", "0"))
+	if parseErr != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: "Invalid limit",
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	timeline, err := tweetHandler.tweetUsecase.GetHomeTimeline(ctx.MustGet("UserID").(uint), ctx.Query("cursor"), limit)
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Success to get timeline",
+			Data:    timeline,
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"ratelimit"
+	"strconv"
+	"twit/models/requests"
+	"twit/models/responses"
+	"twit/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+type twoFactorHandler struct {
+	twoFactorUsecase usecases.TwoFactorUsecase
+}
+
+type TwoFactorHandler interface {
+	Enroll(ctx *gin.Context)
+	Activate(ctx *gin.Context)
+	Verify(ctx *gin.Context)
+}
+
+func InitTwoFactorHandler(twoFactorUsecase usecases.TwoFactorUsecase) TwoFactorHandler {
+	return &twoFactorHandler{
+		twoFactorUsecase,
+	}
+}
+
+func (twoFactorHandler *twoFactorHandler) Enroll(ctx *gin.Context) {
+	data, err := twoFactorHandler.twoFactorUsecase.Enroll(ctx.GetString("Email"))
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Scan the otpauth URI and verify a code to activate two-factor authentication",
+			Data:    data,
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
+
+func (twoFactorHandler *twoFactorHandler) Activate(ctx *gin.Context) {
+	var request requests.TwoFactorCodeRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	err := twoFactorHandler.twoFactorUsecase.Activate(ctx.GetString("Email"), request.Code)
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Success to activate two-factor authentication",
+			Data:    struct{}{},
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
+
+func (twoFactorHandler *twoFactorHandler) Verify(ctx *gin.Context) {
+	var request requests.TwoFactorVerifyRequest
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	response, err := twoFactorHandler.twoFactorUsecase.Verify(request.ChallengeToken, request.Code, ctx.ClientIP())
+	var limitedErr *ratelimit.LimitedError
+	if err != nil && errors.As(err.Err, &limitedErr) {
+		ctx.Header("Retry-After", strconv.FormatInt(ratelimit.RetryAfterSeconds(limitedErr.RetryAfter), 10))
+	}
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.LoginUserResponse{
+			Success: true,
+			Message: "Success to login",
+			Data:    response,
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.LoginUserResponse{
+			Success: false,
+			Message: err.Error(),
+			Data:    response,
+		})
+	}
+}
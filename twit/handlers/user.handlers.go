@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"ratelimit"
+	"strconv"
 	"twit/models"
+	"twit/models/requests"
 	"twit/models/responses"
 	"twit/usecases"
 
@@ -17,6 +21,8 @@ type UserHandler interface {
 	RegisterUser(ctx *gin.Context)
 	LoginUser(ctx *gin.Context)
 	UserProfile(ctx *gin.Context)
+	GetUsers(ctx *gin.Context)
+	AssignRole(ctx *gin.Context)
 }
 
 func InitUserHandler(userUsecase usecases.UserUsecase) UserHandler {
@@ -26,9 +32,9 @@ func InitUserHandler(userUsecase usecases.UserUsecase) UserHandler {
 }
 
 func (userHandler *userHandler) RegisterUser(ctx *gin.Context) {
-	var user models.User
+	var request requests.RegisterUserRequest
 
-	if err := ctx.ShouldBindJSON(&user); err != nil {
+	if err := ctx.ShouldBindJSON(&request); err != nil {
 		ctx.JSON(http.StatusBadRequest, responses.Response{
 			Success: false,
 			Message: err.Error(),
@@ -36,7 +42,11 @@ func (userHandler *userHandler) RegisterUser(ctx *gin.Context) {
 		})
 	}
 
-	err := userHandler.userUsecase.RegisterUser(user)
+	err := userHandler.userUsecase.RegisterUser(models.User{
+		Email:    request.Email,
+		Username: request.Username,
+		Password: request.Password,
+	})
 	if err == nil {
 		ctx.JSON(http.StatusOK, responses.Response{
 			Success: true,
@@ -53,13 +63,21 @@ func (userHandler *userHandler) RegisterUser(ctx *gin.Context) {
 }
 
 func (userHandler *userHandler) LoginUser(ctx *gin.Context) {
-	var user models.User
+	var request requests.LoginUserRequest
 
-	if err := ctx.ShouldBindJSON(&user); err != nil {
+	if err := ctx.ShouldBindJSON(&request); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 	}
 
-	response, err := userHandler.userUsecase.LoginUser(user)
+	user := models.User{
+		Email:    request.Email,
+		Password: request.Password,
+	}
+	response, err := userHandler.userUsecase.LoginUser(user, ctx.ClientIP())
+	var limitedErr *ratelimit.LimitedError
+	if err != nil && errors.As(err.Err, &limitedErr) {
+		ctx.Header("Retry-After", strconv.FormatInt(ratelimit.RetryAfterSeconds(limitedErr.RetryAfter), 10))
+	}
 	if err == nil {
 		ctx.JSON(http.StatusOK, responses.LoginUserResponse{
 			Success: true,
@@ -81,13 +99,68 @@ func (userHandler *userHandler) UserProfile(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, responses.Response{
 			Success: true,
 			Message: "Success to get user profile",
-			Data:    user,
+			Data:    responses.NewUserResponse(user),
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
+
+func (userHandler *userHandler) GetUsers(ctx *gin.Context) {
+	users, err := userHandler.userUsecase.GetUsers()
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Success to get users",
+			Data:    responses.NewUserResponses(users),
+		})
+	} else {
+		ctx.JSON(int(err.StatusCode), responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+	}
+}
+
+func (userHandler *userHandler) AssignRole(ctx *gin.Context) {
+	var request requests.AssignRoleRequest
+
+	userID, parseErr := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if parseErr != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: "Invalid user id",
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, responses.Response{
+			Success: false,
+			Message: err.Error(),
+			Data:    struct{}{},
+		})
+		return
+	}
+
+	err := userHandler.userUsecase.AssignRole(uint(userID), request.Role)
+	if err == nil {
+		ctx.JSON(http.StatusOK, responses.Response{
+			Success: true,
+			Message: "Success to assign role",
+			Data:    struct{}{},
 		})
 	} else {
 		ctx.JSON(int(err.StatusCode), responses.Response{
 			Success: false,
 			Message: err.Error(),
-			Data:    user,
+			Data:    struct{}{},
 		})
 	}
 }
@@ -21,6 +21,8 @@ func AuthenticateUser() gin.HandlerFunc {
 		} else {
 			ctx.Set("UserID", user.ID)
 			ctx.Set("Email", user.Email)
+			ctx.Set("Role", user.Role.Name)
+			ctx.Set("Permissions", user.Role.PermissionNames())
 			ctx.Next()
 		}
 	}
@@ -0,0 +1,24 @@
+package middlewares
+
+import (
+	"net/http"
+	"twit/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		permissions := ctx.GetStringSlice("Permissions")
+
+		if !models.HasPermission(permissions, permission) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"success": false,
+				"message": "You don't have permission to access this resource",
+				"data":    nil,
+			})
+		} else {
+			ctx.Next()
+		}
+	}
+}
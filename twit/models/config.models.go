@@ -1,9 +1,11 @@
 package models
 
 type Config struct {
-	Database  DatabaseConfig
-	TimeZone  string
-	SecretKey string
+	Database   DatabaseConfig
+	RateLimit  RateLimitConfig
+	TimeZone   string
+	SecretKey  string
+	AdminEmail string
 }
 
 type DatabaseConfig struct {
@@ -13,3 +15,8 @@ type DatabaseConfig struct {
 	Username string
 	Password string
 }
+
+type RateLimitConfig struct {
+	Backend   string
+	RedisAddr string
+}
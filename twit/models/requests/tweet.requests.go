@@ -0,0 +1,5 @@
+package requests
+
+type CreateTweetRequest struct {
+	Text string `json:"text"`
+}
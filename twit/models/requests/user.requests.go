@@ -10,3 +10,16 @@ type LoginUserRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+type AssignRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge-token"`
+	Code           string `json:"code"`
+}
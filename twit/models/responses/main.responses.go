@@ -1,7 +1,5 @@
 package responses
 
-import "twit/models"
-
 type Response struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
@@ -9,8 +7,17 @@ type Response struct {
 }
 
 type LoginData struct {
-	AccessToken string      `json:"access-token"`
-	User        models.User `json:"user"`
+	AccessToken string       `json:"access-token"`
+	User        UserResponse `json:"user"`
+	// When two-factor authentication is on, only ChallengeToken is set until POST /auth/2fa/verify succeeds
+	TwoFactorRequired bool   `json:"two-factor-required"`
+	ChallengeToken    string `json:"challenge-token,omitempty"`
+}
+
+type TwoFactorEnrollData struct {
+	Secret        string   `json:"secret"`
+	OTPAuthURI    string   `json:"otpauth-uri"`
+	RecoveryCodes []string `json:"recovery-codes"`
 }
 
 type LoginUserResponse struct {
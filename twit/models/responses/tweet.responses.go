@@ -0,0 +1,45 @@
+package responses
+
+import (
+	"time"
+	"twit/models"
+)
+
+type TweetAuthor struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type TweetResponse struct {
+	ID        uint        `json:"id"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Text      string      `json:"text"`
+	Author    TweetAuthor `json:"author"`
+}
+
+type TimelineData struct {
+	Tweets []TweetResponse `json:"tweets"`
+	// Pass as the cursor query parameter to get the next page, empty on the last page
+	NextCursor string `json:"next-cursor"`
+}
+
+func NewTweetResponse(tweet models.Tweet) TweetResponse {
+	return TweetResponse{
+		ID:        tweet.ID,
+		CreatedAt: tweet.CreatedAt,
+		Text:      tweet.Text,
+		Author: TweetAuthor{
+			ID:       tweet.User.ID,
+			Username: tweet.User.Username,
+		},
+	}
+}
+
+func NewTweetResponses(tweets []models.Tweet) []TweetResponse {
+	result := make([]TweetResponse, 0, len(tweets))
+	for _, tweet := range tweets {
+		result = append(result, NewTweetResponse(tweet))
+	}
+
+	return result
+}
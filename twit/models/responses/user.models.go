@@ -1 +1,37 @@
 package responses
+
+import (
+	"time"
+	"twit/models"
+)
+
+// UserResponse is the public representation of models.User.
+// Build it with NewUserResponse so sensitive fields never reach the client.
+type UserResponse struct {
+	ID        uint      `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Email     string    `json:"email"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+}
+
+func NewUserResponse(user models.User) UserResponse {
+	return UserResponse{
+		ID:        user.ID,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		Email:     user.Email,
+		Username:  user.Username,
+		Role:      user.Role.Name,
+	}
+}
+
+func NewUserResponses(users []models.User) []UserResponse {
+	result := make([]UserResponse, 0, len(users))
+	for _, user := range users {
+		result = append(result, NewUserResponse(user))
+	}
+
+	return result
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+const (
+	PermissionProfileReadOwn = "profile:read:own"
+	PermissionUserReadAny    = "user:read:any"
+	PermissionUserRoleAssign = "user:role:assign"
+	PermissionTweetReadAny   = "tweet:read:any"
+	PermissionTweetWriteOwn  = "tweet:write:own"
+)
+
+type Permission struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Name      string    `gorm:"uniqueIndex;not null" json:"name"`
+}
+
+type Role struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	CreatedAt   time.Time    `json:"createdAt"`
+	UpdatedAt   time.Time    `json:"updatedAt"`
+	Name        string       `gorm:"uniqueIndex;not null" json:"name"`
+	Permissions []Permission `gorm:"many2many:role_permissions;" json:"permissions"`
+}
+
+// DefaultRolePermissions is the set of roles and permissions seeded on startup
+var DefaultRolePermissions = map[string][]string{
+	RoleUser: {
+		PermissionProfileReadOwn,
+		PermissionTweetReadAny,
+		PermissionTweetWriteOwn,
+	},
+	RoleAdmin: {
+		PermissionProfileReadOwn,
+		PermissionUserReadAny,
+		PermissionUserRoleAssign,
+		PermissionTweetReadAny,
+		PermissionTweetWriteOwn,
+	},
+}
+
+func (role *Role) PermissionNames() []string {
+	names := make([]string, 0, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		names = append(names, permission.Name)
+	}
+
+	return names
+}
+
+func HasPermission(permissions []string, permission string) bool {
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const TweetMaxLength = 280
+
+type Tweet struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `gorm:"index" json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	UserID    uint           `gorm:"index;not null" json:"userId"`
+	User      User           `json:"user"`
+	Text      string         `gorm:"not null" json:"text"`
+}
+
+type Follow struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	FollowerID uint      `gorm:"uniqueIndex:idx_follower_followee;not null" json:"followerId"`
+	Follower   User      `json:"-"`
+	FolloweeID uint      `gorm:"uniqueIndex:idx_follower_followee;index;not null" json:"followeeId"`
+	Followee   User      `json:"-"`
+}
@@ -7,11 +7,24 @@ import (
 )
 
 type User struct {
-	ID        uint           `gorm:"primaryKey" json:"id"`
-	CreatedAt time.Time      `json:"createdAt"`
-	UpdatedAt time.Time      `json:"updatedAt"`
-	DeletedAt gorm.DeletedAt `gorm:"index"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	Username  string         `json:"username" json:"username"`
-	Password  string         `gorm:"not null" json:"password"`
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+	DeletedAt         gorm.DeletedAt `gorm:"index"`
+	Email             string         `gorm:"uniqueIndex;not null" json:"email"`
+	Username          string         `json:"username" json:"username"`
+	Password          string         `gorm:"not null" json:"-"`
+	RoleID            uint           `json:"roleId"`
+	Role              Role           `json:"role"`
+	TwoFactorSecret   string         `json:"-"`
+	TwoFactorEnabled  bool           `gorm:"not null;default:false" json:"twoFactorEnabled"`
+	TwoFactorLastStep int64          `json:"-"`
+}
+
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UserID    uint       `gorm:"index;not null" json:"userId"`
+	CodeHash  string     `gorm:"not null" json:"-"`
+	UsedAt    *time.Time `json:"usedAt"`
 }
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"twit/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type followRepository struct {
+	db *gorm.DB
+}
+
+type FollowRepository interface {
+	Follow(followerID, followeeID uint) *models.RequestError
+	Unfollow(followerID, followeeID uint) *models.RequestError
+}
+
+func InitFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepository{
+		db,
+	}
+}
+
+func (followRepository *followRepository) Follow(followerID, followeeID uint) *models.RequestError {
+	// Following someone twice is a no-op
+	follow := models.Follow{FollowerID: followerID, FolloweeID: followeeID}
+	result := followRepository.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Select("FollowerID", "FolloweeID").Create(&follow)
+	if result.Error != nil {
+		return internalServerError()
+	}
+
+	return nil
+}
+
+func (followRepository *followRepository) Unfollow(followerID, followeeID uint) *models.RequestError {
+	result := followRepository.db.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&models.Follow{})
+	if result.Error != nil {
+		return internalServerError()
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"errors"
+	"net/http"
+	"twit/models"
+	"twit/utils"
+
+	"gorm.io/gorm"
+)
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+type RoleRepository interface {
+	GetRoleByName(name string) (models.Role, *models.RequestError)
+}
+
+func InitRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{
+		db,
+	}
+}
+
+func (roleRepository *roleRepository) GetRoleByName(name string) (models.Role, *models.RequestError) {
+	var role models.Role
+	result := roleRepository.db.Preload("Permissions").First(&role, "name = ?", name)
+	if result.Error != nil {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        errors.New("No role found"),
+		}
+		utils.Logging(err)
+		return models.Role{}, err
+	}
+
+	return role, nil
+}
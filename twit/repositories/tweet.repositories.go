@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"errors"
+	"net/http"
+	"twit/models"
+	"twit/utils"
+
+	"gorm.io/gorm"
+)
+
+type tweetRepository struct {
+	db *gorm.DB
+}
+
+type TweetRepository interface {
+	CreateTweet(tweet *models.Tweet) *models.RequestError
+	GetTweet(tweetID uint) (models.Tweet, *models.RequestError)
+	DeleteTweet(tweetID uint) *models.RequestError
+	GetHomeTimeline(userID uint, beforeID uint, limit int) ([]models.Tweet, *models.RequestError)
+}
+
+func InitTweetRepository(db *gorm.DB) TweetRepository {
+	return &tweetRepository{
+		db,
+	}
+}
+
+func (tweetRepository *tweetRepository) CreateTweet(tweet *models.Tweet) *models.RequestError {
+	result := tweetRepository.db.Select("UserID", "Text").Create(tweet)
+	if result.Error != nil {
+		return internalServerError()
+	}
+
+	return nil
+}
+
+func (tweetRepository *tweetRepository) GetTweet(tweetID uint) (models.Tweet, *models.RequestError) {
+	var tweet models.Tweet
+	result := tweetRepository.db.Preload("User").First(&tweet, tweetID)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		err := &models.RequestError{
+			StatusCode: http.StatusNotFound,
+			Err:        errors.New("No tweet found"),
+		}
+		utils.Logging(err)
+		return models.Tweet{}, err
+	}
+	if result.Error != nil {
+		return models.Tweet{}, internalServerError()
+	}
+
+	return tweet, nil
+}
+
+func (tweetRepository *tweetRepository) DeleteTweet(tweetID uint) *models.RequestError {
+	result := tweetRepository.db.Delete(&models.Tweet{}, tweetID)
+	if result.Error != nil {
+		return internalServerError()
+	}
+
+	return nil
+}
+
+// GetHomeTimeline returns the newest tweets of the user and everyone they follow.
+// Pages are keyed by tweet id, beforeID 0 starts from the newest tweet.
+func (tweetRepository *tweetRepository) GetHomeTimeline(userID uint, beforeID uint, limit int) ([]models.Tweet, *models.RequestError) {
+	var tweets []models.Tweet
+	followees := tweetRepository.db.Model(&models.Follow{}).Select("followee_id").Where("follower_id = ?", userID)
+	query := tweetRepository.db.Preload("User").
+		Where("user_id = ? OR user_id IN (?)", userID, followees)
+	if beforeID != 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	result := query.Order("id DESC").Limit(limit).Find(&tweets)
+	if result.Error != nil {
+		return nil, internalServerError()
+	}
+
+	return tweets, nil
+}
@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"errors"
+	"net/http"
+	"time"
+	"twit/models"
+	"twit/utils"
+
+	"gorm.io/gorm"
+)
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+type TwoFactorRepository interface {
+	StartEnrollment(userID uint, secret string, recoveryCodeHashes []string) *models.RequestError
+	Enable(userID uint, step int64) *models.RequestError
+	AcceptStep(userID uint, step int64) (bool, *models.RequestError)
+	UseRecoveryCode(userID uint, codeHash string) (bool, *models.RequestError)
+}
+
+func InitTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{
+		db,
+	}
+}
+
+func internalServerError() *models.RequestError {
+	err := &models.RequestError{
+		StatusCode: http.StatusInternalServerError,
+		Err:        errors.New("INTERNAL SERVER ERROR"),
+	}
+	utils.Logging(err)
+	return err
+}
+
+func (twoFactorRepository *twoFactorRepository) StartEnrollment(userID uint, secret string, recoveryCodeHashes []string) *models.RequestError {
+	err := twoFactorRepository.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_secret":    secret,
+			"two_factor_enabled":   false,
+			"two_factor_last_step": 0,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		recoveryCodes := make([]models.RecoveryCode, 0, len(recoveryCodeHashes))
+		for _, codeHash := range recoveryCodeHashes {
+			recoveryCodes = append(recoveryCodes, models.RecoveryCode{UserID: userID, CodeHash: codeHash})
+		}
+		return tx.Create(&recoveryCodes).Error
+	})
+	if err != nil {
+		return internalServerError()
+	}
+
+	return nil
+}
+
+func (twoFactorRepository *twoFactorRepository) Enable(userID uint, step int64) *models.RequestError {
+	result := twoFactorRepository.db.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"two_factor_enabled":   true,
+		"two_factor_last_step": step,
+	})
+	if result.Error != nil {
+		return internalServerError()
+	}
+
+	return nil
+}
+
+// AcceptStep records the step of a verified code, it fails when that step or a later one was already used
+func (twoFactorRepository *twoFactorRepository) AcceptStep(userID uint, step int64) (bool, *models.RequestError) {
+	result := twoFactorRepository.db.Model(&models.User{}).
+		Where("id = ? AND two_factor_last_step < ?", userID, step).
+		Update("two_factor_last_step", step)
+	if result.Error != nil {
+		return false, internalServerError()
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (twoFactorRepository *twoFactorRepository) UseRecoveryCode(userID uint, codeHash string) (bool, *models.RequestError) {
+	result := twoFactorRepository.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, internalServerError()
+	}
+
+	return result.RowsAffected == 1, nil
+}
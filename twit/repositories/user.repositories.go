@@ -16,6 +16,9 @@ type userRepository struct {
 type UserRepository interface {
 	RegisterUser(user models.User) *models.RequestError
 	GetUserData(email string) (models.User, *models.RequestError)
+	GetUserByID(userID uint) (models.User, *models.RequestError)
+	GetUsers() ([]models.User, *models.RequestError)
+	AssignRole(userID uint, role models.Role) *models.RequestError
 }
 
 func InitUserRepository(db *gorm.DB) UserRepository {
@@ -25,7 +28,7 @@ func InitUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (userRepository *userRepository) RegisterUser(user models.User) *models.RequestError {
-	result := userRepository.db.Select("Email", "Username", "Password").Create(&user)
+	result := userRepository.db.Select("Email", "Username", "Password", "RoleID").Create(&user)
 	if result.Error != nil {
 		err := &models.RequestError{
 			StatusCode: http.StatusBadRequest,
@@ -40,7 +43,7 @@ func (userRepository *userRepository) RegisterUser(user models.User) *models.Req
 
 func (userRepository *userRepository) GetUserData(email string) (models.User, *models.RequestError) {
 	var user models.User
-	result := userRepository.db.First(&user, "email = ?", email)
+	result := userRepository.db.Preload("Role.Permissions").First(&user, "email = ?", email)
 	if result.Error != nil {
 		err := &models.RequestError{
 			StatusCode: http.StatusBadRequest,
@@ -52,3 +55,56 @@ func (userRepository *userRepository) GetUserData(email string) (models.User, *m
 
 	return user, nil
 }
+
+func (userRepository *userRepository) GetUserByID(userID uint) (models.User, *models.RequestError) {
+	var user models.User
+	result := userRepository.db.First(&user, userID)
+	if result.Error != nil {
+		err := &models.RequestError{
+			StatusCode: http.StatusNotFound,
+			Err:        errors.New("No user found"),
+		}
+		utils.Logging(err)
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func (userRepository *userRepository) GetUsers() ([]models.User, *models.RequestError) {
+	var users []models.User
+	result := userRepository.db.Preload("Role").Order("id").Find(&users)
+	if result.Error != nil {
+		err := &models.RequestError{
+			StatusCode: http.StatusInternalServerError,
+			Err:        errors.New("INTERNAL SERVER ERROR"),
+		}
+		utils.Logging(err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (userRepository *userRepository) AssignRole(userID uint, role models.Role) *models.RequestError {
+	result := userRepository.db.Model(&models.User{}).Where("id = ?", userID).Update("role_id", role.ID)
+	if result.Error != nil {
+		err := &models.RequestError{
+			StatusCode: http.StatusInternalServerError,
+			Err:        errors.New("INTERNAL SERVER ERROR"),
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		err := &models.RequestError{
+			StatusCode: http.StatusNotFound,
+			Err:        errors.New("No user found"),
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"twit/configs"
 	"twit/handlers"
 	"twit/middlewares"
+	"twit/models"
 	"twit/repositories"
 	"twit/usecases"
 
@@ -22,7 +23,7 @@ func CORSMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
 		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -37,18 +38,27 @@ func SetupRepositories() Repositories {
 	// Configurations, database settings and auto migrations
 	configModel := configs.GetConfig()
 	db := configs.InitializeDB(configModel)
-	configs.AutoMigrate(db)
+	configs.AutoMigrate(db, configModel)
 
 	repositories := Repositories{
-		UserRepository: repositories.InitUserRepository(db),
+		UserRepository:      repositories.InitUserRepository(db),
+		RoleRepository:      repositories.InitRoleRepository(db),
+		TwoFactorRepository: repositories.InitTwoFactorRepository(db),
+		TweetRepository:     repositories.InitTweetRepository(db),
+		FollowRepository:    repositories.InitFollowRepository(db),
 	}
 
 	return repositories
 }
 
 func SetupUsecases(repositories Repositories) Usecases {
+	loginLimiter := configs.InitializeLoginLimiter(configs.GetConfig())
+
 	usecases := Usecases{
-		UserUsecase: usecases.InitUserUsecase(repositories.UserRepository),
+		UserUsecase:      usecases.InitUserUsecase(repositories.UserRepository, repositories.RoleRepository, loginLimiter),
+		TwoFactorUsecase: usecases.InitTwoFactorUsecase(repositories.UserRepository, repositories.TwoFactorRepository, loginLimiter),
+		TweetUsecase:     usecases.InitTweetUsecase(repositories.TweetRepository),
+		FollowUsecase:    usecases.InitFollowUsecase(repositories.FollowRepository, repositories.UserRepository),
 	}
 
 	return usecases
@@ -56,7 +66,10 @@ func SetupUsecases(repositories Repositories) Usecases {
 
 func SetupHandlers(usecases Usecases) Handlers {
 	handlers := Handlers{
-		UserHandler: handlers.InitUserHandler(usecases.UserUsecase),
+		UserHandler:      handlers.InitUserHandler(usecases.UserUsecase),
+		TwoFactorHandler: handlers.InitTwoFactorHandler(usecases.TwoFactorUsecase),
+		TweetHandler:     handlers.InitTweetHandler(usecases.TweetUsecase),
+		FollowHandler:    handlers.InitFollowHandler(usecases.FollowUsecase),
 	}
 
 	return handlers
@@ -76,11 +89,33 @@ func SetupServer() *gin.Engine {
 	// Router for user
 	router.POST("/auth/register", handlers.UserHandler.RegisterUser)
 	router.POST("/auth/login", handlers.UserHandler.LoginUser)
+	router.POST("/auth/2fa/verify", handlers.TwoFactorHandler.Verify)
 
 	authorized := router.Group("/user")
 	authorized.Use(middlewares.AuthenticateUser())
 	{
-		authorized.GET("/profile", handlers.UserHandler.UserProfile)
+		authorized.GET("/profile", middlewares.RequirePermission(models.PermissionProfileReadOwn), handlers.UserHandler.UserProfile)
+		authorized.POST("/2fa/enroll", handlers.TwoFactorHandler.Enroll)
+		authorized.POST("/2fa/activate", handlers.TwoFactorHandler.Activate)
+		authorized.GET("/timeline", middlewares.RequirePermission(models.PermissionTweetReadAny), handlers.TweetHandler.GetHomeTimeline)
+		authorized.POST("/follow/:id", middlewares.RequirePermission(models.PermissionTweetWriteOwn), handlers.FollowHandler.Follow)
+		authorized.DELETE("/follow/:id", middlewares.RequirePermission(models.PermissionTweetWriteOwn), handlers.FollowHandler.Unfollow)
+	}
+
+	// Router for tweets
+	tweets := router.Group("/tweets")
+	tweets.Use(middlewares.AuthenticateUser())
+	{
+		tweets.POST("", middlewares.RequirePermission(models.PermissionTweetWriteOwn), handlers.TweetHandler.CreateTweet)
+		tweets.DELETE("/:id", middlewares.RequirePermission(models.PermissionTweetWriteOwn), handlers.TweetHandler.DeleteTweet)
+	}
+
+	// Router for user management
+	admin := router.Group("/admin")
+	admin.Use(middlewares.AuthenticateUser())
+	{
+		admin.GET("/users", middlewares.RequirePermission(models.PermissionUserReadAny), handlers.UserHandler.GetUsers)
+		admin.PUT("/users/:id/role", middlewares.RequirePermission(models.PermissionUserRoleAssign), handlers.UserHandler.AssignRole)
 	}
 
 	return router
@@ -7,13 +7,23 @@ import (
 )
 
 type Repositories struct {
-	UserRepository repositories.UserRepository
+	UserRepository      repositories.UserRepository
+	RoleRepository      repositories.RoleRepository
+	TwoFactorRepository repositories.TwoFactorRepository
+	TweetRepository     repositories.TweetRepository
+	FollowRepository    repositories.FollowRepository
 }
 
 type Usecases struct {
-	UserUsecase usecases.UserUsecase
+	UserUsecase      usecases.UserUsecase
+	TwoFactorUsecase usecases.TwoFactorUsecase
+	TweetUsecase     usecases.TweetUsecase
+	FollowUsecase    usecases.FollowUsecase
 }
 
 type Handlers struct {
-	UserHandler handlers.UserHandler
+	UserHandler      handlers.UserHandler
+	TwoFactorHandler handlers.TwoFactorHandler
+	TweetHandler     handlers.TweetHandler
+	FollowHandler    handlers.FollowHandler
 }
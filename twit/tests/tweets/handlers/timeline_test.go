@@ -0,0 +1,172 @@
+package tweetHandlerTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"twit/configs"
+	"twit/models"
+	"twit/models/responses"
+	"twit/servers"
+	"twit/utils"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerTimelineSuite struct {
+	suite.Suite
+	testingServer   *httptest.Server
+	cleanupExecutor utils.TruncateTableExecutor
+}
+
+func (suite *HandlerTimelineSuite) SetupTest() {
+	router := servers.SetupServer()
+	testingServer := httptest.NewServer(router)
+
+	suite.testingServer = testingServer
+
+	cleanupExecutor := utils.InitTruncateTableExecutor(configs.DB)
+	suite.cleanupExecutor = cleanupExecutor
+}
+
+func (suite *HandlerTimelineSuite) TearDownTest() {
+	defer suite.testingServer.Close()
+	defer suite.cleanupExecutor.TruncateTable([]string{"users", "tweets", "follows"})
+}
+
+func (suite *HandlerTimelineSuite) request(method, path, token string, payload interface{}, body interface{}) int {
+	requestBody, err := json.Marshal(payload)
+	suite.NoError(err, "There should be no errors when create requestBody")
+
+	request, err := http.NewRequest(method, fmt.Sprintf("%s%s", suite.testingServer.URL, path), bytes.NewBuffer(requestBody))
+	suite.NoError(err)
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	suite.NoError(err)
+
+	defer response.Body.Close()
+	json.NewDecoder(response.Body).Decode(body)
+	return response.StatusCode
+}
+
+// registerAndLogin returns the access token and the id of a new user
+func (suite *HandlerTimelineSuite) registerAndLogin(username string) (string, uint) {
+	statusCode := suite.request(http.MethodPost, "/auth/register", "", map[string]string{
+		"username": username,
+		"email":    username + "@example.com",
+		"password": "password",
+	}, &responses.Response{})
+	suite.Equal(http.StatusOK, statusCode)
+
+	body := responses.LoginUserResponse{}
+	statusCode = suite.request(http.MethodPost, "/auth/login", "", map[string]string{
+		"email":    username + "@example.com",
+		"password": "password",
+	}, &body)
+	suite.Equal(http.StatusOK, statusCode)
+
+	return body.Data.AccessToken, body.Data.User.ID
+}
+
+func (suite *HandlerTimelineSuite) tweet(token, text string) responses.TweetResponse {
+	body := struct {
+		Data responses.TweetResponse `json:"data"`
+	}{}
+	statusCode := suite.request(http.MethodPost, "/tweets", token, map[string]string{"text": text}, &body)
+	suite.Equal(http.StatusCreated, statusCode)
+	return body.Data
+}
+
+func (suite *HandlerTimelineSuite) timeline(token, query string) responses.TimelineData {
+	body := struct {
+		Data responses.TimelineData `json:"data"`
+	}{}
+	statusCode := suite.request(http.MethodGet, "/user/timeline"+query, token, nil, &body)
+	suite.Equal(http.StatusOK, statusCode)
+	return body.Data
+}
+
+func (suite *HandlerTimelineSuite) TestCreateTweetValidation() {
+	token, _ := suite.registerAndLogin("alice")
+
+	statusCode := suite.request(http.MethodPost, "/tweets", token, map[string]string{"text": "   "}, &responses.Response{})
+	suite.Equal(http.StatusBadRequest, statusCode)
+
+	tooLong := make([]byte, models.TweetMaxLength+1)
+	for i := range tooLong {
+		tooLong[i] = 'a'
+	}
+	statusCode = suite.request(http.MethodPost, "/tweets", token, map[string]string{"text": string(tooLong)}, &responses.Response{})
+	suite.Equal(http.StatusBadRequest, statusCode)
+}
+
+func (suite *HandlerTimelineSuite) TestHomeTimelineMergesFollowedUsers() {
+	aliceToken, _ := suite.registerAndLogin("alice")
+	bobToken, bobID := suite.registerAndLogin("bob")
+	carolToken, _ := suite.registerAndLogin("carol")
+
+	suite.tweet(bobToken, "bob 1")
+	suite.tweet(carolToken, "carol 1")
+	suite.tweet(aliceToken, "alice 1")
+	suite.tweet(bobToken, "bob 2")
+
+	// Before following anyone only alice's tweets are visible
+	timeline := suite.timeline(aliceToken, "")
+	suite.Len(timeline.Tweets, 1)
+
+	statusCode := suite.request(http.MethodPost, fmt.Sprintf("/user/follow/%d", bobID), aliceToken, nil, &responses.Response{})
+	suite.Equal(http.StatusOK, statusCode)
+
+	timeline = suite.timeline(aliceToken, "?limit=2")
+	suite.Len(timeline.Tweets, 2)
+	suite.Equal("bob 2", timeline.Tweets[0].Text)
+	suite.Equal("bob", timeline.Tweets[0].Author.Username)
+	suite.Equal("alice 1", timeline.Tweets[1].Text)
+	suite.NotEmpty(timeline.NextCursor)
+
+	timeline = suite.timeline(aliceToken, "?limit=2&cursor="+timeline.NextCursor)
+	suite.Len(timeline.Tweets, 1)
+	suite.Equal("bob 1", timeline.Tweets[0].Text)
+	suite.Empty(timeline.NextCursor)
+
+	statusCode = suite.request(http.MethodDelete, fmt.Sprintf("/user/follow/%d", bobID), aliceToken, nil, &responses.Response{})
+	suite.Equal(http.StatusOK, statusCode)
+	suite.Len(suite.timeline(aliceToken, "").Tweets, 1)
+}
+
+func (suite *HandlerTimelineSuite) TestCannotFollowYourself() {
+	token, userID := suite.registerAndLogin("alice")
+
+	statusCode := suite.request(http.MethodPost, fmt.Sprintf("/user/follow/%d", userID), token, nil, &responses.Response{})
+	suite.Equal(http.StatusBadRequest, statusCode)
+
+	statusCode = suite.request(http.MethodPost, "/user/follow/999999", token, nil, &responses.Response{})
+	suite.Equal(http.StatusNotFound, statusCode)
+}
+
+func (suite *HandlerTimelineSuite) TestDeleteTweet() {
+	aliceToken, _ := suite.registerAndLogin("alice")
+	bobToken, _ := suite.registerAndLogin("bob")
+	tweet := suite.tweet(aliceToken, "alice 1")
+
+	statusCode := suite.request(http.MethodDelete, fmt.Sprintf("/tweets/%d", tweet.ID), bobToken, nil, &responses.Response{})
+	suite.Equal(http.StatusForbidden, statusCode)
+
+	statusCode = suite.request(http.MethodDelete, fmt.Sprintf("/tweets/%d", tweet.ID), aliceToken, nil, &responses.Response{})
+	suite.Equal(http.StatusOK, statusCode)
+	suite.Empty(suite.timeline(aliceToken, "").Tweets)
+
+	statusCode = suite.request(http.MethodDelete, fmt.Sprintf("/tweets/%d", tweet.ID), aliceToken, nil, &responses.Response{})
+	suite.Equal(http.StatusNotFound, statusCode)
+}
+
+func TestHandlerTimelineSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTimelineSuite))
+}
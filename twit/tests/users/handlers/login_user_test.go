@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -71,6 +72,51 @@ func (suite *HandlerLoginUserSuite) TestLoginUserPositive() {
 	suite.Equal(true, body.Success)
 }
 
+func (suite *HandlerLoginUserSuite) TestLoginUserHidesPassword() {
+	requestBody, err := json.Marshal(map[string]string{
+		"username": "username",
+		"email":    "email",
+		"password": "password",
+	})
+	suite.NoError(err, "There should be no errors when create requestBody")
+
+	response, err := http.Post(fmt.Sprintf("%s/auth/register", suite.testingServer.URL), "application/json", bytes.NewBuffer(requestBody))
+	suite.Equal(http.StatusOK, response.StatusCode)
+	response.Body.Close()
+
+	requestBody, err = json.Marshal(map[string]string{
+		"email":    "email",
+		"password": "password",
+	})
+	suite.NoError(err, "There should be no errors when create requestBody")
+
+	response, err = http.Post(fmt.Sprintf("%s/auth/login", suite.testingServer.URL), "application/json", bytes.NewBuffer(requestBody))
+	suite.Equal(http.StatusOK, response.StatusCode)
+
+	defer response.Body.Close()
+	rawBody, err := ioutil.ReadAll(response.Body)
+	suite.NoError(err)
+	suite.NotContains(string(rawBody), "password")
+	suite.NotContains(string(rawBody), "$2a$")
+
+	body := responses.LoginUserResponse{}
+	json.Unmarshal(rawBody, &body)
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/user/profile", suite.testingServer.URL), nil)
+	suite.NoError(err)
+	request.Header.Set("Authorization", body.Data.AccessToken)
+
+	response, err = http.DefaultClient.Do(request)
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, response.StatusCode)
+
+	defer response.Body.Close()
+	rawBody, err = ioutil.ReadAll(response.Body)
+	suite.NoError(err)
+	suite.NotContains(string(rawBody), "password")
+	suite.NotContains(string(rawBody), "$2a$")
+}
+
 func TestHandlerLoginUserSuite(t *testing.T) {
 	suite.Run(t, new(HandlerLoginUserSuite))
 }
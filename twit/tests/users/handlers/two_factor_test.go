@@ -0,0 +1,151 @@
+package userHandlerTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"twit/configs"
+	"twit/models/responses"
+	"twit/servers"
+	"twit/utils"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerTwoFactorSuite struct {
+	suite.Suite
+	testingServer   *httptest.Server
+	cleanupExecutor utils.TruncateTableExecutor
+}
+
+func (suite *HandlerTwoFactorSuite) SetupTest() {
+	router := servers.SetupServer()
+	testingServer := httptest.NewServer(router)
+
+	suite.testingServer = testingServer
+
+	cleanupExecutor := utils.InitTruncateTableExecutor(configs.DB)
+	suite.cleanupExecutor = cleanupExecutor
+}
+
+func (suite *HandlerTwoFactorSuite) TearDownTest() {
+	defer suite.testingServer.Close()
+	defer suite.cleanupExecutor.TruncateTable([]string{"users", "recovery_codes"})
+}
+
+func (suite *HandlerTwoFactorSuite) post(path, token string, payload interface{}, body interface{}) int {
+	requestBody, err := json.Marshal(payload)
+	suite.NoError(err, "There should be no errors when create requestBody")
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s%s", suite.testingServer.URL, path), bytes.NewBuffer(requestBody))
+	suite.NoError(err)
+	request.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		request.Header.Set("Authorization", token)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	suite.NoError(err)
+
+	defer response.Body.Close()
+	json.NewDecoder(response.Body).Decode(body)
+	return response.StatusCode
+}
+
+func (suite *HandlerTwoFactorSuite) login() responses.LoginUserResponse {
+	body := responses.LoginUserResponse{}
+	statusCode := suite.post("/auth/login", "", map[string]string{
+		"email":    "email",
+		"password": "password",
+	}, &body)
+	suite.Equal(http.StatusOK, statusCode)
+	return body
+}
+
+func (suite *HandlerTwoFactorSuite) enroll() (string, []string) {
+	statusCode := suite.post("/auth/register", "", map[string]string{
+		"username": "username",
+		"email":    "email",
+		"password": "password",
+	}, &responses.Response{})
+	suite.Equal(http.StatusOK, statusCode)
+	accessToken := suite.login().Data.AccessToken
+
+	enrollBody := struct {
+		Data responses.TwoFactorEnrollData `json:"data"`
+	}{}
+	statusCode = suite.post("/user/2fa/enroll", accessToken, nil, &enrollBody)
+	suite.Equal(http.StatusOK, statusCode)
+	suite.Contains(enrollBody.Data.OTPAuthURI, "otpauth://totp/")
+	suite.Len(enrollBody.Data.RecoveryCodes, 10)
+
+	code, err := utils.GenerateTOTP(enrollBody.Data.Secret, time.Now().Add(-utils.TOTPPeriod))
+	suite.NoError(err)
+	statusCode = suite.post("/user/2fa/activate", accessToken, map[string]string{"code": code}, &responses.Response{})
+	suite.Equal(http.StatusOK, statusCode)
+
+	return enrollBody.Data.Secret, enrollBody.Data.RecoveryCodes
+}
+
+func (suite *HandlerTwoFactorSuite) TestTwoStepLogin() {
+	secret, _ := suite.enroll()
+
+	loginBody := suite.login()
+	suite.True(loginBody.Data.TwoFactorRequired)
+	suite.Empty(loginBody.Data.AccessToken)
+	suite.NotEmpty(loginBody.Data.ChallengeToken)
+
+	// The challenge token is not an access token
+	statusCode := suite.post("/user/2fa/enroll", loginBody.Data.ChallengeToken, nil, &responses.Response{})
+	suite.Equal(http.StatusUnauthorized, statusCode)
+
+	code, err := utils.GenerateTOTP(secret, time.Now())
+	suite.NoError(err)
+	verifyBody := responses.LoginUserResponse{}
+	statusCode = suite.post("/auth/2fa/verify", "", map[string]string{
+		"challenge-token": loginBody.Data.ChallengeToken,
+		"code":            code,
+	}, &verifyBody)
+	suite.Equal(http.StatusOK, statusCode)
+	suite.NotEmpty(verifyBody.Data.AccessToken)
+
+	// The same code can't be replayed
+	statusCode = suite.post("/auth/2fa/verify", "", map[string]string{
+		"challenge-token": loginBody.Data.ChallengeToken,
+		"code":            code,
+	}, &responses.LoginUserResponse{})
+	suite.Equal(http.StatusUnauthorized, statusCode)
+}
+
+func (suite *HandlerTwoFactorSuite) TestRecoveryCodeIsSingleUse() {
+	_, recoveryCodes := suite.enroll()
+	challengeToken := suite.login().Data.ChallengeToken
+
+	statusCode := suite.post("/auth/2fa/verify", "", map[string]string{
+		"challenge-token": challengeToken,
+		"code":            recoveryCodes[0],
+	}, &responses.LoginUserResponse{})
+	suite.Equal(http.StatusOK, statusCode)
+
+	statusCode = suite.post("/auth/2fa/verify", "", map[string]string{
+		"challenge-token": challengeToken,
+		"code":            recoveryCodes[0],
+	}, &responses.LoginUserResponse{})
+	suite.Equal(http.StatusUnauthorized, statusCode)
+}
+
+func (suite *HandlerTwoFactorSuite) TestVerifyInvalidChallengeToken() {
+	statusCode := suite.post("/auth/2fa/verify", "", map[string]string{
+		"challenge-token": "invalid",
+		"code":            "123456",
+	}, &responses.LoginUserResponse{})
+	suite.Equal(http.StatusUnauthorized, statusCode)
+}
+
+func TestHandlerTwoFactorSuite(t *testing.T) {
+	suite.Run(t, new(HandlerTwoFactorSuite))
+}
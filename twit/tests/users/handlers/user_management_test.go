@@ -0,0 +1,140 @@
+package userHandlerTests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"twit/configs"
+	"twit/models"
+	"twit/models/responses"
+	"twit/servers"
+	"twit/utils"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerUserManagementSuite struct {
+	suite.Suite
+	testingServer   *httptest.Server
+	cleanupExecutor utils.TruncateTableExecutor
+}
+
+func (suite *HandlerUserManagementSuite) SetupTest() {
+	router := servers.SetupServer()
+	testingServer := httptest.NewServer(router)
+
+	suite.testingServer = testingServer
+
+	cleanupExecutor := utils.InitTruncateTableExecutor(configs.DB)
+	suite.cleanupExecutor = cleanupExecutor
+}
+
+func (suite *HandlerUserManagementSuite) TearDownTest() {
+	defer suite.testingServer.Close()
+	defer suite.cleanupExecutor.TruncateTable([]string{"users"})
+}
+
+func (suite *HandlerUserManagementSuite) registerAndLogin(email string) string {
+	requestBody, err := json.Marshal(map[string]string{
+		"username": email,
+		"email":    email,
+		"password": "password",
+	})
+	suite.NoError(err, "There should be no errors when create requestBody")
+
+	response, err := http.Post(fmt.Sprintf("%s/auth/register", suite.testingServer.URL), "application/json", bytes.NewBuffer(requestBody))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, response.StatusCode)
+	response.Body.Close()
+
+	return suite.login(email)
+}
+
+func (suite *HandlerUserManagementSuite) login(email string) string {
+	requestBody, err := json.Marshal(map[string]string{
+		"email":    email,
+		"password": "password",
+	})
+	suite.NoError(err, "There should be no errors when create requestBody")
+
+	response, err := http.Post(fmt.Sprintf("%s/auth/login", suite.testingServer.URL), "application/json", bytes.NewBuffer(requestBody))
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, response.StatusCode)
+
+	defer response.Body.Close()
+	body := responses.LoginUserResponse{}
+	json.NewDecoder(response.Body).Decode(&body)
+	return body.Data.AccessToken
+}
+
+func (suite *HandlerUserManagementSuite) request(method, path, token string, payload interface{}) *http.Response {
+	requestBody, err := json.Marshal(payload)
+	suite.NoError(err, "There should be no errors when create requestBody")
+
+	request, err := http.NewRequest(method, fmt.Sprintf("%s%s", suite.testingServer.URL, path), bytes.NewBuffer(requestBody))
+	suite.NoError(err)
+	request.Header.Set("Authorization", token)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := http.DefaultClient.Do(request)
+	suite.NoError(err)
+	return response
+}
+
+func (suite *HandlerUserManagementSuite) TestRegularUserCanReadProfile() {
+	token := suite.registerAndLogin("user@example.com")
+
+	response := suite.request(http.MethodGet, "/user/profile", token, nil)
+	defer response.Body.Close()
+	suite.Equal(http.StatusOK, response.StatusCode)
+}
+
+func (suite *HandlerUserManagementSuite) TestRegularUserCannotListUsers() {
+	token := suite.registerAndLogin("user@example.com")
+
+	response := suite.request(http.MethodGet, "/admin/users", token, nil)
+	defer response.Body.Close()
+	suite.Equal(http.StatusForbidden, response.StatusCode)
+}
+
+func (suite *HandlerUserManagementSuite) TestRegularUserCannotAssignRole() {
+	token := suite.registerAndLogin("user@example.com")
+
+	response := suite.request(http.MethodPut, "/admin/users/1/role", token, map[string]string{"role": models.RoleAdmin})
+	defer response.Body.Close()
+	suite.Equal(http.StatusForbidden, response.StatusCode)
+}
+
+func (suite *HandlerUserManagementSuite) TestAdminAssignRole() {
+	suite.registerAndLogin("admin@example.com")
+	var adminRole models.Role
+	configs.DB.First(&adminRole, "name = ?", models.RoleAdmin)
+	configs.DB.Model(&models.User{}).Where("email = ?", "admin@example.com").Update("role_id", adminRole.ID)
+
+	// Log in again so the token carries the admin permissions
+	adminToken := suite.login("admin@example.com")
+	suite.registerAndLogin("user@example.com")
+
+	var user models.User
+	configs.DB.First(&user, "email = ?", "user@example.com")
+
+	response := suite.request(http.MethodPut, fmt.Sprintf("/admin/users/%d/role", user.ID), adminToken, map[string]string{"role": models.RoleAdmin})
+	defer response.Body.Close()
+	suite.Equal(http.StatusOK, response.StatusCode)
+
+	body := responses.Response{}
+	json.NewDecoder(response.Body).Decode(&body)
+	suite.Equal("Success to assign role", body.Message)
+	suite.Equal(true, body.Success)
+
+	response = suite.request(http.MethodGet, "/admin/users", adminToken, nil)
+	defer response.Body.Close()
+	suite.Equal(http.StatusOK, response.StatusCode)
+}
+
+func TestHandlerUserManagementSuite(t *testing.T) {
+	suite.Run(t, new(HandlerUserManagementSuite))
+}
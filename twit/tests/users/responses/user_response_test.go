@@ -0,0 +1,63 @@
+package userResponseTests
+
+import (
+	"encoding/json"
+	"testing"
+	"twit/models"
+	"twit/models/responses"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type UserResponseSuite struct {
+	suite.Suite
+	user models.User
+}
+
+func (suite *UserResponseSuite) SetupTest() {
+	suite.user = models.User{
+		ID:       1,
+		Email:    "email",
+		Username: "username",
+		Password: "$2a$14$ajq8Q7fbtFRQvXpdCq7Jcuy.Rx1h/L4J60Otx.gyNLbAYctGMJ9tK",
+		Role:     models.Role{Name: models.RoleUser},
+	}
+}
+
+func (suite *UserResponseSuite) assertNoPassword(value interface{}) {
+	body, err := json.Marshal(value)
+	suite.NoError(err)
+	suite.NotContains(string(body), "password")
+	suite.NotContains(string(body), suite.user.Password)
+}
+
+func (suite *UserResponseSuite) TestUserModelHidesPassword() {
+	suite.assertNoPassword(suite.user)
+}
+
+func (suite *UserResponseSuite) TestUserResponse() {
+	response := responses.NewUserResponse(suite.user)
+	suite.Equal(uint(1), response.ID)
+	suite.Equal("email", response.Email)
+	suite.Equal("username", response.Username)
+	suite.Equal(models.RoleUser, response.Role)
+	suite.assertNoPassword(response)
+}
+
+func (suite *UserResponseSuite) TestUserResponses() {
+	suite.assertNoPassword(responses.NewUserResponses([]models.User{suite.user, suite.user}))
+}
+
+func (suite *UserResponseSuite) TestLoginUserResponse() {
+	suite.assertNoPassword(responses.LoginUserResponse{
+		Success: true,
+		Data: responses.LoginData{
+			AccessToken: "token",
+			User:        responses.NewUserResponse(suite.user),
+		},
+	})
+}
+
+func TestUserResponseSuite(t *testing.T) {
+	suite.Run(t, new(UserResponseSuite))
+}
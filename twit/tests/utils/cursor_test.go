@@ -0,0 +1,32 @@
+package utilsTests
+
+import (
+	"testing"
+	"twit/utils"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CursorSuite struct {
+	suite.Suite
+}
+
+func (suite *CursorSuite) TestRoundTrip() {
+	for _, id := range []uint{1, 42, 18446744073709551615} {
+		decoded, err := utils.DecodeCursor(utils.EncodeCursor(id))
+		suite.NoError(err)
+		suite.Equal(id, decoded)
+	}
+}
+
+func (suite *CursorSuite) TestInvalidCursor() {
+	_, err := utils.DecodeCursor("not a cursor")
+	suite.Error(err)
+
+	_, err = utils.DecodeCursor(utils.EncodeCursor(1) + "!")
+	suite.Error(err)
+}
+
+func TestCursorSuite(t *testing.T) {
+	suite.Run(t, new(CursorSuite))
+}
@@ -0,0 +1,110 @@
+package utilsTests
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+	"twit/utils"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TOTPSuite struct {
+	suite.Suite
+}
+
+// Test vectors from RFC 4226 Appendix D
+func (suite *TOTPSuite) TestHOTPVectors() {
+	secret := []byte("12345678901234567890")
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+
+	for counter, code := range expected {
+		suite.Equal(code, utils.HOTP(secret, int64(counter), 6), "counter %d", counter)
+	}
+}
+
+// SHA1 test vectors from RFC 6238 Appendix B
+func (suite *TOTPSuite) TestTOTPVectors() {
+	secret := []byte("12345678901234567890")
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, cs := range cases {
+		step := utils.TOTPStep(time.Unix(cs.unix, 0))
+		suite.Equal(cs.code, utils.HOTP(secret, step, 8), "time %d", cs.unix)
+	}
+}
+
+func (suite *TOTPSuite) TestValidateTOTP() {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	code, err := utils.GenerateTOTP(secret, now)
+	suite.NoError(err)
+	suite.Equal("050471", code)
+
+	step, valid := utils.ValidateTOTP(secret, code, now)
+	suite.True(valid)
+	suite.Equal(utils.TOTPStep(now), step)
+
+	// One step of clock drift is accepted
+	_, valid = utils.ValidateTOTP(secret, code, now.Add(utils.TOTPPeriod))
+	suite.True(valid)
+	_, valid = utils.ValidateTOTP(secret, code, now.Add(-utils.TOTPPeriod))
+	suite.True(valid)
+
+	_, valid = utils.ValidateTOTP(secret, code, now.Add(3*utils.TOTPPeriod))
+	suite.False(valid)
+	_, valid = utils.ValidateTOTP(secret, "000000", now)
+	suite.False(valid)
+	_, valid = utils.ValidateTOTP(secret, "", now)
+	suite.False(valid)
+}
+
+func (suite *TOTPSuite) TestGenerateTOTPSecret() {
+	secret, err := utils.GenerateTOTPSecret()
+	suite.NoError(err)
+	suite.Len(secret, 32)
+
+	other, err := utils.GenerateTOTPSecret()
+	suite.NoError(err)
+	suite.NotEqual(secret, other)
+}
+
+func (suite *TOTPSuite) TestTOTPURI() {
+	uri, err := url.Parse(utils.TOTPURI("Twit", "user@example.com", "JBSWY3DPEHPK3PXP"))
+	suite.NoError(err)
+	suite.Equal("otpauth", uri.Scheme)
+	suite.Equal("totp", uri.Host)
+	suite.Equal("/Twit:user@example.com", uri.Path)
+	suite.Equal("JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	suite.Equal("Twit", uri.Query().Get("issuer"))
+	suite.Equal("6", uri.Query().Get("digits"))
+	suite.Equal("30", uri.Query().Get("period"))
+}
+
+func (suite *TOTPSuite) TestRecoveryCodes() {
+	codes, err := utils.GenerateRecoveryCodes(10)
+	suite.NoError(err)
+	suite.Len(codes, 10)
+	for _, code := range codes {
+		suite.Regexp(`^[0-9a-f]{5}-[0-9a-f]{5}$`, code)
+	}
+
+	suite.Equal(utils.HashRecoveryCode(codes[0]), utils.HashRecoveryCode(" "+codes[0]+" "))
+	suite.NotEqual(utils.HashRecoveryCode(codes[0]), utils.HashRecoveryCode(codes[1]))
+}
+
+func TestTOTPSuite(t *testing.T) {
+	suite.Run(t, new(TOTPSuite))
+}
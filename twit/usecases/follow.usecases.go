@@ -0,0 +1,47 @@
+package usecases
+
+import (
+	"errors"
+	"net/http"
+	"twit/models"
+	"twit/repositories"
+	"twit/utils"
+)
+
+type followUsecase struct {
+	followRepository repositories.FollowRepository
+	userRepository   repositories.UserRepository
+}
+
+type FollowUsecase interface {
+	Follow(followerID, followeeID uint) *models.RequestError
+	Unfollow(followerID, followeeID uint) *models.RequestError
+}
+
+func InitFollowUsecase(followRepository repositories.FollowRepository, userRepository repositories.UserRepository) FollowUsecase {
+	return &followUsecase{
+		followRepository,
+		userRepository,
+	}
+}
+
+func (followUsecase *followUsecase) Follow(followerID, followeeID uint) *models.RequestError {
+	if followerID == followeeID {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        errors.New("You can't follow yourself"),
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	if _, err := followUsecase.userRepository.GetUserByID(followeeID); err != nil {
+		return err
+	}
+
+	return followUsecase.followRepository.Follow(followerID, followeeID)
+}
+
+func (followUsecase *followUsecase) Unfollow(followerID, followeeID uint) *models.RequestError {
+	return followUsecase.followRepository.Unfollow(followerID, followeeID)
+}
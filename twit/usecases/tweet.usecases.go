@@ -0,0 +1,121 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"twit/models"
+	"twit/models/responses"
+	"twit/repositories"
+	"twit/utils"
+	"unicode/utf8"
+)
+
+const (
+	timelineDefaultLimit = 20
+	timelineMaxLimit     = 100
+)
+
+type tweetUsecase struct {
+	tweetRepository repositories.TweetRepository
+}
+
+type TweetUsecase interface {
+	CreateTweet(userID uint, text string) (responses.TweetResponse, *models.RequestError)
+	DeleteTweet(userID, tweetID uint) *models.RequestError
+	GetHomeTimeline(userID uint, cursor string, limit int) (responses.TimelineData, *models.RequestError)
+}
+
+func InitTweetUsecase(tweetRepository repositories.TweetRepository) TweetUsecase {
+	return &tweetUsecase{
+		tweetRepository,
+	}
+}
+
+func (tweetUsecase *tweetUsecase) CreateTweet(userID uint, text string) (responses.TweetResponse, *models.RequestError) {
+	var result responses.TweetResponse
+
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > models.TweetMaxLength {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        fmt.Errorf("Tweet must be between 1 and %d characters", models.TweetMaxLength),
+		}
+		utils.Logging(err)
+		return result, err
+	}
+
+	tweet := models.Tweet{
+		UserID: userID,
+		Text:   text,
+	}
+	err := tweetUsecase.tweetRepository.CreateTweet(&tweet)
+	if err != nil {
+		return result, err
+	}
+
+	tweet, err = tweetUsecase.tweetRepository.GetTweet(tweet.ID)
+	if err != nil {
+		return result, err
+	}
+
+	return responses.NewTweetResponse(tweet), nil
+}
+
+func (tweetUsecase *tweetUsecase) DeleteTweet(userID, tweetID uint) *models.RequestError {
+	tweet, err := tweetUsecase.tweetRepository.GetTweet(tweetID)
+	if err != nil {
+		return err
+	}
+
+	if tweet.UserID != userID {
+		err := &models.RequestError{
+			StatusCode: http.StatusForbidden,
+			Err:        errors.New("You can only delete your own tweets"),
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	return tweetUsecase.tweetRepository.DeleteTweet(tweetID)
+}
+
+func (tweetUsecase *tweetUsecase) GetHomeTimeline(userID uint, cursor string, limit int) (responses.TimelineData, *models.RequestError) {
+	var result responses.TimelineData
+
+	if limit <= 0 {
+		limit = timelineDefaultLimit
+	}
+	if limit > timelineMaxLimit {
+		limit = timelineMaxLimit
+	}
+
+	var beforeID uint
+	if cursor != "" {
+		var decodeErr error
+		beforeID, decodeErr = utils.DecodeCursor(cursor)
+		if decodeErr != nil {
+			err := &models.RequestError{
+				StatusCode: http.StatusBadRequest,
+				Err:        errors.New("Invalid cursor"),
+			}
+			utils.Logging(err)
+			return result, err
+		}
+	}
+
+	// Fetch one extra tweet to know whether there is a next page
+	tweets, err := tweetUsecase.tweetRepository.GetHomeTimeline(userID, beforeID, limit+1)
+	if err != nil {
+		return result, err
+	}
+
+	if len(tweets) > limit {
+		tweets = tweets[:limit]
+		result.NextCursor = utils.EncodeCursor(tweets[limit-1].ID)
+	}
+	result.Tweets = responses.NewTweetResponses(tweets)
+
+	return result, nil
+}
@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"ratelimit"
+	"time"
+	"twit/models"
+	"twit/models/responses"
+	"twit/repositories"
+	"twit/utils"
+)
+
+const (
+	twoFactorIssuer    = "Twit"
+	recoveryCodesCount = 10
+)
+
+type twoFactorUsecase struct {
+	userRepository      repositories.UserRepository
+	twoFactorRepository repositories.TwoFactorRepository
+	loginLimiter        *ratelimit.Limiter
+}
+
+type TwoFactorUsecase interface {
+	Enroll(email string) (responses.TwoFactorEnrollData, *models.RequestError)
+	Activate(email, code string) *models.RequestError
+	Verify(challengeToken, code, clientIP string) (responses.LoginData, *models.RequestError)
+}
+
+func InitTwoFactorUsecase(userRepository repositories.UserRepository, twoFactorRepository repositories.TwoFactorRepository, loginLimiter *ratelimit.Limiter) TwoFactorUsecase {
+	return &twoFactorUsecase{
+		userRepository,
+		twoFactorRepository,
+		loginLimiter,
+	}
+}
+
+func (twoFactorUsecase *twoFactorUsecase) Enroll(email string) (responses.TwoFactorEnrollData, *models.RequestError) {
+	var result responses.TwoFactorEnrollData
+
+	user, err := twoFactorUsecase.userRepository.GetUserData(email)
+	if err != nil {
+		return result, err
+	}
+
+	if user.TwoFactorEnabled {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        errors.New("Two-factor authentication is already enabled"),
+		}
+		utils.Logging(err)
+		return result, err
+	}
+
+	secret, secretErr := utils.GenerateTOTPSecret()
+	if secretErr != nil {
+		return result, internalServerError()
+	}
+
+	recoveryCodes, codesErr := utils.GenerateRecoveryCodes(recoveryCodesCount)
+	if codesErr != nil {
+		return result, internalServerError()
+	}
+
+	recoveryCodeHashes := make([]string, 0, len(recoveryCodes))
+	for _, code := range recoveryCodes {
+		recoveryCodeHashes = append(recoveryCodeHashes, utils.HashRecoveryCode(code))
+	}
+
+	err = twoFactorUsecase.twoFactorRepository.StartEnrollment(user.ID, secret, recoveryCodeHashes)
+	if err != nil {
+		return result, err
+	}
+
+	result = responses.TwoFactorEnrollData{
+		Secret:        secret,
+		OTPAuthURI:    utils.TOTPURI(twoFactorIssuer, user.Email, secret),
+		RecoveryCodes: recoveryCodes,
+	}
+	return result, nil
+}
+
+func (twoFactorUsecase *twoFactorUsecase) Activate(email, code string) *models.RequestError {
+	user, err := twoFactorUsecase.userRepository.GetUserData(email)
+	if err != nil {
+		return err
+	}
+
+	if user.TwoFactorEnabled || user.TwoFactorSecret == "" {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        errors.New("Enroll in two-factor authentication first"),
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	step, valid := utils.ValidateTOTP(user.TwoFactorSecret, code, time.Now())
+	if !valid {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        errors.New("Invalid two-factor code"),
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	return twoFactorUsecase.twoFactorRepository.Enable(user.ID, step)
+}
+
+func (twoFactorUsecase *twoFactorUsecase) Verify(challengeToken, code, clientIP string) (responses.LoginData, *models.RequestError) {
+	var result responses.LoginData
+	ctx := context.Background()
+
+	if challengeToken == "" || code == "" {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        errors.New("Please provide challenge token and code"),
+		}
+		return result, err
+	}
+
+	challengedUser, parseErr := utils.ParseChallengeToken(challengeToken)
+	if parseErr != nil {
+		err := &models.RequestError{
+			StatusCode: http.StatusUnauthorized,
+			Err:        errors.New("Invalid or expired challenge token"),
+		}
+		utils.Logging(err)
+		return result, err
+	}
+
+	if limitErr := twoFactorUsecase.loginLimiter.Allow(ctx, clientIP, challengedUser.Email); limitErr != nil {
+		return result, loginLimitError(limitErr)
+	}
+
+	user, err := twoFactorUsecase.userRepository.GetUserData(challengedUser.Email)
+	if err != nil {
+		return result, err
+	}
+
+	verified, err := twoFactorUsecase.verifyCode(user, code)
+	if err != nil {
+		return result, err
+	}
+
+	if !verified {
+		if limitErr := twoFactorUsecase.loginLimiter.Failure(ctx, clientIP, user.Email); limitErr != nil {
+			return result, loginLimitError(limitErr)
+		}
+		err := &models.RequestError{
+			StatusCode: http.StatusUnauthorized,
+			Err:        errors.New("Invalid two-factor code"),
+		}
+		utils.Logging(err)
+		return result, err
+	}
+
+	if limitErr := twoFactorUsecase.loginLimiter.Success(ctx, clientIP, user.Email); limitErr != nil {
+		return result, loginLimitError(limitErr)
+	}
+
+	tokenStr, err := utils.GenerateToken(user)
+	if err != nil {
+		return result, err
+	}
+
+	result = responses.LoginData{
+		AccessToken: tokenStr,
+		User:        responses.NewUserResponse(user),
+	}
+	return result, nil
+}
+
+// verifyCode accepts a TOTP code that wasn't used before, or an unused recovery code
+func (twoFactorUsecase *twoFactorUsecase) verifyCode(user models.User, code string) (bool, *models.RequestError) {
+	if !user.TwoFactorEnabled {
+		return false, nil
+	}
+
+	if step, valid := utils.ValidateTOTP(user.TwoFactorSecret, code, time.Now()); valid {
+		return twoFactorUsecase.twoFactorRepository.AcceptStep(user.ID, step)
+	}
+
+	return twoFactorUsecase.twoFactorRepository.UseRecoveryCode(user.ID, utils.HashRecoveryCode(code))
+}
+
+func internalServerError() *models.RequestError {
+	err := &models.RequestError{
+		StatusCode: http.StatusInternalServerError,
+		Err:        errors.New("INTERNAL SERVER ERROR"),
+	}
+	utils.Logging(err)
+	return err
+}
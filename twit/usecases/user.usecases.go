@@ -1,8 +1,10 @@
 package usecases
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"ratelimit"
 	"twit/models"
 	"twit/models/responses"
 	"twit/repositories"
@@ -11,17 +13,23 @@ import (
 
 type userUsecase struct {
 	userRepository repositories.UserRepository
+	roleRepository repositories.RoleRepository
+	loginLimiter   *ratelimit.Limiter
 }
 
 type UserUsecase interface {
 	RegisterUser(user models.User) *models.RequestError
-	LoginUser(userRequest models.User) (responses.LoginData, *models.RequestError)
+	LoginUser(userRequest models.User, clientIP string) (responses.LoginData, *models.RequestError)
 	UserProfile(email string) (models.User, *models.RequestError)
+	GetUsers() ([]models.User, *models.RequestError)
+	AssignRole(userID uint, roleName string) *models.RequestError
 }
 
-func InitUserUsecase(userRepository repositories.UserRepository) UserUsecase {
+func InitUserUsecase(userRepository repositories.UserRepository, roleRepository repositories.RoleRepository, loginLimiter *ratelimit.Limiter) UserUsecase {
 	return &userUsecase{
 		userRepository,
+		roleRepository,
+		loginLimiter,
 	}
 }
 
@@ -41,14 +49,21 @@ func (userUsecase *userUsecase) RegisterUser(user models.User) *models.RequestEr
 		return err
 	}
 
+	role, err := userUsecase.roleRepository.GetRoleByName(models.RoleUser)
+	if err != nil {
+		return err
+	}
+
 	user.Password = hashedPassword
+	user.RoleID = role.ID
 	err = userUsecase.userRepository.RegisterUser(user)
 
 	return err
 }
 
-func (userUsecase *userUsecase) LoginUser(userRequest models.User) (responses.LoginData, *models.RequestError) {
+func (userUsecase *userUsecase) LoginUser(userRequest models.User, clientIP string) (responses.LoginData, *models.RequestError) {
 	var result responses.LoginData
+	ctx := context.Background()
 
 	if userRequest.Email == "" || userRequest.Password == "" {
 		err := &models.RequestError{
@@ -57,12 +72,23 @@ func (userUsecase *userUsecase) LoginUser(userRequest models.User) (responses.Lo
 		}
 		return result, err
 	}
+
+	if limitErr := userUsecase.loginLimiter.Allow(ctx, clientIP, userRequest.Email); limitErr != nil {
+		return result, loginLimitError(limitErr)
+	}
+
 	user, err := userUsecase.userRepository.GetUserData(userRequest.Email)
 	if err != nil {
+		if limitErr := userUsecase.loginLimiter.Failure(ctx, clientIP, userRequest.Email); limitErr != nil {
+			return result, loginLimitError(limitErr)
+		}
 		return result, err
 	}
 
 	if verified := utils.CheckPasswordHash(userRequest.Password, user.Password); !verified {
+		if limitErr := userUsecase.loginLimiter.Failure(ctx, clientIP, userRequest.Email); limitErr != nil {
+			return result, loginLimitError(limitErr)
+		}
 		err := &models.RequestError{
 			StatusCode: http.StatusBadRequest,
 			Err:        errors.New("Wrong email or password"),
@@ -71,6 +97,24 @@ func (userUsecase *userUsecase) LoginUser(userRequest models.User) (responses.Lo
 		return result, err
 	}
 
+	if limitErr := userUsecase.loginLimiter.Success(ctx, clientIP, userRequest.Email); limitErr != nil {
+		return result, loginLimitError(limitErr)
+	}
+
+	// The access token is only issued by POST /auth/2fa/verify for users with two-factor authentication
+	if user.TwoFactorEnabled {
+		challengeToken, err := utils.GenerateChallengeToken(user)
+		if err != nil {
+			return result, err
+		}
+
+		result = responses.LoginData{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}
+		return result, nil
+	}
+
 	tokenStr, err := utils.GenerateToken(user)
 	if err != nil {
 		return result, err
@@ -78,7 +122,7 @@ func (userUsecase *userUsecase) LoginUser(userRequest models.User) (responses.Lo
 
 	loginData := responses.LoginData{
 		AccessToken: tokenStr,
-		User:        user,
+		User:        responses.NewUserResponse(user),
 	}
 	return loginData, nil
 }
@@ -92,3 +136,44 @@ func (userUsecase *userUsecase) UserProfile(email string) (models.User, *models.
 
 	return user, nil
 }
+
+func (userUsecase *userUsecase) GetUsers() ([]models.User, *models.RequestError) {
+	return userUsecase.userRepository.GetUsers()
+}
+
+func (userUsecase *userUsecase) AssignRole(userID uint, roleName string) *models.RequestError {
+	if roleName == "" {
+		err := &models.RequestError{
+			StatusCode: http.StatusBadRequest,
+			Err:        errors.New("Please provide role"),
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	role, err := userUsecase.roleRepository.GetRoleByName(roleName)
+	if err != nil {
+		return err
+	}
+
+	return userUsecase.userRepository.AssignRole(userID, role)
+}
+
+func loginLimitError(limitErr error) *models.RequestError {
+	var limitedErr *ratelimit.LimitedError
+	if errors.As(limitErr, &limitedErr) {
+		err := &models.RequestError{
+			StatusCode: http.StatusTooManyRequests,
+			Err:        limitedErr,
+		}
+		utils.Logging(err)
+		return err
+	}
+
+	err := &models.RequestError{
+		StatusCode: http.StatusInternalServerError,
+		Err:        errors.New("INTERNAL SERVER ERROR"),
+	}
+	utils.Logging(err)
+	return err
+}
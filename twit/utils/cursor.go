@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"encoding/base64"
+	"strconv"
+)
+
+// EncodeCursor hides the id a page ends at behind an opaque string
+func EncodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func DecodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	return uint(id), err
+}
@@ -20,6 +20,8 @@ func GenerateToken(user models.User) (string, *models.RequestError) {
 	claims := token.Claims.(jwt.MapClaims)
 	claims["id"] = user.ID
 	claims["email"] = user.Email
+	claims["role"] = user.Role.Name
+	claims["permissions"] = user.Role.PermissionNames()
 	claims["exp"] = time.Now().Add(time.Hour * 24).Unix()
 	tokenString, err := token.SignedString(SecretKey)
 	if err != nil {
@@ -43,11 +45,71 @@ func ParseToken(tokenStr string) (models.User, error) {
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
+		// Challenge tokens only prove the password, they can't be used as access tokens
+		if _, isChallenge := claims["purpose"]; isChallenge {
+			return models.User{}, errors.New("Not an access token")
+		}
 		idStr := fmt.Sprintf("%v", claims["id"])
 		id, _ := strconv.ParseInt(idStr, 10, 64)
 		email := claims["email"].(string)
-		return models.User{Email: email, ID: uint(id)}, nil
+		role, _ := claims["role"].(string)
+		return models.User{Email: email, ID: uint(id), Role: parseRoleClaims(role, claims["permissions"])}, nil
 	}
 
 	return models.User{}, err
 }
+
+const (
+	challengePurpose  = "2fa-challenge"
+	challengeLifetime = 5 * time.Minute
+)
+
+func GenerateChallengeToken(user models.User) (string, *models.RequestError) {
+	token := jwt.New(jwt.SigningMethodHS256)
+	claims := token.Claims.(jwt.MapClaims)
+	claims["id"] = user.ID
+	claims["email"] = user.Email
+	claims["purpose"] = challengePurpose
+	claims["exp"] = time.Now().Add(challengeLifetime).Unix()
+	tokenString, err := token.SignedString(SecretKey)
+	if err != nil {
+		err := &models.RequestError{
+			StatusCode: http.StatusInternalServerError,
+			Err:        errors.New("INTERNAL SERVER ERROR"),
+		}
+		Logging(err)
+		return "", err
+	}
+	return tokenString, nil
+}
+
+func ParseChallengeToken(tokenStr string) (models.User, error) {
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		return SecretKey, nil
+	})
+	if err != nil {
+		log.Println("Error to parse challenge token", err)
+		return models.User{}, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["purpose"] != challengePurpose {
+		return models.User{}, errors.New("Invalid challenge token")
+	}
+	idStr := fmt.Sprintf("%v", claims["id"])
+	id, _ := strconv.ParseInt(idStr, 10, 64)
+	email, _ := claims["email"].(string)
+	return models.User{Email: email, ID: uint(id)}, nil
+}
+
+func parseRoleClaims(name string, permissionsClaim interface{}) models.Role {
+	role := models.Role{Name: name}
+	permissions, _ := permissionsClaim.([]interface{})
+	for _, permission := range permissions {
+		if permissionName, ok := permission.(string); ok {
+			role.Permissions = append(role.Permissions, models.Permission{Name: permissionName})
+		}
+	}
+
+	return role
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	TOTPPeriod = 30 * time.Second
+	TOTPDigits = 6
+	// Accept codes from one step before and after the current one to allow clock drift
+	TOTPSkew = 1
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret returns a random 160 bit secret encoded in base32, as expected by authenticator apps
+func GenerateTOTPSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return base32NoPadding.EncodeToString(secret), nil
+}
+
+// TOTPStep is the RFC 6238 time counter T = (unix time - T0) / X with T0 = 0
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod/time.Second)
+}
+
+// HOTP computes the RFC 4226 one-time password of the counter using HMAC-SHA1
+func HOTP(secret []byte, counter int64, digits int) string {
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, uint64(counter))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	// Dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	binaryCode := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	code := binaryCode % uint32(math.Pow10(digits))
+
+	return fmt.Sprintf("%0*d", digits, code)
+}
+
+// GenerateTOTP computes the code of a base32 secret at the given time
+func GenerateTOTP(secret string, t time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return HOTP(key, TOTPStep(t), TOTPDigits), nil
+}
+
+// ValidateTOTP checks the code against the steps around t and returns the matching step.
+// Callers should reject steps that are not greater than the last accepted one to prevent replays.
+func ValidateTOTP(secret, code string, t time.Time) (int64, bool) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil || len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	current := TOTPStep(t)
+	for step := current - TOTPSkew; step <= current+TOTPSkew; step++ {
+		if hmac.Equal([]byte(HOTP(key, step, TOTPDigits)), []byte(code)) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// TOTPURI builds the otpauth:// URI that authenticator apps import, usually through a QR code
+func TOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprintf("%d", TOTPDigits))
+	query.Set("period", fmt.Sprintf("%d", int(TOTPPeriod/time.Second)))
+
+	return fmt.Sprintf("otpauth://totp/%s?%s", label, query.Encode())
+}
+
+// GenerateRecoveryCodes returns n random codes formatted as xxxxx-xxxxx
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(raw)
+		codes = append(codes, code[:5]+"-"+code[5:])
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode hashes a recovery code before it is stored or looked up
+func HashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	return base32NoPadding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}